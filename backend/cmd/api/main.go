@@ -3,12 +3,22 @@ package main
 import (
 	"log"
 	"os"
+	"path/filepath"
 
 	"cotacoes/config"
+	"cotacoes/internal/analytics/dividends"
+	"cotacoes/internal/analytics/irpf"
+	"cotacoes/internal/domain"
 	repository "cotacoes/internal/infra/cache"
+	"cotacoes/internal/infra/history"
 	"cotacoes/internal/infra/http/handler"
 	httpRouter "cotacoes/internal/infra/http/router"
+	"cotacoes/internal/infra/resilience"
+	"cotacoes/internal/infra/storage"
+	"cotacoes/internal/provider"
+	"cotacoes/internal/provider/bcb"
 	"cotacoes/internal/provider/brapi"
+	"cotacoes/internal/provider/indexes"
 	"cotacoes/internal/usecase"
 )
 
@@ -21,23 +31,157 @@ func main() {
 		log.Fatal("Token da BRAPI não definido. Configure BRAPI_TOKEN (ou BRAPI_API_KEY).")
 	}
 
-	// Provider
-	brapiProvider := brapi.NewBrapiProvider(apiKey)
+	// Providers (protegidos por circuit breaker)
+	breakerSettings := config.GetBreakerSettings()
+	brapiBreaker := resilience.NewCircuitBreaker("brapi", resilience.BreakerConfig{
+		FailureThreshold: breakerSettings.FailureThreshold,
+		OpenTimeout:      breakerSettings.OpenTimeout,
+		HalfOpenMaxCalls: breakerSettings.HalfOpenMaxCalls,
+		SuccessThreshold: breakerSettings.SuccessThreshold,
+	})
+	brapiProvider := resilience.NewBreakerProvider(
+		brapi.NewBrapiProvider(apiKey),
+		brapiBreaker,
+	)
 
 	// Cache
 	snapshotRepo := &repository.CotacoesFileCache{}
 
-	// Repositório
+	// Registro de providers: a ordem de fallback vem da configuração
+	registry := provider.NewRegistry()
+	if err := registry.Register("brapi", brapiProvider); err != nil {
+		log.Fatal(err)
+	}
+	if err := registry.Register("snapshot", repository.NewSnapshotStockProvider(snapshotRepo)); err != nil {
+		log.Fatal(err)
+	}
+
+	chainConfig := provider.ChainConfig{
+		DemoteAfter: config.GetProviderDemoteAfter(),
+		RetryAfter:  config.GetProviderRetryAfter(),
+	}
+	providerChain, err := registry.ProviderChain(config.GetProviderChain(), chainConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+	repositoryChain, err := registry.RepositoryChain(config.GetStockRepositoryChain(), chainConfig)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Repositórios
 	cotacoesRepo := repository.NewCotacoesBrapiRepo(
-		brapiProvider,
+		providerChain,
 		snapshotRepo,
 	)
+	retention := history.DefaultRetention()
+	for interval, keep := range config.GetHistoryRetention() {
+		retention[interval] = keep
+	}
+	historyStore := history.NewFileStore(config.GetHistoryDir(), retention)
+	go historyStore.RunCompaction(config.GetHistoryCompactionEvery(), nil)
+	stockRepo := repository.NewStockDetailCacheRepo(
+		history.NewStockRepo(repositoryChain, historyStore),
+	)
+	screenRepo := storage.NewScreenFileRepo(filepath.Join(config.GetDataDir(), "screens.json"))
+	customIndexRepo := storage.NewCustomIndexFileRepo(filepath.Join(config.GetDataDir(), "indexes.json"))
+	watchlistRepo := storage.NewWatchlistFileRepo(filepath.Join(config.GetDataDir(), "watchlists.json"))
+	portfolioRepo := storage.NewPortfolioFileRepo(filepath.Join(config.GetDataDir(), "portfolios.json"))
+	// Índices customizados respondem como símbolos (custom:<id>)
+	indexStockRepo := indexes.NewStockRepo(stockRepo, customIndexRepo)
 
 	// Use cases
-	getStockUC := usecase.NewGetStockUseCase(brapiProvider)
+	taxRules, err := dividends.ParseTaxRules(config.GetDividendTaxRules())
+	if err != nil {
+		log.Fatal(err)
+	}
+	getStockUC := usecase.NewGetStockUseCase(
+		indexStockRepo,
+		dividends.DefaultTaxRules().Merge(taxRules),
+	)
 	listCotacoesUC := usecase.NewListCotacoesUseCase(cotacoesRepo)
-	listSectorsUC := usecase.NewListSectorsUseCase(brapiProvider)
-	listTypesUC := usecase.NewListTypesUseCase(brapiProvider)
+	marketUniverse := usecase.NewMarketUniverse(cotacoesRepo, config.GetMarketUniverseTTL())
+	listSectorsUC := usecase.NewListSectorsUseCase(providerChain)
+	listTypesUC := usecase.NewListTypesUseCase(providerChain)
+	getIndicatorsUC := usecase.NewGetIndicatorsUseCase(getStockUC)
+	getStatsUC := usecase.NewGetStatsUseCase(
+		getStockUC,
+		config.GetBenchmarkSymbol(),
+		config.GetRiskFreeRate(),
+	)
+	correlationUC := usecase.NewCorrelationUseCase(getStockUC)
+	compareUC := usecase.NewCompareUseCase(
+		getStockUC,
+		config.GetBenchmarkSymbol(),
+		config.GetRiskFreeRate(),
+	)
+	getDividendSummaryUC := usecase.NewGetDividendSummaryUseCase(getStockUC)
+	dividendCalendarUC := usecase.NewDividendCalendarUseCase(
+		marketUniverse,
+		getStockUC,
+		config.GetDividendCalendarUniverse(),
+		config.GetDividendCalendarTTL(),
+	)
+	getRatiosUC := usecase.NewGetRatiosUseCase(getStockUC)
+	getValuationUC := usecase.NewGetValuationUseCase(getStockUC)
+	sectorSummaryUC := usecase.NewSectorSummaryUseCase(marketUniverse)
+	marketMoversUC := usecase.NewMarketMoversUseCase(
+		marketUniverse,
+		historyStore,
+		config.GetMarketMinVolume(),
+	)
+	marketHeatmapUC := usecase.NewMarketHeatmapUseCase(marketUniverse)
+	customIndexUC := usecase.NewCustomIndexUseCase(
+		customIndexRepo,
+		indexStockRepo,
+		getStockUC,
+	)
+	watchlistUC := usecase.NewWatchlistUseCase(
+		watchlistRepo,
+		marketUniverse,
+		getStockUC,
+	)
+	portfolioUC := usecase.NewPortfolioUseCase(
+		portfolioRepo,
+		marketUniverse,
+		getStockUC,
+	)
+	irRules := irpf.DefaultRules()
+	if path := config.GetIRRulesFile(); path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Fatal(err)
+		}
+		overrides, err := irpf.ParseRules(data)
+		if err != nil {
+			log.Fatal(err)
+		}
+		irRules = irRules.Merge(overrides)
+	}
+	portfolioImportUC := usecase.NewPortfolioImportUseCase(portfolioRepo)
+	taxReportUC := usecase.NewTaxReportUseCase(
+		portfolioRepo,
+		marketUniverse,
+		irRules,
+	)
+	portfolioPerformanceUC := usecase.NewPortfolioPerformanceUseCase(
+		portfolioRepo,
+		getStockUC,
+		bcb.NewBCBProvider(config.GetRatesTTL()),
+		config.GetBenchmarkSymbol(),
+		config.GetPerformanceBenchmarks(),
+	)
+	screenerUC := usecase.NewScreenerUseCase(
+		marketUniverse,
+		getStockUC,
+		screenRepo,
+		config.GetScreenerUniverse(),
+		config.GetScreenerTTL(),
+	)
+	getHealthUC := usecase.NewGetHealthUseCase(
+		[]domain.HealthReporter{brapiProvider},
+		[]domain.ChainReporter{providerChain, repositoryChain},
+	)
 
 	// Handlers
 	stockHandler := handler.NewStockHandler(
@@ -48,12 +192,59 @@ func main() {
 	metadataHandler := handler.NewMetadataHandler(
 		listSectorsUC,
 		listTypesUC,
+		sectorSummaryUC,
 	)
 
+	analyticsHandler := handler.NewAnalyticsHandler(
+		getIndicatorsUC,
+		getStatsUC,
+		correlationUC,
+		compareUC,
+	)
+
+	dividendHandler := handler.NewDividendHandler(
+		getDividendSummaryUC,
+		dividendCalendarUC,
+	)
+
+	fundamentalsHandler := handler.NewFundamentalsHandler(
+		getRatiosUC,
+		getValuationUC,
+	)
+
+	screenerHandler := handler.NewScreenerHandler(screenerUC)
+
+	marketHandler := handler.NewMarketHandler(
+		marketMoversUC,
+		marketHeatmapUC,
+	)
+
+	indexHandler := handler.NewIndexHandler(customIndexUC)
+
+	watchlistHandler := handler.NewWatchlistHandler(watchlistUC)
+
+	portfolioHandler := handler.NewPortfolioHandler(
+		portfolioUC,
+		taxReportUC,
+		portfolioImportUC,
+		portfolioPerformanceUC,
+	)
+
+	healthHandler := handler.NewHealthHandler(getHealthUC)
+
 	// Router
 	r := httpRouter.SetupRouter(httpRouter.Handlers{
-		StockHandler:    stockHandler,
-		MetadataHandler: metadataHandler,
+		StockHandler:        stockHandler,
+		MetadataHandler:     metadataHandler,
+		AnalyticsHandler:    analyticsHandler,
+		DividendHandler:     dividendHandler,
+		FundamentalsHandler: fundamentalsHandler,
+		ScreenerHandler:     screenerHandler,
+		MarketHandler:       marketHandler,
+		IndexHandler:        indexHandler,
+		WatchlistHandler:    watchlistHandler,
+		PortfolioHandler:    portfolioHandler,
+		HealthHandler:       healthHandler,
 	})
 
 	// Server
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"cotacoes/config"
+	"cotacoes/internal/domain"
+	"cotacoes/internal/infra/history"
+	"cotacoes/internal/provider/cotahist"
+)
+
+// Importa arquivos COTAHIST da B3 (diários, mensais ou anuais, .TXT ou .ZIP)
+// para o histórico local.
+//
+// Ex: go run ./cmd/cotahist -symbols PETR4,VALE3 COTAHIST_A2023.ZIP COTAHIST_M012024.TXT
+func main() {
+	config.LoadEnv()
+
+	dir := flag.String("dir", config.GetHistoryDir(), "diretório do histórico local")
+	markets := flag.String("markets", cotahist.MarketSpot, "códigos de mercado (TPMERC) aceitos, separados por vírgula")
+	bdi := flag.String("bdi", strings.Join([]string{cotahist.BDIStandardLot, cotahist.BDIRealEstate, cotahist.BDIETF}, ","), "códigos BDI aceitos, separados por vírgula (vazio = todos)")
+	symbols := flag.String("symbols", "", "tickers a importar, separados por vírgula (vazio = todos)")
+	fractional := flag.Bool("fractional", false, "inclui o mercado fracionário (tickers com sufixo F)")
+	flag.Parse()
+
+	files := flag.Args()
+	if len(files) == 0 {
+		fmt.Fprintln(os.Stderr, "uso: cotahist [flags] ARQUIVO...")
+		flag.PrintDefaults()
+		os.Exit(2)
+	}
+
+	filter := cotahist.Filter{
+		Markets:  toSet(*markets),
+		BDICodes: toSet(*bdi),
+		Symbols:  toSet(*symbols),
+	}
+	if *fractional {
+		filter.Markets[cotahist.MarketFractional] = true
+		if len(filter.BDICodes) > 0 {
+			filter.BDICodes[cotahist.BDIFractional] = true
+		}
+	}
+
+	store := history.NewFileStore(*dir, history.DefaultRetention())
+
+	for _, path := range files {
+		candles := make(map[string][]domain.HistoricalDataPrice)
+
+		summary, err := cotahist.ParseFile(path, filter, func(r cotahist.Record) error {
+			candles[r.Symbol] = append(candles[r.Symbol], r.Candle())
+			return nil
+		})
+		if err != nil {
+			log.Fatalf("❌ Erro ao ler %s: %v", path, err)
+		}
+
+		for _, e := range summary.Errors {
+			log.Printf("⚠️ %s: %v", path, e)
+		}
+
+		tickers := make([]string, 0, len(candles))
+		for symbol := range candles {
+			tickers = append(tickers, symbol)
+		}
+		sort.Strings(tickers)
+
+		for _, symbol := range tickers {
+			if err := store.Append(symbol, "1d", candles[symbol]); err != nil {
+				log.Fatalf("❌ Erro ao gravar %s: %v", symbol, err)
+			}
+		}
+
+		log.Printf("✅ %s (%s): %d registros importados, %d ignorados, %d erros, %d símbolos",
+			path, summary.Variant, summary.Accepted, summary.Skipped, len(summary.Errors), len(tickers))
+	}
+
+	// Arquivos podem vir fora de ordem cronológica; a compactação reordena
+	if err := store.CompactAll(); err != nil {
+		log.Fatalf("❌ Erro ao compactar o histórico: %v", err)
+	}
+}
+
+func toSet(list string) map[string]bool {
+	set := make(map[string]bool)
+	for _, item := range strings.Split(list, ",") {
+		if item = strings.ToUpper(strings.TrimSpace(item)); item != "" {
+			set[item] = true
+		}
+	}
+	return set
+}
@@ -1,20 +1,106 @@
 package main
 
 import (
-	"fmt"
 	"log"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
-	"github.com/joho/godotenv"
+	"cotacoes/config"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+	"cotacoes/internal/infra/history"
+	"cotacoes/internal/infra/resilience"
+	"cotacoes/internal/intraday"
+	"cotacoes/internal/provider/brapi"
 )
 
+// Worker que consulta a listagem periodicamente durante o pregão e monta
+// candles intraday (1m, 5m, 15m, 60m) no histórico local.
 func main() {
-	// Carrega o .env
-	if err := godotenv.Load(); err != nil {
-		log.Println("Nenhum .env encontrado")
+	config.LoadEnv()
+
+	apiKey := config.GetBrapiToken()
+	if apiKey == "" {
+		log.Fatal("Token da BRAPI não definido. Configure BRAPI_TOKEN (ou BRAPI_API_KEY).")
+	}
+
+	breakerSettings := config.GetBreakerSettings()
+	provider := resilience.NewBreakerProvider(
+		brapi.NewBrapiProvider(apiKey),
+		resilience.NewCircuitBreaker("brapi", resilience.BreakerConfig{
+			FailureThreshold: breakerSettings.FailureThreshold,
+			OpenTimeout:      breakerSettings.OpenTimeout,
+			HalfOpenMaxCalls: breakerSettings.HalfOpenMaxCalls,
+			SuccessThreshold: breakerSettings.SuccessThreshold,
+		}),
+	)
+
+	retention := history.DefaultRetention()
+	for interval, keep := range config.GetHistoryRetention() {
+		retention[interval] = keep
+	}
+	store := history.NewFileStore(config.GetHistoryDir(), retention)
+	builder := intraday.NewBuilder(calendar.Default, intraday.DefaultIntervals)
+
+	pollEvery := config.GetWorkerPollInterval()
+	ticker := time.NewTicker(pollEvery)
+	defer ticker.Stop()
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGINT, syscall.SIGTERM)
+
+	log.Printf("⏱️ Worker iniciado: consultando a listagem a cada %s", pollEvery)
+
+	for {
+		poll(provider, builder, store)
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			persist(store, builder.Flush(time.Now()))
+			log.Println("👋 Worker encerrado")
+			return
+		}
+	}
+}
+
+func poll(provider domain.StockProvider, builder *intraday.Builder, store domain.HistoryStore) {
+	now := time.Now()
+
+	// Fora do pregão só fecha candles pendentes, sem gastar cota da API
+	if !calendar.Default.InSession(now) {
+		persist(store, builder.Ingest(now, nil))
+		return
+	}
+
+	resp, err := provider.ListAllStocks("", "", "volume", "desc", 1, 2500)
+	if err != nil {
+		log.Printf("⚠️ Erro ao consultar a listagem: %v", err)
+		persist(store, builder.Ingest(now, nil))
+		return
+	}
+
+	persist(store, builder.Ingest(now, resp.Stocks))
+}
+
+func persist(store domain.HistoryStore, bars []intraday.Bar) {
+	type seriesKey struct{ symbol, interval string }
+
+	grouped := make(map[seriesKey][]domain.HistoricalDataPrice)
+	for _, bar := range bars {
+		key := seriesKey{bar.Symbol, bar.Interval}
+		grouped[key] = append(grouped[key], bar.Candle)
 	}
 
-	// Pega a chave
-	apiKey := os.Getenv("ALPHA_API_KEY")
-	fmt.Println("Minha API Key:", apiKey)
+	for key, candles := range grouped {
+		if err := store.Append(key.symbol, key.interval, candles); err != nil {
+			log.Printf("⚠️ Erro ao gravar candles de %s (%s): %v", key.symbol, key.interval, err)
+		}
+	}
+
+	if len(bars) > 0 {
+		log.Printf("🕯️ %d candles intraday gravados", len(bars))
+	}
 }
@@ -3,6 +3,9 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 )
@@ -24,3 +27,236 @@ func GetBrapiToken() string {
 	}
 	return os.Getenv("ALPHA_API_KEY")
 }
+
+// BreakerSettings agrupa os limites do circuit breaker dos providers
+type BreakerSettings struct {
+	FailureThreshold int
+	OpenTimeout      time.Duration
+	HalfOpenMaxCalls int
+	SuccessThreshold int
+}
+
+// GetBreakerSettings lê os limites do circuit breaker do ambiente.
+// Variáveis: BREAKER_FAILURE_THRESHOLD, BREAKER_OPEN_TIMEOUT (ex: "30s"),
+// BREAKER_HALF_OPEN_MAX_CALLS e BREAKER_SUCCESS_THRESHOLD.
+func GetBreakerSettings() BreakerSettings {
+	return BreakerSettings{
+		FailureThreshold: getEnvInt("BREAKER_FAILURE_THRESHOLD", 3),
+		OpenTimeout:      getEnvDuration("BREAKER_OPEN_TIMEOUT", 30*time.Second),
+		HalfOpenMaxCalls: getEnvInt("BREAKER_HALF_OPEN_MAX_CALLS", 1),
+		SuccessThreshold: getEnvInt("BREAKER_SUCCESS_THRESHOLD", 1),
+	}
+}
+
+func getEnvInt(key string, fallback int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		log.Printf("Valor inválido para %s (%q), usando %d", key, v, fallback)
+		return fallback
+	}
+	return n
+}
+
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		log.Printf("Valor inválido para %s (%q), usando %s", key, v, fallback)
+		return fallback
+	}
+	return d
+}
+
+// getEnvPositiveDuration é como getEnvDuration, mas rejeita valores <= 0
+// (usados em time.NewTicker, que entra em pânico com eles)
+func getEnvPositiveDuration(key string, fallback time.Duration) time.Duration {
+	d := getEnvDuration(key, fallback)
+	if d <= 0 {
+		log.Printf("Valor inválido para %s (%s), usando %s", key, d, fallback)
+		return fallback
+	}
+	return d
+}
+
+// GetProviderChain retorna a ordem de fallback dos StockProviders
+// (listagem). Ex: PROVIDER_CHAIN=brapi,snapshot
+func GetProviderChain() []string {
+	return getEnvList("PROVIDER_CHAIN", []string{"brapi", "snapshot"})
+}
+
+// GetStockRepositoryChain retorna a ordem de fallback dos StockRepositories
+// (detalhe da ação). Ex: STOCK_REPOSITORY_CHAIN=brapi
+func GetStockRepositoryChain() []string {
+	return getEnvList("STOCK_REPOSITORY_CHAIN", []string{"brapi"})
+}
+
+// GetProviderDemoteAfter retorna quantas falhas consecutivas rebaixam um
+// provider para o fim da cadeia
+func GetProviderDemoteAfter() int {
+	return getEnvInt("PROVIDER_DEMOTE_AFTER", 3)
+}
+
+// GetProviderRetryAfter retorna por quanto tempo um provider rebaixado fica
+// no fim da cadeia antes de voltar a ser tentado na posição original
+func GetProviderRetryAfter() time.Duration {
+	return getEnvDuration("PROVIDER_RETRY_AFTER", time.Minute)
+}
+
+func getEnvList(key string, fallback []string) []string {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+
+	list := make([]string, 0)
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	if len(list) == 0 {
+		return fallback
+	}
+	return list
+}
+
+// GetHistoryDir retorna o diretório do histórico local de candles
+func GetHistoryDir() string {
+	if v := os.Getenv("HISTORY_DIR"); v != "" {
+		return v
+	}
+	return "data/history"
+}
+
+// GetHistoryRetention lê overrides de retenção do histórico local, em dias
+// por intervalo. Ex: HISTORY_RETENTION=1m=30,5m=60,15m=180
+func GetHistoryRetention() map[string]time.Duration {
+	retention := make(map[string]time.Duration)
+	for _, item := range getEnvList("HISTORY_RETENTION", nil) {
+		interval, days, ok := strings.Cut(item, "=")
+		if !ok {
+			log.Printf("Valor inválido em HISTORY_RETENTION (%q), ignorando", item)
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(days))
+		if err != nil {
+			log.Printf("Valor inválido em HISTORY_RETENTION (%q), ignorando", item)
+			continue
+		}
+		retention[strings.TrimSpace(interval)] = time.Duration(n) * 24 * time.Hour
+	}
+	return retention
+}
+
+// GetHistoryCompactionEvery retorna o intervalo entre compactações do histórico
+func GetHistoryCompactionEvery() time.Duration {
+	return getEnvPositiveDuration("HISTORY_COMPACTION_EVERY", 24*time.Hour)
+}
+
+// GetWorkerPollInterval retorna o intervalo de consulta da listagem pelo worker
+func GetWorkerPollInterval() time.Duration {
+	return getEnvPositiveDuration("WORKER_POLL_INTERVAL", time.Minute)
+}
+
+// GetRiskFreeRate retorna a taxa livre de risco anual usada em Sharpe/Sortino
+// (ex: RISK_FREE_RATE=0.1075 para 10,75% a.a.)
+func GetRiskFreeRate() float64 {
+	return getEnvFloat("RISK_FREE_RATE", 0.10)
+}
+
+// GetBenchmarkSymbol retorna o símbolo do benchmark padrão (IBOVESPA)
+func GetBenchmarkSymbol() string {
+	if v := os.Getenv("BENCHMARK_SYMBOL"); v != "" {
+		return v
+	}
+	return "^BVSP"
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return fallback
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		log.Printf("Valor inválido para %s (%q), usando %v", key, v, fallback)
+		return fallback
+	}
+	return f
+}
+
+// GetDividendCalendarUniverse retorna quantas ações (as mais negociadas
+// por volume) entram no calendário de proventos; 0 usa o universo inteiro.
+// Cada ação custa uma chamada ao provider a cada DIVIDEND_CALENDAR_TTL.
+func GetDividendCalendarUniverse() int {
+	return getEnvInt("DIVIDEND_CALENDAR_UNIVERSE", 0)
+}
+
+// GetDividendCalendarTTL retorna por quanto tempo o calendário fica em cache
+func GetDividendCalendarTTL() time.Duration {
+	return getEnvDuration("DIVIDEND_CALENDAR_TTL", 6*time.Hour)
+}
+
+// GetDividendTaxRules retorna overrides das alíquotas de IR retido na fonte
+// sobre proventos, com vigência opcional pela data "com".
+// Ex: DIVIDEND_TAX_RULES=jcp=0.15,dividend=0.10@2026-01-01
+func GetDividendTaxRules() string {
+	return os.Getenv("DIVIDEND_TAX_RULES")
+}
+
+// GetIRRulesFile retorna o arquivo JSON com overrides das regras de
+// apuração do IR sobre renda variável (alíquotas, isenção, IRRF).
+// Ex: IR_RULES_FILE=config/ir_rules.json
+func GetIRRulesFile() string {
+	return os.Getenv("IR_RULES_FILE")
+}
+
+// GetDataDir retorna o diretório dos dados do usuário (screens, watchlists)
+func GetDataDir() string {
+	if v := os.Getenv("DATA_DIR"); v != "" {
+		return v
+	}
+	return "data"
+}
+
+// GetMarketUniverseTTL retorna por quanto tempo a listagem completa de
+// ações usada nas agregações de mercado fica em cache
+func GetMarketUniverseTTL() time.Duration {
+	return getEnvDuration("MARKET_UNIVERSE_TTL", time.Minute)
+}
+
+// GetMarketMinVolume retorna o volume mínimo para um ativo entrar nas
+// listas de maiores altas/baixas e na amplitude do mercado
+func GetMarketMinVolume() int64 {
+	return int64(getEnvInt("MARKET_MIN_VOLUME", 100000))
+}
+
+// GetScreenerUniverse retorna quantas ações (por volume) têm os campos de
+// fundamentos e proventos calculados no screener
+func GetScreenerUniverse() int {
+	return getEnvInt("SCREENER_UNIVERSE", 200)
+}
+
+// GetScreenerTTL retorna por quanto tempo os detalhes do screener ficam em cache
+func GetScreenerTTL() time.Duration {
+	return getEnvDuration("SCREENER_TTL", 15*time.Minute)
+}
+
+// GetPerformanceBenchmarks retorna os benchmarks padrão da rentabilidade
+// das carteiras. Ex: PERFORMANCE_BENCHMARKS=cdi,ibov,ipca+6,custom:bancoes
+func GetPerformanceBenchmarks() []string {
+	return getEnvList("PERFORMANCE_BENCHMARKS", []string{"cdi", "ibov"})
+}
+
+// GetRatesTTL retorna por quanto tempo as séries do Banco Central (CDI,
+// IPCA) ficam em cache
+func GetRatesTTL() time.Duration {
+	return getEnvDuration("RATES_TTL", 6*time.Hour)
+}
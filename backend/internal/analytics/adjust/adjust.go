@@ -0,0 +1,234 @@
+// Package adjust aplica eventos corporativos (desdobramentos, grupamentos,
+// bonificações e proventos em dinheiro) ao histórico de preços.
+package adjust
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+var ErrInvalidMode = errors.New("invalid adjust mode")
+
+// Mode define o tipo de ajuste
+type Mode string
+
+const (
+	// None devolve os preços brutos
+	None Mode = "none"
+	// Splits ajusta por desdobramentos, grupamentos e bonificações
+	Splits Mode = "splits"
+	// Total ajusta também por proventos em dinheiro (retorno total)
+	Total Mode = "total"
+)
+
+// ParseMode valida o parâmetro "adjust"
+func ParseMode(s string) (Mode, error) {
+	switch Mode(strings.ToLower(strings.TrimSpace(s))) {
+	case None:
+		return None, nil
+	case Splits:
+		return Splits, nil
+	case Total:
+		return Total, nil
+	}
+	return "", fmt.Errorf("%w: %q (use none, splits ou total)", ErrInvalidMode, s)
+}
+
+// splitEvent é um evento que muda a quantidade de ações
+type splitEvent struct {
+	lastDatePrior int64   // último dia "com" (inclusive)
+	ratio         float64 // ações depois / ações antes
+}
+
+// cashEvent é um provento em dinheiro por ação
+type cashEvent struct {
+	lastDatePrior int64
+	rate          float64
+}
+
+// Apply devolve uma cópia dos candles ajustada segundo o modo.
+// Candles até a data "com" de cada evento (inclusive) são ajustados:
+// preços são divididos pela razão do desdobramento (e volumes
+// multiplicados) e, no modo total, multiplicados pelo fator
+// 1 - provento/fechamento da data "com". O AdjustedClose passa a refletir
+// o modo escolhido.
+func Apply(candles []domain.HistoricalDataPrice, dividends domain.DividendsData, mode Mode) []domain.HistoricalDataPrice {
+	out := make([]domain.HistoricalDataPrice, len(candles))
+	copy(out, candles)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+
+	if mode == None {
+		for i := range out {
+			out[i].AdjustedClose = out[i].Close
+		}
+		return out
+	}
+
+	splits := splitEvents(dividends.StockDividends)
+
+	// 1) Ajuste por eventos de ações
+	for i := range out {
+		ratio := cumulativeRatio(splits, out[i].Date)
+		if ratio == 1 {
+			out[i].AdjustedClose = out[i].Close
+			continue
+		}
+		out[i].Open /= ratio
+		out[i].High /= ratio
+		out[i].Low /= ratio
+		out[i].Close /= ratio
+		out[i].AdjustedClose = out[i].Close
+		out[i].Volume = int64(float64(out[i].Volume) * ratio)
+	}
+
+	if mode == Splits {
+		return out
+	}
+
+	// 2) Ajuste por proventos em dinheiro, do mais recente para o mais antigo.
+	// O valor por ação é convertido para a quantidade atual de ações.
+	cash := cashEvents(dividends.CashDividends)
+	factors := make([]float64, len(out))
+	for i := range factors {
+		factors[i] = 1
+	}
+
+	for _, ev := range cash {
+		cum := lastCandleOnOrBefore(out, ev.lastDatePrior)
+		if cum < 0 || out[cum].Close <= 0 {
+			continue
+		}
+		// Sem candle depois do evento o provento ainda não afetou o preço
+		if cum == len(out)-1 && !sessionPassed(ev.lastDatePrior, out[cum].Date) {
+			continue
+		}
+
+		rate := ev.rate / cumulativeRatio(splits, ev.lastDatePrior)
+		factor := 1 - rate/out[cum].Close
+		if factor <= 0 || factor >= 1 {
+			continue
+		}
+		for i := 0; i <= cum; i++ {
+			factors[i] *= factor
+		}
+	}
+
+	for i := range out {
+		out[i].Open *= factors[i]
+		out[i].High *= factors[i]
+		out[i].Low *= factors[i]
+		out[i].Close *= factors[i]
+		out[i].AdjustedClose = out[i].Close
+	}
+	return out
+}
+
+// cumulativeRatio é o produto das razões dos eventos cuja data "com" é
+// igual ou posterior à data do candle
+func cumulativeRatio(events []splitEvent, date int64) float64 {
+	day := calendar.StartOfDay(time.Unix(date, 0)).Unix()
+	ratio := 1.0
+	for _, ev := range events {
+		if day <= ev.lastDatePrior {
+			ratio *= ev.ratio
+		}
+	}
+	return ratio
+}
+
+func lastCandleOnOrBefore(candles []domain.HistoricalDataPrice, day int64) int {
+	idx := -1
+	for i, c := range candles {
+		if calendar.StartOfDay(time.Unix(c.Date, 0)).Unix() <= day {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// sessionPassed informa se o candle é posterior ao dia "com"
+func sessionPassed(lastDatePrior, candleDate int64) bool {
+	return calendar.StartOfDay(time.Unix(candleDate, 0)).Unix() > lastDatePrior
+}
+
+func splitEvents(stockDividends []domain.StockDividend) []splitEvent {
+	events := make([]splitEvent, 0, len(stockDividends))
+	for _, sd := range stockDividends {
+		if sd.LastDatePrior.IsZero() {
+			continue
+		}
+		ratio := SplitRatio(sd)
+		if ratio <= 0 || ratio == 1 {
+			continue
+		}
+		events = append(events, splitEvent{
+			lastDatePrior: calendar.StartOfDay(sd.LastDatePrior).Unix(),
+			ratio:         ratio,
+		})
+	}
+	return events
+}
+
+func cashEvents(cashDividends []domain.CashDividend) []cashEvent {
+	events := make([]cashEvent, 0, len(cashDividends))
+	for _, cd := range cashDividends {
+		if cd.LastDatePrior.IsZero() || cd.Rate <= 0 {
+			continue
+		}
+		events = append(events, cashEvent{
+			lastDatePrior: calendar.StartOfDay(cd.LastDatePrior).Unix(),
+			rate:          cd.Rate,
+		})
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].lastDatePrior > events[j].lastDatePrior })
+	return events
+}
+
+var completeFactorPattern = regexp.MustCompile(`(?i)([\d.,]+)\s*para\s*([\d.,]+)`)
+
+// SplitRatio retorna quantas ações existem depois do evento para cada ação
+// antes dele. Usa o "completeFactor" ("1 para 2") quando disponível; senão
+// interpreta o fator como na B3: percentual para desdobramentos e
+// bonificações (100 = +100%) e razão direta para grupamentos (0,1 = 10:1).
+func SplitRatio(sd domain.StockDividend) float64 {
+	if m := completeFactorPattern.FindStringSubmatch(sd.CompleteFactor); m != nil {
+		before, errB := parseNumber(m[1])
+		after, errA := parseNumber(m[2])
+		if errB == nil && errA == nil && before > 0 && after > 0 {
+			return after / before
+		}
+	}
+
+	if sd.Factor <= 0 {
+		return 1
+	}
+
+	label := strings.ToUpper(sd.Label)
+	switch {
+	case strings.Contains(label, "GRUPAMENTO"):
+		if sd.Factor < 1 {
+			return sd.Factor
+		}
+		return 1 / sd.Factor
+	case strings.Contains(label, "DESDOBRAMENTO"), strings.Contains(label, "BONIFICA"):
+		return 1 + sd.Factor/100
+	}
+	return 1
+}
+
+// parseNumber aceita "1.000,5" e "1000.5"
+func parseNumber(s string) (float64, error) {
+	if strings.Contains(s, ",") {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	}
+	return strconv.ParseFloat(s, 64)
+}
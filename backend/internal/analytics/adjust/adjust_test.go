@@ -0,0 +1,136 @@
+package adjust
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+func day(d int) time.Time {
+	return time.Date(2024, 3, d, 0, 0, 0, 0, calendar.Location)
+}
+
+// candles monta pregões consecutivos a partir de 4/3, às 17h
+func candles(closes ...float64) []domain.HistoricalDataPrice {
+	out := make([]domain.HistoricalDataPrice, len(closes))
+	for i, c := range closes {
+		out[i] = domain.HistoricalDataPrice{
+			Date:   day(4+i).Add(17 * time.Hour).Unix(),
+			Open:   c,
+			High:   c,
+			Low:    c,
+			Close:  c,
+			Volume: 1000,
+		}
+	}
+	return out
+}
+
+func TestApply(t *testing.T) {
+	tests := []struct {
+		name       string
+		closes     []float64
+		dividends  domain.DividendsData
+		wantSplits []float64
+		wantTotal  []float64
+	}{
+		{
+			name:   "desdobramento 1:2",
+			closes: []float64{40, 40, 20},
+			dividends: domain.DividendsData{StockDividends: []domain.StockDividend{
+				{Label: "DESDOBRAMENTO", Factor: 100, LastDatePrior: day(5)},
+			}},
+			wantSplits: []float64{20, 20, 20},
+			wantTotal:  []float64{20, 20, 20},
+		},
+		{
+			name:   "grupamento 10:1",
+			closes: []float64{1, 1, 10},
+			dividends: domain.DividendsData{StockDividends: []domain.StockDividend{
+				{Label: "GRUPAMENTO", Factor: 10, LastDatePrior: day(5)},
+			}},
+			wantSplits: []float64{10, 10, 10},
+			wantTotal:  []float64{10, 10, 10},
+		},
+		{
+			name:   "bonificação de 10%",
+			closes: []float64{11, 11, 10},
+			dividends: domain.DividendsData{StockDividends: []domain.StockDividend{
+				{Label: "BONIFICACAO", Factor: 10, LastDatePrior: day(5)},
+			}},
+			wantSplits: []float64{10, 10, 10},
+			wantTotal:  []float64{10, 10, 10},
+		},
+		{
+			name:   "dividendo de R$ 1 com fechamento de 20 na data com",
+			closes: []float64{21, 20, 19},
+			dividends: domain.DividendsData{CashDividends: []domain.CashDividend{
+				{Label: "DIVIDENDO", Rate: 1, LastDatePrior: day(5)},
+			}},
+			wantSplits: []float64{21, 20, 19},
+			wantTotal:  []float64{21 * 0.95, 19, 19},
+		},
+		{
+			name:   "dividendo por ação antiga no dia do desdobramento",
+			closes: []float64{40, 40, 19},
+			dividends: domain.DividendsData{
+				StockDividends: []domain.StockDividend{
+					{Label: "DESDOBRAMENTO", CompleteFactor: "1 para 2", LastDatePrior: day(5)},
+				},
+				CashDividends: []domain.CashDividend{
+					{Label: "JCP", Rate: 2, LastDatePrior: day(5)},
+				},
+			},
+			wantSplits: []float64{20, 20, 19},
+			wantTotal:  []float64{19, 19, 19},
+		},
+		{
+			name:   "dividendo sem pregão depois da data com",
+			closes: []float64{21, 20, 19},
+			dividends: domain.DividendsData{CashDividends: []domain.CashDividend{
+				{Label: "DIVIDENDO", Rate: 1, LastDatePrior: day(6)},
+			}},
+			wantSplits: []float64{21, 20, 19},
+			wantTotal:  []float64{21, 20, 19},
+		},
+	}
+
+	for _, tt := range tests {
+		raw := candles(tt.closes...)
+		for mode, want := range map[Mode][]float64{None: tt.closes, Splits: tt.wantSplits, Total: tt.wantTotal} {
+			got := Apply(raw, tt.dividends, mode)
+			for i, w := range want {
+				c := got[i]
+				if math.Abs(c.Close-w) > 1e-9 || c.AdjustedClose != c.Close || c.Open != c.Close {
+					t.Errorf("%s (%s): candle %d = close %.4f/ajustado %.4f/abertura %.4f, esperado %.4f",
+						tt.name, mode, i, c.Close, c.AdjustedClose, c.Open, w)
+				}
+			}
+		}
+		if raw[0].Close != tt.closes[0] {
+			t.Errorf("%s: Apply alterou os candles de entrada", tt.name)
+		}
+	}
+}
+
+func TestApplySplitVolume(t *testing.T) {
+	dividends := domain.DividendsData{StockDividends: []domain.StockDividend{
+		{Label: "DESDOBRAMENTO", Factor: 100, LastDatePrior: day(5)},
+	}}
+	got := Apply(candles(40, 40, 20), dividends, Splits)
+	if got[0].Volume != 2000 || got[2].Volume != 1000 {
+		t.Errorf("volumes = %d/%d, esperado 2000 antes e 1000 depois do desdobramento", got[0].Volume, got[2].Volume)
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	if m, err := ParseMode(" Total "); err != nil || m != Total {
+		t.Errorf("ParseMode(total) = %q, %v", m, err)
+	}
+	if _, err := ParseMode("dividends"); err == nil {
+		t.Error("esperado erro para modo desconhecido")
+	}
+}
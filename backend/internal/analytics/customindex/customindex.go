@@ -0,0 +1,278 @@
+// Package customindex calcula o nível histórico de índices definidos pelo
+// usuário a partir dos preços dos ativos que os compõem.
+package customindex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"cotacoes/internal/analytics/stats"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// Limites de composição
+const (
+	MaxConstituents  = 50
+	DefaultBaseValue = 1000
+)
+
+var ErrInvalidDefinition = errors.New("definição de índice inválida")
+
+// Normalize valida a definição e preenche os padrões (ponderação equal,
+// sem rebalanceamento, base 1000). Símbolos ficam em maiúsculas.
+func Normalize(idx *domain.CustomIndex) error {
+	idx.Name = strings.TrimSpace(idx.Name)
+	if idx.Name == "" {
+		return fmt.Errorf("%w: informe name", ErrInvalidDefinition)
+	}
+
+	idx.Weighting = strings.ToLower(strings.TrimSpace(idx.Weighting))
+	switch idx.Weighting {
+	case "":
+		idx.Weighting = domain.WeightingEqual
+	case domain.WeightingEqual, domain.WeightingCap, domain.WeightingCustom:
+	default:
+		return fmt.Errorf("%w: weighting %q (use equal, cap ou custom)", ErrInvalidDefinition, idx.Weighting)
+	}
+
+	idx.Rebalance = strings.ToLower(strings.TrimSpace(idx.Rebalance))
+	switch idx.Rebalance {
+	case "":
+		idx.Rebalance = domain.RebalanceNone
+	case domain.RebalanceNone, domain.RebalanceMonthly, domain.RebalanceQuarterly, domain.RebalanceYearly:
+	default:
+		return fmt.Errorf("%w: rebalance %q (use none, monthly, quarterly ou yearly)", ErrInvalidDefinition, idx.Rebalance)
+	}
+
+	switch {
+	case idx.BaseValue == 0:
+		idx.BaseValue = DefaultBaseValue
+	case idx.BaseValue < 0:
+		return fmt.Errorf("%w: baseValue deve ser positivo", ErrInvalidDefinition)
+	}
+
+	if len(idx.Constituents) == 0 {
+		return fmt.Errorf("%w: informe ao menos 1 ativo", ErrInvalidDefinition)
+	}
+	if len(idx.Constituents) > MaxConstituents {
+		return fmt.Errorf("%w: máximo de %d ativos", ErrInvalidDefinition, MaxConstituents)
+	}
+
+	seen := make(map[string]bool, len(idx.Constituents))
+	for i := range idx.Constituents {
+		c := &idx.Constituents[i]
+		c.Symbol = strings.ToUpper(strings.TrimSpace(c.Symbol))
+		switch {
+		case c.Symbol == "":
+			return fmt.Errorf("%w: ativo %d sem símbolo", ErrInvalidDefinition, i+1)
+		case IsSymbol(c.Symbol):
+			return fmt.Errorf("%w: %s é um índice customizado", ErrInvalidDefinition, c.Symbol)
+		case seen[c.Symbol]:
+			return fmt.Errorf("%w: %s repetido", ErrInvalidDefinition, c.Symbol)
+		}
+		seen[c.Symbol] = true
+
+		if idx.Weighting == domain.WeightingCustom {
+			if c.Weight <= 0 || math.IsNaN(c.Weight) {
+				return fmt.Errorf("%w: peso de %s deve ser positivo", ErrInvalidDefinition, c.Symbol)
+			}
+		} else {
+			c.Weight = 0
+		}
+	}
+	return nil
+}
+
+// Prefixo que identifica um índice customizado onde se espera um símbolo
+// (ex: benchmark=custom:bancoes)
+const symbolPrefix = "custom:"
+
+// Symbol retorna o símbolo do índice
+func Symbol(id string) string {
+	return symbolPrefix + id
+}
+
+// IsSymbol indica se o símbolo é de um índice customizado
+func IsSymbol(symbol string) bool {
+	return len(symbol) > len(symbolPrefix) && strings.EqualFold(symbol[:len(symbolPrefix)], symbolPrefix)
+}
+
+// IDFromSymbol extrai o id do índice do símbolo
+func IDFromSymbol(symbol string) string {
+	return strings.ToLower(symbol[len(symbolPrefix):])
+}
+
+// Rebalance é uma recomposição do índice: os pesos passam a valer a
+// partir de Date
+type Rebalance struct {
+	Date    time.Time          `json:"date"`
+	Reason  string             `json:"reason"`
+	Weights map[string]float64 `json:"weights"`
+}
+
+// Input reúne os preços de cada ativo e, para a ponderação por valor de
+// mercado, a quantidade de ações
+type Input struct {
+	Prices map[string][]stats.Point
+	Shares map[string]float64
+}
+
+// Result é o histórico do índice
+type Result struct {
+	Levels     []stats.Point `json:"-"`
+	Rebalances []Rebalance   `json:"rebalances"`
+	Warnings   []string      `json:"warnings,omitempty"`
+}
+
+// Compute calcula o nível do índice em cada data em que algum ativo tem
+// preço. O índice começa em BaseValue na primeira dessas datas. Em cada
+// rebalanceamento o valor é redistribuído pelos pesos-alvo entre os ativos
+// que já negociam; um ativo que ainda não existia entra no índice (com
+// novo rebalanceamento) na sua primeira cotação. Em datas sem negócio o
+// último preço do ativo é mantido.
+func Compute(idx domain.CustomIndex, in Input, interval string) Result {
+	var result Result
+
+	// Preços por data alinhada e datas de todos os ativos
+	prices := make(map[string]map[int64]float64, len(idx.Constituents))
+	union := make(map[int64]bool)
+	members := make([]domain.IndexConstituent, 0, len(idx.Constituents))
+	for _, c := range idx.Constituents {
+		series := in.Prices[c.Symbol]
+		if len(series) == 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s sem histórico de preços no período", c.Symbol))
+			continue
+		}
+		if idx.Weighting == domain.WeightingCap && in.Shares[c.Symbol] <= 0 {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s sem quantidade de ações: fora da ponderação por valor de mercado", c.Symbol))
+			continue
+		}
+		m := make(map[int64]float64, len(series))
+		for _, p := range series {
+			key := stats.DateKey(p.Date, interval)
+			m[key] = p.Value
+			union[key] = true
+		}
+		prices[c.Symbol] = m
+		members = append(members, c)
+	}
+
+	dates := make([]int64, 0, len(union))
+	for d := range union {
+		dates = append(dates, d)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i] < dates[j] })
+
+	last := make(map[string]float64, len(members)) // último preço conhecido
+	units := make(map[string]float64, len(members))
+	level := idx.BaseValue
+	period := ""
+
+	for _, date := range dates {
+		listed := []string{}
+		for _, c := range members {
+			if p, ok := prices[c.Symbol][date]; ok {
+				if _, known := last[c.Symbol]; !known {
+					listed = append(listed, c.Symbol)
+				}
+				last[c.Symbol] = p
+			}
+		}
+
+		// Nível com a composição vigente, antes de qualquer rebalanceamento
+		if len(units) > 0 {
+			level = 0
+			for symbol, u := range units {
+				level += u * last[symbol]
+			}
+		}
+
+		reason := ""
+		current := periodKey(date, idx.Rebalance)
+		switch {
+		case len(units) == 0:
+			reason = "início"
+		case len(listed) > 0:
+			reason = "inclusão de " + strings.Join(listed, ", ")
+		case current != period:
+			reason = "rebalanceamento " + idx.Rebalance
+		}
+		period = current
+
+		if reason != "" {
+			weights := targetWeights(idx, members, last, in.Shares)
+			units = make(map[string]float64, len(weights))
+			for symbol, w := range weights {
+				units[symbol] = level * w / last[symbol]
+			}
+			result.Rebalances = append(result.Rebalances, Rebalance{
+				Date:    time.Unix(date, 0).In(calendar.Location),
+				Reason:  reason,
+				Weights: roundWeights(weights),
+			})
+		}
+
+		result.Levels = append(result.Levels, stats.Point{Date: date, Value: level})
+	}
+
+	return result
+}
+
+// targetWeights calcula os pesos-alvo entre os ativos que já têm preço,
+// normalizados para somar 1
+func targetWeights(
+	idx domain.CustomIndex,
+	members []domain.IndexConstituent,
+	last map[string]float64,
+	shares map[string]float64,
+) map[string]float64 {
+
+	raw := make(map[string]float64, len(members))
+	total := 0.0
+	for _, c := range members {
+		price, ok := last[c.Symbol]
+		if !ok || price <= 0 {
+			continue
+		}
+		w := 1.0
+		switch idx.Weighting {
+		case domain.WeightingCustom:
+			w = c.Weight
+		case domain.WeightingCap:
+			w = shares[c.Symbol] * price
+		}
+		raw[c.Symbol] = w
+		total += w
+	}
+	for symbol := range raw {
+		raw[symbol] /= total
+	}
+	return raw
+}
+
+// periodKey identifica o período de rebalanceamento da data
+func periodKey(unix int64, rebalance string) string {
+	t := time.Unix(unix, 0).In(calendar.Location)
+	switch rebalance {
+	case domain.RebalanceMonthly:
+		return t.Format("2006-01")
+	case domain.RebalanceQuarterly:
+		return fmt.Sprintf("%d-Q%d", t.Year(), (int(t.Month())-1)/3+1)
+	case domain.RebalanceYearly:
+		return t.Format("2006")
+	}
+	return ""
+}
+
+func roundWeights(weights map[string]float64) map[string]float64 {
+	out := make(map[string]float64, len(weights))
+	for symbol, w := range weights {
+		out[symbol] = math.Round(w*1e6) / 1e6
+	}
+	return out
+}
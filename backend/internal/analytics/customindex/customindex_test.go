@@ -0,0 +1,107 @@
+package customindex
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"cotacoes/internal/analytics/stats"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+func date(month time.Month, d int) int64 {
+	return time.Date(2024, month, d, 0, 0, 0, 0, calendar.Location).Unix()
+}
+
+func series(month time.Month, first int, values ...float64) []stats.Point {
+	points := make([]stats.Point, len(values))
+	for i, v := range values {
+		points[i] = stats.Point{Date: date(month, first+i), Value: v}
+	}
+	return points
+}
+
+func index(t *testing.T, weighting, rebalance string, symbols ...string) domain.CustomIndex {
+	t.Helper()
+	idx := domain.CustomIndex{Name: "Teste", Weighting: weighting, Rebalance: rebalance}
+	for _, s := range symbols {
+		idx.Constituents = append(idx.Constituents, domain.IndexConstituent{Symbol: s})
+	}
+	if err := Normalize(&idx); err != nil {
+		t.Fatal(err)
+	}
+	return idx
+}
+
+func assertLevels(t *testing.T, got []stats.Point, want ...float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("%d níveis, esperado %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		if math.Abs(got[i].Value-w) > 1e-9 {
+			t.Errorf("nível %d = %.4f, esperado %.4f", i, got[i].Value, w)
+		}
+	}
+}
+
+func TestComputeLateListing(t *testing.T) {
+	// B só começa a negociar no segundo pregão e entra com peso igual
+	in := Input{Prices: map[string][]stats.Point{
+		"AAAA3": series(time.March, 4, 10, 11, 12),
+		"BBBB3": series(time.March, 5, 20, 22),
+	}}
+
+	result := Compute(index(t, domain.WeightingEqual, domain.RebalanceNone, "AAAA3", "BBBB3"), in, "1d")
+	// 1000 → 1100 (só A); depois 50 A + 27,5 B
+	assertLevels(t, result.Levels, 1000, 1100, 1205)
+	if len(result.Rebalances) != 2 || result.Rebalances[1].Reason != "inclusão de BBBB3" {
+		t.Fatalf("rebalanceamentos = %+v", result.Rebalances)
+	}
+	if w := result.Rebalances[1].Weights; w["AAAA3"] != 0.5 || w["BBBB3"] != 0.5 {
+		t.Errorf("pesos na inclusão = %v, esperado 0,5/0,5", w)
+	}
+}
+
+func TestComputeMonthlyRebalance(t *testing.T) {
+	in := Input{Prices: map[string][]stats.Point{
+		"AAAA3": append(series(time.January, 30, 10, 20), series(time.February, 1, 20, 10)...),
+		"BBBB3": append(series(time.January, 30, 10, 10), series(time.February, 1, 10, 10)...),
+	}}
+
+	monthly := Compute(index(t, domain.WeightingEqual, domain.RebalanceMonthly, "AAAA3", "BBBB3"), in, "1d")
+	// Em fevereiro o valor de 1500 volta a ser dividido meio a meio
+	assertLevels(t, monthly.Levels, 1000, 1500, 1500, 1125)
+	if len(monthly.Rebalances) != 2 || monthly.Rebalances[1].Reason != "rebalanceamento monthly" {
+		t.Errorf("rebalanceamentos = %+v", monthly.Rebalances)
+	}
+
+	none := Compute(index(t, domain.WeightingEqual, domain.RebalanceNone, "AAAA3", "BBBB3"), in, "1d")
+	assertLevels(t, none.Levels, 1000, 1500, 1500, 1000)
+	if len(none.Rebalances) != 1 {
+		t.Errorf("sem rebalanceamento: %+v", none.Rebalances)
+	}
+}
+
+func TestComputeCapWeighting(t *testing.T) {
+	in := Input{
+		Prices: map[string][]stats.Point{
+			"AAAA3": series(time.March, 4, 10, 12),
+			"BBBB3": series(time.March, 4, 20, 20),
+			"CCCC3": series(time.March, 4, 5, 50),
+		},
+		// Valor de mercado: A = 1.000, B = 3.000; C sem quantidade de ações
+		Shares: map[string]float64{"AAAA3": 100, "BBBB3": 150},
+	}
+
+	result := Compute(index(t, domain.WeightingCap, domain.RebalanceNone, "AAAA3", "BBBB3", "CCCC3"), in, "1d")
+	assertLevels(t, result.Levels, 1000, 1050)
+	if w := result.Rebalances[0].Weights; w["AAAA3"] != 0.25 || w["BBBB3"] != 0.75 || len(w) != 2 {
+		t.Errorf("pesos = %v, esperado 0,25/0,75 sem CCCC3", w)
+	}
+	if len(result.Warnings) != 1 || !strings.Contains(result.Warnings[0], "CCCC3") {
+		t.Errorf("avisos = %v, esperado CCCC3 fora da ponderação", result.Warnings)
+	}
+}
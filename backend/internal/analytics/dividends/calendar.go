@@ -0,0 +1,78 @@
+package dividends
+
+import (
+	"sort"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// CalendarEvent é um provento com datas "com", ex e de pagamento
+type CalendarEvent struct {
+	Symbol        string     `json:"symbol"`
+	Label         string     `json:"label"`
+	Kind          string     `json:"kind"`
+	Rate          float64    `json:"rate"`
+	NetRate       float64    `json:"netRate"`
+	Withholding   float64    `json:"withholdingRate"`
+	LastDatePrior time.Time  `json:"lastDatePrior"`
+	ExDate        time.Time  `json:"exDate"`
+	PaymentDate   *time.Time `json:"paymentDate"`
+}
+
+// Events converte os proventos de uma ação em eventos de calendário.
+// A data ex é o pregão seguinte à data "com".
+func Events(symbol string, cash []domain.CashDividend) []CalendarEvent {
+	events := make([]CalendarEvent, 0, len(cash))
+	for _, cd := range cash {
+		if cd.LastDatePrior.IsZero() {
+			continue
+		}
+		ev := CalendarEvent{
+			Symbol:        symbol,
+			Label:         cd.Label,
+			Kind:          Kind(cd.Label),
+			Rate:          cd.Rate,
+			NetRate:       cd.NetRate,
+			Withholding:   cd.WithholdingRate,
+			LastDatePrior: cd.LastDatePrior.In(calendar.Location),
+			ExDate:        calendar.Default.NextTradingDay(cd.LastDatePrior),
+		}
+		if !cd.PaymentDate.IsZero() {
+			payment := cd.PaymentDate.In(calendar.Location)
+			ev.PaymentDate = &payment
+		}
+		events = append(events, ev)
+	}
+	return events
+}
+
+// Window filtra eventos com data ex ou de pagamento entre from e to
+// (inclusivos) e ordena pela primeira dessas datas dentro da janela
+func Window(events []CalendarEvent, from, to time.Time) []CalendarEvent {
+	inWindow := func(t time.Time) bool {
+		return !t.Before(from) && !t.After(to)
+	}
+	key := func(ev CalendarEvent) time.Time {
+		if inWindow(ev.ExDate) || ev.PaymentDate == nil {
+			return ev.ExDate
+		}
+		return *ev.PaymentDate
+	}
+
+	out := make([]CalendarEvent, 0)
+	for _, ev := range events {
+		if inWindow(ev.ExDate) || (ev.PaymentDate != nil && inWindow(*ev.PaymentDate)) {
+			out = append(out, ev)
+		}
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		ki, kj := key(out[i]), key(out[j])
+		if !ki.Equal(kj) {
+			return ki.Before(kj)
+		}
+		return out[i].Symbol < out[j].Symbol
+	})
+	return out
+}
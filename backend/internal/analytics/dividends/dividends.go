@@ -0,0 +1,239 @@
+// Package dividends resume o histórico de proventos em dinheiro
+// (CashDividends): dividend yield, totais anuais, frequência e sequências.
+package dividends
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// Tipos de provento
+const (
+	KindDividend = "dividend" // Dividendos
+	KindJCP      = "jcp"      // Juros sobre capital próprio
+	KindIncome   = "income"   // Rendimentos (FIIs)
+	KindOther    = "other"
+)
+
+// Kind classifica o provento pelo Label da B3
+func Kind(label string) string {
+	l := strings.ToUpper(label)
+	switch {
+	case strings.Contains(l, "JCP"), strings.Contains(l, "JUROS"), strings.Contains(l, "JRS"):
+		return KindJCP
+	case strings.Contains(l, "DIVIDEND"):
+		return KindDividend
+	case strings.Contains(l, "RENDIMENTO"):
+		return KindIncome
+	}
+	return KindOther
+}
+
+// YearTotal soma os proventos por ação de um ano (pela data "com")
+type YearTotal struct {
+	Year     int                `json:"year"`
+	Total    float64            `json:"total"`
+	NetTotal float64            `json:"netTotal"`
+	ByKind   map[string]float64 `json:"byKind"`
+	Payments int                `json:"payments"`
+}
+
+// Streaks mede a consistência dos pagamentos em anos completos
+type Streaks struct {
+	// Anos consecutivos com pagamento, terminando no último ano completo
+	ConsecutiveYearsPaid int `json:"consecutiveYearsPaid"`
+	// Anos consecutivos com total por ação maior que o ano anterior
+	ConsecutiveYearsGrowth int `json:"consecutiveYearsGrowth"`
+	// Maior sequência de anos com pagamento no histórico
+	LongestYearsPaid int `json:"longestYearsPaid"`
+}
+
+// Summary é o resumo de proventos de uma ação
+type Summary struct {
+	Price           float64            `json:"price"`
+	TTMPerShare     float64            `json:"ttmPerShare"`
+	TTMNetPerShare  float64            `json:"ttmNetPerShare"`
+	TTMYield        *float64           `json:"ttmYield"`
+	TTMNetYield     *float64           `json:"ttmNetYield"`
+	TTMByKind       map[string]float64 `json:"ttmByKind"`
+	Frequency       string             `json:"frequency"`
+	PaymentsPerYear float64            `json:"paymentsPerYear"`
+	LastPayment     *time.Time         `json:"lastPayment"`
+	NextPayment     *time.Time         `json:"nextPayment"`
+	YearlyTotals    []YearTotal        `json:"yearlyTotals"`
+	Streaks         Streaks            `json:"streaks"`
+}
+
+// Summarize calcula o resumo a partir dos proventos e do preço atual.
+// Eventos são datados pela data "com" (LastDatePrior); sem ela, usa a data
+// de pagamento. Os valores brutos (ByKind) usam Rate e os líquidos usam
+// NetRate, que deve ter sido preenchido por ApplyWithholding.
+func Summarize(cash []domain.CashDividend, price float64, now time.Time) Summary {
+	now = now.In(calendar.Location)
+	summary := Summary{
+		Price:        price,
+		TTMByKind:    map[string]float64{},
+		YearlyTotals: []YearTotal{},
+		Frequency:    "none",
+	}
+
+	ttmStart := now.AddDate(-1, 0, 0)
+	years := make(map[int]*YearTotal)
+	// Dividendo e JCP na mesma data contam como um único pagamento
+	dates := make(map[int]map[string]bool)
+
+	for _, cd := range cash {
+		if cd.Rate <= 0 {
+			continue
+		}
+		date := EventDate(cd)
+		if date.IsZero() {
+			continue
+		}
+		kind := Kind(cd.Label)
+
+		if date.After(ttmStart) && !date.After(now) {
+			summary.TTMPerShare += cd.Rate
+			summary.TTMNetPerShare += cd.NetRate
+			summary.TTMByKind[kind] += cd.Rate
+		}
+
+		y := years[date.Year()]
+		if y == nil {
+			y = &YearTotal{Year: date.Year(), ByKind: map[string]float64{}}
+			years[date.Year()] = y
+		}
+		y.Total += cd.Rate
+		y.NetTotal += cd.NetRate
+		y.ByKind[kind] += cd.Rate
+		if dates[date.Year()] == nil {
+			dates[date.Year()] = make(map[string]bool)
+		}
+		dates[date.Year()][date.Format("2006-01-02")] = true
+		y.Payments = len(dates[date.Year()])
+
+		if !cd.PaymentDate.IsZero() {
+			payment := cd.PaymentDate.In(calendar.Location)
+			if !payment.After(now) && (summary.LastPayment == nil || payment.After(*summary.LastPayment)) {
+				summary.LastPayment = &payment
+			}
+			if payment.After(now) && (summary.NextPayment == nil || payment.Before(*summary.NextPayment)) {
+				summary.NextPayment = &payment
+			}
+		}
+	}
+
+	if price > 0 {
+		yield := summary.TTMPerShare / price
+		netYield := summary.TTMNetPerShare / price
+		summary.TTMYield = &yield
+		summary.TTMNetYield = &netYield
+	}
+
+	for _, y := range years {
+		y.Total = round(y.Total)
+		y.NetTotal = round(y.NetTotal)
+		for k, v := range y.ByKind {
+			y.ByKind[k] = round(v)
+		}
+		summary.YearlyTotals = append(summary.YearlyTotals, *y)
+	}
+	sort.Slice(summary.YearlyTotals, func(i, j int) bool {
+		return summary.YearlyTotals[i].Year < summary.YearlyTotals[j].Year
+	})
+
+	summary.PaymentsPerYear, summary.Frequency = frequency(years, now.Year())
+	summary.Streaks = streaks(years, now.Year())
+	summary.TTMPerShare = round(summary.TTMPerShare)
+	summary.TTMNetPerShare = round(summary.TTMNetPerShare)
+	for k, v := range summary.TTMByKind {
+		summary.TTMByKind[k] = round(v)
+	}
+	return summary
+}
+
+// EventDate retorna a data "com" do provento, ou a de pagamento na falta dela
+func EventDate(cd domain.CashDividend) time.Time {
+	if !cd.LastDatePrior.IsZero() {
+		return cd.LastDatePrior.In(calendar.Location)
+	}
+	if !cd.PaymentDate.IsZero() {
+		return cd.PaymentDate.In(calendar.Location)
+	}
+	return time.Time{}
+}
+
+// frequency usa a média de pagamentos (datas distintas) por ano nos
+// últimos 3 anos completos
+func frequency(years map[int]*YearTotal, currentYear int) (float64, string) {
+	total, counted := 0, 0
+	for y := currentYear - 3; y < currentYear; y++ {
+		if yt, ok := years[y]; ok {
+			total += yt.Payments
+			counted++
+		}
+	}
+	if counted == 0 {
+		return 0, "none"
+	}
+
+	perYear := float64(total) / 3
+	switch {
+	case perYear >= 10:
+		return perYear, "monthly"
+	case perYear >= 3.5:
+		return perYear, "quarterly"
+	case perYear >= 1.5:
+		return perYear, "semiannual"
+	case perYear >= 0.9 && counted == 3:
+		return perYear, "annual"
+	}
+	return perYear, "irregular"
+}
+
+func streaks(years map[int]*YearTotal, currentYear int) Streaks {
+	var s Streaks
+
+	for y := currentYear - 1; ; y-- {
+		if _, ok := years[y]; !ok {
+			break
+		}
+		s.ConsecutiveYearsPaid++
+	}
+
+	for y := currentYear - 1; ; y-- {
+		cur, ok := years[y]
+		prev, okPrev := years[y-1]
+		if !ok || !okPrev || cur.Total <= prev.Total {
+			break
+		}
+		s.ConsecutiveYearsGrowth++
+	}
+
+	run := 0
+	keys := make([]int, 0, len(years))
+	for y := range years {
+		if y < currentYear {
+			keys = append(keys, y)
+		}
+	}
+	sort.Ints(keys)
+	for i, y := range keys {
+		if i > 0 && keys[i-1] == y-1 {
+			run++
+		} else {
+			run = 1
+		}
+		s.LongestYearsPaid = max(s.LongestYearsPaid, run)
+	}
+	return s
+}
+
+func round(v float64) float64 {
+	return math.Round(v*1e8) / 1e8
+}
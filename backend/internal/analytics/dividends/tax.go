@@ -0,0 +1,117 @@
+package dividends
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// TaxRule define a alíquota retida na fonte para um tipo de provento a
+// partir de uma data (data "com" do evento)
+type TaxRule struct {
+	Kind          string    `json:"kind"`
+	Rate          float64   `json:"rate"`
+	EffectiveFrom time.Time `json:"effectiveFrom"`
+}
+
+// TaxRules é o conjunto de regras de retenção
+type TaxRules []TaxRule
+
+// DefaultTaxRules: JCP tem 15% de IR retido na fonte; dividendos e
+// rendimentos de FIIs são isentos para pessoa física
+func DefaultTaxRules() TaxRules {
+	return TaxRules{
+		{Kind: KindJCP, Rate: 0.15},
+		{Kind: KindDividend, Rate: 0},
+		{Kind: KindIncome, Rate: 0},
+		{Kind: KindOther, Rate: 0},
+	}
+}
+
+// ParseTaxRules interpreta "jcp=0.15,dividend=0.10@2026-01-01". A data
+// opcional após "@" define o início da vigência (data "com").
+func ParseTaxRules(spec string) (TaxRules, error) {
+	rules := TaxRules{}
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		kind, rest, ok := strings.Cut(item, "=")
+		if !ok {
+			return nil, fmt.Errorf("regra de retenção inválida: %q", item)
+		}
+		rateStr, dateStr, hasDate := strings.Cut(rest, "@")
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(rateStr), 64)
+		if err != nil || rate < 0 || rate >= 1 {
+			return nil, fmt.Errorf("alíquota inválida em %q", item)
+		}
+
+		rule := TaxRule{Kind: strings.ToLower(strings.TrimSpace(kind)), Rate: rate}
+		if hasDate {
+			from, err := time.ParseInLocation("2006-01-02", strings.TrimSpace(dateStr), calendar.Location)
+			if err != nil {
+				return nil, fmt.Errorf("data de vigência inválida em %q", item)
+			}
+			rule.EffectiveFrom = from
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Merge devolve as regras atuais acrescidas de overrides; para o mesmo tipo
+// e vigência, o override prevalece
+func (r TaxRules) Merge(overrides TaxRules) TaxRules {
+	merged := make(TaxRules, 0, len(r)+len(overrides))
+	for _, rule := range r {
+		replaced := false
+		for _, o := range overrides {
+			if o.Kind == rule.Kind && o.EffectiveFrom.Equal(rule.EffectiveFrom) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged = append(merged, rule)
+		}
+	}
+	return append(merged, overrides...)
+}
+
+// RateFor retorna a alíquota vigente para o tipo na data
+func (r TaxRules) RateFor(kind string, date time.Time) float64 {
+	candidates := make(TaxRules, 0)
+	for _, rule := range r {
+		if rule.Kind == kind && !rule.EffectiveFrom.After(date) {
+			candidates = append(candidates, rule)
+		}
+	}
+	if len(candidates) == 0 {
+		return 0
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].EffectiveFrom.Before(candidates[j].EffectiveFrom)
+	})
+	return candidates[len(candidates)-1].Rate
+}
+
+// ApplyWithholding devolve uma cópia dos proventos com a alíquota retida e
+// o valor líquido por ação preenchidos
+func ApplyWithholding(cash []domain.CashDividend, rules TaxRules) []domain.CashDividend {
+	out := make([]domain.CashDividend, len(cash))
+	for i, cd := range cash {
+		rate := rules.RateFor(Kind(cd.Label), EventDate(cd))
+		cd.WithholdingRate = rate
+		cd.NetRate = round(cd.Rate * (1 - rate))
+		out[i] = cd
+	}
+	return out
+}
@@ -0,0 +1,199 @@
+// Package indicators calcula indicadores técnicos sobre séries de preços.
+//
+// Todas as funções recebem séries sem lacunas e devolvem séries do mesmo
+// tamanho, com NaN nas posições de aquecimento (warm-up), onde ainda não há
+// dados suficientes para o cálculo.
+package indicators
+
+import "math"
+
+// SMA é a média móvel simples de n períodos
+func SMA(values []float64, n int) []float64 {
+	out := nanSeries(len(values))
+	if n <= 0 || len(values) < n {
+		return out
+	}
+
+	sum := 0.0
+	for i, v := range values {
+		sum += v
+		if i >= n {
+			sum -= values[i-n]
+		}
+		if i >= n-1 {
+			out[i] = sum / float64(n)
+		}
+	}
+	return out
+}
+
+// EMA é a média móvel exponencial de n períodos, semeada com a SMA dos
+// primeiros n valores (convenção do TA-Lib)
+func EMA(values []float64, n int) []float64 {
+	out := nanSeries(len(values))
+	if n <= 0 || len(values) < n {
+		return out
+	}
+
+	k := 2.0 / float64(n+1)
+	sum := 0.0
+	for i := 0; i < n; i++ {
+		sum += values[i]
+	}
+	out[n-1] = sum / float64(n)
+	for i := n; i < len(values); i++ {
+		out[i] = values[i]*k + out[i-1]*(1-k)
+	}
+	return out
+}
+
+// RSI é o índice de força relativa com a suavização de Wilder
+func RSI(values []float64, n int) []float64 {
+	out := nanSeries(len(values))
+	if n <= 0 || len(values) <= n {
+		return out
+	}
+
+	gain, loss := 0.0, 0.0
+	for i := 1; i <= n; i++ {
+		change := values[i] - values[i-1]
+		if change > 0 {
+			gain += change
+		} else {
+			loss -= change
+		}
+	}
+	avgGain := gain / float64(n)
+	avgLoss := loss / float64(n)
+	out[n] = rsiValue(avgGain, avgLoss)
+
+	for i := n + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		g, l := 0.0, 0.0
+		if change > 0 {
+			g = change
+		} else {
+			l = -change
+		}
+		avgGain = (avgGain*float64(n-1) + g) / float64(n)
+		avgLoss = (avgLoss*float64(n-1) + l) / float64(n)
+		out[i] = rsiValue(avgGain, avgLoss)
+	}
+	return out
+}
+
+func rsiValue(avgGain, avgLoss float64) float64 {
+	if avgLoss == 0 {
+		if avgGain == 0 {
+			return 50
+		}
+		return 100
+	}
+	rs := avgGain / avgLoss
+	return 100 - 100/(1+rs)
+}
+
+// MACD retorna a linha MACD (EMA rápida - EMA lenta), a linha de sinal
+// (EMA da MACD) e o histograma
+func MACD(values []float64, fast, slow, signal int) (macd, sig, hist []float64) {
+	macd = nanSeries(len(values))
+	sig = nanSeries(len(values))
+	hist = nanSeries(len(values))
+	if fast <= 0 || slow <= 0 || signal <= 0 || fast >= slow {
+		return macd, sig, hist
+	}
+
+	fastEMA := EMA(values, fast)
+	slowEMA := EMA(values, slow)
+	for i := range values {
+		macd[i] = fastEMA[i] - slowEMA[i]
+	}
+
+	// A linha de sinal começa quando a MACD passa a existir
+	start := slow - 1
+	if start >= len(values) {
+		return macd, sig, hist
+	}
+	signalEMA := EMA(macd[start:], signal)
+	for i, v := range signalEMA {
+		sig[start+i] = v
+		hist[start+i] = macd[start+i] - v
+	}
+	return macd, sig, hist
+}
+
+// BollingerBands retorna as bandas superior, média e inferior
+// (SMA ± k desvios-padrão populacionais)
+func BollingerBands(values []float64, n int, k float64) (upper, middle, lower []float64) {
+	upper = nanSeries(len(values))
+	lower = nanSeries(len(values))
+	middle = SMA(values, n)
+
+	for i := range values {
+		if math.IsNaN(middle[i]) {
+			continue
+		}
+		variance := 0.0
+		for _, v := range values[i-n+1 : i+1] {
+			d := v - middle[i]
+			variance += d * d
+		}
+		std := math.Sqrt(variance / float64(n))
+		upper[i] = middle[i] + k*std
+		lower[i] = middle[i] - k*std
+	}
+	return upper, middle, lower
+}
+
+// ATR é o Average True Range de Wilder. O true range exige o fechamento
+// anterior, então o primeiro valor sai no índice n (convenção do TA-Lib).
+func ATR(high, low, close []float64, n int) []float64 {
+	out := nanSeries(len(close))
+	if n <= 0 || len(close) <= n || len(high) != len(close) || len(low) != len(close) {
+		return out
+	}
+
+	tr := make([]float64, len(close))
+	for i := 1; i < len(close); i++ {
+		tr[i] = math.Max(high[i]-low[i], math.Max(math.Abs(high[i]-close[i-1]), math.Abs(low[i]-close[i-1])))
+	}
+
+	sum := 0.0
+	for i := 1; i <= n; i++ {
+		sum += tr[i]
+	}
+	out[n] = sum / float64(n)
+	for i := n + 1; i < len(close); i++ {
+		out[i] = (out[i-1]*float64(n-1) + tr[i]) / float64(n)
+	}
+	return out
+}
+
+// OBV é o On-Balance Volume, iniciado com o volume do primeiro candle
+func OBV(close []float64, volume []float64) []float64 {
+	out := nanSeries(len(close))
+	if len(close) == 0 || len(volume) != len(close) {
+		return out
+	}
+
+	out[0] = volume[0]
+	for i := 1; i < len(close); i++ {
+		switch {
+		case close[i] > close[i-1]:
+			out[i] = out[i-1] + volume[i]
+		case close[i] < close[i-1]:
+			out[i] = out[i-1] - volume[i]
+		default:
+			out[i] = out[i-1]
+		}
+	}
+	return out
+}
+
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
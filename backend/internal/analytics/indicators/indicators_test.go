@@ -0,0 +1,277 @@
+package indicators
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"cotacoes/internal/domain"
+)
+
+var nan = math.NaN()
+
+// Série do exemplo de RSI da StockCharts (ChartSchool)
+var rsiCloses = []float64{
+	44.34, 44.09, 44.15, 43.61, 44.33, 44.83, 45.10, 45.42, 45.84, 46.08,
+	45.89, 46.03, 45.61, 46.28, 46.28, 46.00, 46.03, 46.41, 46.22, 45.64,
+}
+
+// Série do exemplo de EMA(10) da StockCharts (ChartSchool)
+var emaCloses = []float64{
+	22.27, 22.19, 22.08, 22.17, 22.18, 22.13, 22.23, 22.43, 22.24, 22.29,
+	22.15, 22.39, 22.38, 22.61, 23.36, 24.05, 23.75, 23.83, 23.95, 23.63,
+}
+
+func assertSeries(t *testing.T, name string, got, want []float64, tol float64) {
+	t.Helper()
+
+	if len(got) != len(want) {
+		t.Fatalf("%s: tamanho %d, esperado %d", name, len(got), len(want))
+	}
+	for i := range want {
+		switch {
+		case math.IsNaN(want[i]) && !math.IsNaN(got[i]):
+			t.Errorf("%s[%d] = %v, esperado aquecimento (NaN)", name, i, got[i])
+		case !math.IsNaN(want[i]) && math.Abs(got[i]-want[i]) > tol:
+			t.Errorf("%s[%d] = %.4f, esperado %.4f", name, i, got[i], want[i])
+		}
+	}
+}
+
+func TestSMA(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		n      int
+		want   []float64
+	}{
+		{"janela 3", []float64{1, 2, 3, 4, 5}, 3, []float64{nan, nan, 2, 3, 4}},
+		{"janela 1", []float64{5, 7}, 1, []float64{5, 7}},
+		{"série menor que a janela", []float64{1, 2}, 3, []float64{nan, nan}},
+		{"janela inválida", []float64{1, 2}, 0, []float64{nan, nan}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "sma", SMA(tt.values, tt.n), tt.want, 1e-9)
+		})
+	}
+}
+
+func TestEMA(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		n      int
+		want   []float64
+	}{
+		{
+			// Valores publicados pela StockCharts (arredondados em 2 casas)
+			name:   "stockcharts ema 10",
+			values: emaCloses,
+			n:      10,
+			want: []float64{
+				nan, nan, nan, nan, nan, nan, nan, nan, nan,
+				22.22, 22.21, 22.24, 22.27, 22.33, 22.52, 22.80, 22.97, 23.13, 23.28, 23.34,
+			},
+		},
+		{"série constante", []float64{3, 3, 3, 3}, 2, []float64{nan, 3, 3, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "ema", EMA(tt.values, tt.n), tt.want, 0.01)
+		})
+	}
+}
+
+func TestRSI(t *testing.T) {
+	warmup := []float64{nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan, nan}
+
+	tests := []struct {
+		name   string
+		values []float64
+		n      int
+		want   []float64
+		tol    float64
+	}{
+		{
+			// A StockCharts publica 70.53, 66.32, 66.55, 69.41, 66.36, 57.97
+			// porque arredonda as médias intermediárias; os valores abaixo
+			// são o cálculo de Wilder sem arredondamento.
+			name:   "stockcharts rsi 14",
+			values: rsiCloses,
+			n:      14,
+			want:   append(append([]float64{}, warmup...), 70.4641, 66.2496, 66.4809, 69.3469, 66.2947, 57.9150),
+			tol:    1e-3,
+		},
+		{"só altas", []float64{1, 2, 3, 4}, 2, []float64{nan, nan, 100, 100}, 1e-9},
+		{"só baixas", []float64{4, 3, 2, 1}, 2, []float64{nan, nan, 0, 0}, 1e-9},
+		{"sem variação", []float64{2, 2, 2}, 2, []float64{nan, nan, 50}, 1e-9},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "rsi", RSI(tt.values, tt.n), tt.want, tt.tol)
+		})
+	}
+}
+
+func TestMACD(t *testing.T) {
+	values := []float64{1, 2, 3, 5, 8, 13, 21, 34, 55, 89}
+
+	macd, sig, hist := MACD(values, 3, 5, 2)
+
+	wantMACD := []float64{nan, nan, nan, nan, 1.950000, 2.508333, 3.609722, 5.541898, 8.762307, 14.042059}
+	wantSignal := []float64{nan, nan, nan, nan, nan, 2.229167, 3.149537, 4.744444, 7.423020, 11.835712}
+	wantHist := make([]float64, len(values))
+	for i := range values {
+		wantHist[i] = wantMACD[i] - wantSignal[i]
+	}
+
+	assertSeries(t, "macd", macd, wantMACD, 1e-5)
+	assertSeries(t, "signal", sig, wantSignal, 1e-5)
+	assertSeries(t, "histogram", hist, wantHist, 1e-5)
+}
+
+func TestBollingerBands(t *testing.T) {
+	tests := []struct {
+		name                 string
+		values               []float64
+		n                    int
+		k                    float64
+		upper, middle, lower []float64
+	}{
+		{
+			name:   "janela 5, 2 desvios",
+			values: []float64{1, 2, 3, 4, 5, 6},
+			n:      5,
+			k:      2,
+			upper:  []float64{nan, nan, nan, nan, 5.828427, 6.828427},
+			middle: []float64{nan, nan, nan, nan, 3, 4},
+			lower:  []float64{nan, nan, nan, nan, 0.171573, 1.171573},
+		},
+		{
+			name:   "série constante",
+			values: []float64{10, 10, 10},
+			n:      2,
+			k:      2,
+			upper:  []float64{nan, 10, 10},
+			middle: []float64{nan, 10, 10},
+			lower:  []float64{nan, 10, 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upper, middle, lower := BollingerBands(tt.values, tt.n, tt.k)
+			assertSeries(t, "upper", upper, tt.upper, 1e-5)
+			assertSeries(t, "middle", middle, tt.middle, 1e-9)
+			assertSeries(t, "lower", lower, tt.lower, 1e-5)
+		})
+	}
+}
+
+func TestATR(t *testing.T) {
+	high := []float64{10, 11, 12, 11.5, 13, 12.5}
+	low := []float64{9, 10, 10.5, 10, 11.5, 11}
+	close := []float64{9.5, 10.5, 11.8, 10.2, 12.8, 11.2}
+
+	want := []float64{nan, nan, nan, 1.6, 2.0, 1.933333}
+	assertSeries(t, "atr", ATR(high, low, close, 3), want, 1e-5)
+}
+
+func TestOBV(t *testing.T) {
+	tests := []struct {
+		name   string
+		close  []float64
+		volume []float64
+		want   []float64
+	}{
+		{"alta, baixa e estável", []float64{10, 11, 10.5, 10.5, 12}, []float64{100, 200, 150, 80, 300}, []float64{100, 300, 150, 150, 450}},
+		{"volumes com tamanho diferente", []float64{1, 2}, []float64{1}, []float64{nan, nan}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, "obv", OBV(tt.close, tt.volume), tt.want, 1e-9)
+		})
+	}
+}
+
+func TestParseSet(t *testing.T) {
+	tests := []struct {
+		name    string
+		set     string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "exemplo completo",
+			set:  "sma:20,ema:50,rsi:14,macd,bbands:20:2,atr:14,obv",
+			want: []string{"sma:20", "ema:50", "rsi:14", "macd:12:26:9", "bbands:20:2", "atr:14", "obv"},
+		},
+		{name: "parâmetros padrão", set: "RSI, bbands", want: []string{"rsi:14", "bbands:20:2"}},
+		{name: "desvio fracionário", set: "bbands:20:2.5", want: []string{"bbands:20:2.5"}},
+		{name: "indicador desconhecido", set: "vwap", wantErr: true},
+		{name: "período fracionário", set: "sma:2.5", wantErr: true},
+		{name: "período negativo", set: "ema:-3", wantErr: true},
+		{name: "parâmetros demais", set: "rsi:14:2", wantErr: true},
+		{name: "macd invertida", set: "macd:26:12", wantErr: true},
+		{name: "vazio", set: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specs, err := ParseSet(tt.set)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidSpec) {
+					t.Fatalf("esperado ErrInvalidSpec, veio %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("erro inesperado: %v", err)
+			}
+			if len(specs) != len(tt.want) {
+				t.Fatalf("%d specs, esperado %d", len(specs), len(tt.want))
+			}
+			for i, s := range specs {
+				if s.String() != tt.want[i] {
+					t.Errorf("spec %d = %q, esperado %q", i, s.String(), tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestComputeGaps(t *testing.T) {
+	candles := []domain.HistoricalDataPrice{
+		{Date: 1, Close: 1},
+		{Date: 2, Close: 2},
+		{Date: 3, Close: 0}, // lacuna
+		{Date: 4, Close: 3},
+		{Date: 5, Close: 4},
+	}
+
+	result := Compute(candles, []Spec{{Name: "sma", Params: []float64{2}}})
+
+	if len(result.Dates) != len(candles) {
+		t.Fatalf("datas desalinhadas: %v", result.Dates)
+	}
+
+	line := result.Indicators[0].Lines["sma"]
+	want := []*float64{nil, ptr(1.5), nil, ptr(2.5), ptr(3.5)}
+	for i := range want {
+		switch {
+		case want[i] == nil && line[i] != nil:
+			t.Errorf("sma[%d] = %v, esperado null", i, *line[i])
+		case want[i] != nil && (line[i] == nil || math.Abs(*line[i]-*want[i]) > 1e-9):
+			t.Errorf("sma[%d] = %v, esperado %v", i, line[i], *want[i])
+		}
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
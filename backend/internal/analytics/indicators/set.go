@@ -0,0 +1,179 @@
+package indicators
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"cotacoes/internal/domain"
+)
+
+var ErrInvalidSpec = errors.New("invalid indicator spec")
+
+// Spec é um indicador pedido na query, ex: "bbands:20:2"
+type Spec struct {
+	Name   string
+	Params []float64
+}
+
+// String devolve a forma canônica, ex: "bbands:20:2"
+func (s Spec) String() string {
+	parts := []string{s.Name}
+	for _, p := range s.Params {
+		parts = append(parts, strconv.FormatFloat(p, 'f', -1, 64))
+	}
+	return strings.Join(parts, ":")
+}
+
+type definition struct {
+	defaults []float64
+	integer  []bool // parâmetros que precisam ser inteiros positivos
+}
+
+var definitions = map[string]definition{
+	"sma":    {defaults: []float64{20}, integer: []bool{true}},
+	"ema":    {defaults: []float64{20}, integer: []bool{true}},
+	"rsi":    {defaults: []float64{14}, integer: []bool{true}},
+	"macd":   {defaults: []float64{12, 26, 9}, integer: []bool{true, true, true}},
+	"bbands": {defaults: []float64{20, 2}, integer: []bool{true, false}},
+	"atr":    {defaults: []float64{14}, integer: []bool{true}},
+	"obv":    {},
+}
+
+// ParseSet interpreta "sma:20,ema:50,rsi:14,macd,bbands:20:2,atr:14,obv".
+// Parâmetros omitidos usam os valores padrão de cada indicador.
+func ParseSet(set string) ([]Spec, error) {
+	specs := make([]Spec, 0)
+	for _, item := range strings.Split(set, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		parts := strings.Split(item, ":")
+		name := strings.ToLower(parts[0])
+		def, ok := definitions[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: indicador desconhecido %q", ErrInvalidSpec, parts[0])
+		}
+		if len(parts)-1 > len(def.defaults) {
+			return nil, fmt.Errorf("%w: %q aceita no máximo %d parâmetros", ErrInvalidSpec, name, len(def.defaults))
+		}
+
+		params := append([]float64(nil), def.defaults...)
+		for i, raw := range parts[1:] {
+			v, err := strconv.ParseFloat(raw, 64)
+			if err != nil || v <= 0 || math.IsInf(v, 0) {
+				return nil, fmt.Errorf("%w: parâmetro inválido %q em %q", ErrInvalidSpec, raw, item)
+			}
+			if def.integer[i] && v != math.Trunc(v) {
+				return nil, fmt.Errorf("%w: parâmetro %q em %q deve ser inteiro", ErrInvalidSpec, raw, item)
+			}
+			params[i] = v
+		}
+
+		if name == "macd" && params[0] >= params[1] {
+			return nil, fmt.Errorf("%w: em %q a média rápida deve ser menor que a lenta", ErrInvalidSpec, item)
+		}
+
+		specs = append(specs, Spec{Name: name, Params: params})
+	}
+
+	if len(specs) == 0 {
+		return nil, fmt.Errorf("%w: nenhum indicador informado", ErrInvalidSpec)
+	}
+	return specs, nil
+}
+
+// Series é o resultado de um indicador; cada linha é alinhada às datas
+// dos candles, com null no aquecimento e nas lacunas
+type Series struct {
+	Key    string                `json:"key"`
+	Name   string                `json:"name"`
+	Params []float64             `json:"params"`
+	Lines  map[string][]*float64 `json:"lines"`
+}
+
+// Result agrupa as datas dos candles e as séries calculadas
+type Result struct {
+	Dates      []int64  `json:"dates"`
+	Indicators []Series `json:"indicators"`
+}
+
+// Compute calcula os indicadores sobre os candles. Candles sem preço
+// (fechamento zero) são tratados como lacunas: ficam null na saída e não
+// entram no cálculo.
+func Compute(candles []domain.HistoricalDataPrice, specs []Spec) Result {
+	dates := make([]int64, len(candles))
+	valid := make([]int, 0, len(candles)) // índices dos candles com preço
+	for i, c := range candles {
+		dates[i] = c.Date
+		if c.Close > 0 {
+			valid = append(valid, i)
+		}
+	}
+
+	high := make([]float64, len(valid))
+	low := make([]float64, len(valid))
+	close := make([]float64, len(valid))
+	volume := make([]float64, len(valid))
+	for j, i := range valid {
+		c := candles[i]
+		close[j] = c.Close
+		high[j] = c.High
+		low[j] = c.Low
+		if high[j] == 0 {
+			high[j] = c.Close
+		}
+		if low[j] == 0 {
+			low[j] = c.Close
+		}
+		volume[j] = float64(c.Volume)
+	}
+
+	align := func(values []float64) []*float64 {
+		out := make([]*float64, len(candles))
+		for j, i := range valid {
+			if !math.IsNaN(values[j]) {
+				v := values[j]
+				out[i] = &v
+			}
+		}
+		return out
+	}
+
+	result := Result{Dates: dates, Indicators: make([]Series, 0, len(specs))}
+	for _, spec := range specs {
+		s := Series{Key: spec.String(), Name: spec.Name, Params: spec.Params, Lines: make(map[string][]*float64)}
+		p := func(i int) int { return int(spec.Params[i]) }
+
+		switch spec.Name {
+		case "sma":
+			s.Lines["sma"] = align(SMA(close, p(0)))
+		case "ema":
+			s.Lines["ema"] = align(EMA(close, p(0)))
+		case "rsi":
+			s.Lines["rsi"] = align(RSI(close, p(0)))
+		case "macd":
+			macd, sig, hist := MACD(close, p(0), p(1), p(2))
+			s.Lines["macd"] = align(macd)
+			s.Lines["signal"] = align(sig)
+			s.Lines["histogram"] = align(hist)
+		case "bbands":
+			upper, middle, lower := BollingerBands(close, p(0), spec.Params[1])
+			s.Lines["upper"] = align(upper)
+			s.Lines["middle"] = align(middle)
+			s.Lines["lower"] = align(lower)
+		case "atr":
+			s.Lines["atr"] = align(ATR(high, low, close, p(0)))
+		case "obv":
+			s.Lines["obv"] = align(OBV(close, volume))
+		}
+
+		result.Indicators = append(result.Indicators, s)
+	}
+
+	return result
+}
@@ -0,0 +1,270 @@
+package irpf
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"cotacoes/internal/calendar"
+)
+
+// Operation é uma venda com resultado apurado e classe do ativo
+type Operation struct {
+	Date     string // AAAA-MM-DD
+	Symbol   string
+	Class    string
+	DayTrade bool
+	Gross    float64 // valor de venda
+	Result   float64 // resultado líquido de taxas
+}
+
+// CategoryResult é o resultado de uma classe/modalidade no mês
+type CategoryResult struct {
+	Class    string  `json:"class"`
+	DayTrade bool    `json:"dayTrade"`
+	LossPool string  `json:"lossPool"`
+	Rate     float64 `json:"rate"`
+	Sales    float64 `json:"sales"`
+	Result   float64 `json:"result"`
+	// Exempt indica ganho isento pelo limite de vendas do mês
+	Exempt      bool    `json:"exempt"`
+	LossOffset  float64 `json:"lossOffset"`
+	TaxableBase float64 `json:"taxableBase"`
+	Tax         float64 `json:"tax"`
+	Withheld    float64 `json:"withheld"`
+}
+
+// Month é a apuração de um mês
+type Month struct {
+	Month       string           `json:"month"` // AAAA-MM
+	Categories  []CategoryResult `json:"categories"`
+	ExemptGains float64          `json:"exemptGains"`
+	Tax         float64          `json:"tax"`
+	// Withheld é o IRRF do mês; WithheldUsed o IRRF (do mês e de meses
+	// anteriores) descontado do imposto
+	Withheld     float64 `json:"withheld"`
+	WithheldUsed float64 `json:"withheldUsed"`
+	// CarriedIn é o imposto abaixo do mínimo acumulado de meses anteriores
+	CarriedIn  float64 `json:"carriedIn"`
+	DarfAmount float64 `json:"darfAmount"`
+	DarfCode   string  `json:"darfCode,omitempty"`
+	DueDate    string  `json:"dueDate,omitempty"`
+	// Deferred é o valor abaixo do mínimo levado ao mês seguinte
+	Deferred float64 `json:"deferred"`
+	// LossCarryforward é o prejuízo a compensar por grupo ao fim do mês
+	LossCarryforward map[string]float64 `json:"lossCarryforward"`
+	WithheldCarry    float64            `json:"withheldCarry"`
+}
+
+// Report é a apuração de todos os meses com vendas, em ordem
+type Report struct {
+	Months []Month
+}
+
+// Compute apura mês a mês as operações. Prejuízos são levados adiante
+// por grupo sem prazo; o IRRF não usado é compensado nos meses seguintes
+// do mesmo ano.
+func Compute(ops []Operation, rules Rules) (*Report, error) {
+	byMonth := make(map[string][]Operation)
+	for _, op := range ops {
+		if len(op.Date) < 7 {
+			return nil, fmt.Errorf("data inválida na operação de %s: %q", op.Symbol, op.Date)
+		}
+		month := op.Date[:7]
+		byMonth[month] = append(byMonth[month], op)
+	}
+	months := make([]string, 0, len(byMonth))
+	for month := range byMonth {
+		months = append(months, month)
+	}
+	sort.Strings(months)
+
+	report := &Report{}
+	losses := make(map[string]float64)
+	var withheldCarry, deferred float64
+	lastYear := ""
+
+	for _, month := range months {
+		start, err := time.ParseInLocation("2006-01", month, calendar.Location)
+		if err != nil {
+			return nil, fmt.Errorf("mês inválido: %q", month)
+		}
+		if year := month[:4]; year != lastYear {
+			withheldCarry = 0
+			lastYear = year
+		}
+
+		categories, err := categorize(byMonth[month], rules, start)
+		if err != nil {
+			return nil, err
+		}
+
+		m := Month{Month: month, Categories: categories}
+
+		// Isenção pelo limite de vendas
+		for i := range m.Categories {
+			c := &m.Categories[i]
+			rule, _ := rules.RuleFor(c.Class, c.DayTrade, start)
+			if rule.ExemptionLimit > 0 && c.Sales <= rule.ExemptionLimit && c.Result > 0 {
+				c.Exempt = true
+				m.ExemptGains += c.Result
+			}
+		}
+
+		// Compensação de prejuízos dentro de cada grupo: prejuízos do mês e
+		// acumulados abatem os ganhos tributáveis, na ordem das regras
+		for _, pool := range pools(m.Categories) {
+			available := losses[pool]
+			for _, c := range m.Categories {
+				if c.LossPool == pool && c.Result < 0 {
+					available += -c.Result
+				}
+			}
+			for i := range m.Categories {
+				c := &m.Categories[i]
+				if c.LossPool != pool || c.Exempt || c.Result <= 0 {
+					continue
+				}
+				c.LossOffset = math.Min(available, c.Result)
+				available -= c.LossOffset
+				c.TaxableBase = c.Result - c.LossOffset
+				c.Tax = round(c.TaxableBase * c.Rate)
+				m.Tax += c.Tax
+			}
+			losses[pool] = round(available)
+		}
+
+		for _, c := range m.Categories {
+			m.Withheld += c.Withheld
+		}
+		m.Tax = round(m.Tax)
+		m.Withheld = round(m.Withheld)
+
+		available := withheldCarry + m.Withheld
+		m.WithheldUsed = round(math.Min(available, m.Tax))
+		withheldCarry = round(available - m.WithheldUsed)
+
+		m.CarriedIn = deferred
+		payable := round(m.Tax - m.WithheldUsed + deferred)
+		if payable < rules.DarfMinimum {
+			m.Deferred = payable
+		} else {
+			m.DarfAmount = payable
+			m.DarfCode = rules.DarfCode
+			m.DueDate = DueDate(start).Format("2006-01-02")
+		}
+		deferred = m.Deferred
+
+		m.LossCarryforward = make(map[string]float64, len(losses))
+		for pool, v := range losses {
+			m.LossCarryforward[pool] = v
+		}
+		m.WithheldCarry = withheldCarry
+
+		report.Months = append(report.Months, m)
+	}
+
+	return report, nil
+}
+
+// DueDate é o vencimento do DARF: último dia útil do mês seguinte
+func DueDate(month time.Time) time.Time {
+	firstOfNext := time.Date(month.Year(), month.Month()+2, 1, 0, 0, 0, 0, calendar.Location)
+	return calendar.Default.PreviousTradingDay(firstOfNext)
+}
+
+// categorize agrupa as operações do mês por classe e modalidade e calcula
+// o IRRF: no swing trade sobre o valor de venda de cada dia (dispensado
+// até o mínimo); no day trade sobre o resultado positivo de cada dia
+func categorize(ops []Operation, rules Rules, month time.Time) ([]CategoryResult, error) {
+	type key struct {
+		class    string
+		dayTrade bool
+	}
+	type dayKey struct {
+		key
+		date string
+	}
+
+	index := make(map[key]int)
+	var categories []CategoryResult
+	daily := make(map[dayKey]float64)
+	var days []dayKey
+
+	for _, op := range ops {
+		k := key{op.Class, op.DayTrade}
+		i, ok := index[k]
+		if !ok {
+			rule, found := rules.RuleFor(op.Class, op.DayTrade, month)
+			if !found {
+				return nil, fmt.Errorf("sem regra de IR para a classe %q (dayTrade=%v)", op.Class, op.DayTrade)
+			}
+			i = len(categories)
+			index[k] = i
+			categories = append(categories, CategoryResult{
+				Class:    op.Class,
+				DayTrade: op.DayTrade,
+				LossPool: rule.LossPool,
+				Rate:     rule.Rate,
+			})
+		}
+		categories[i].Sales += op.Gross
+		categories[i].Result += op.Result
+
+		dk := dayKey{k, op.Date}
+		if _, ok := daily[dk]; !ok {
+			days = append(days, dk)
+		}
+		if op.DayTrade {
+			daily[dk] += op.Result
+		} else {
+			daily[dk] += op.Gross
+		}
+	}
+
+	for _, dk := range days {
+		rule, _ := rules.RuleFor(dk.class, dk.dayTrade, month)
+		base := daily[dk]
+		if base <= 0 {
+			continue
+		}
+		withheld := round(base * rule.Withholding)
+		if !dk.dayTrade && withheld <= rules.WithholdingMinimum {
+			continue
+		}
+		categories[index[dk.key]].Withheld += withheld
+	}
+
+	for i := range categories {
+		c := &categories[i]
+		c.Sales = round(c.Sales)
+		c.Result = round(c.Result)
+		c.Withheld = round(c.Withheld)
+	}
+	sort.SliceStable(categories, func(i, j int) bool {
+		if categories[i].DayTrade != categories[j].DayTrade {
+			return !categories[i].DayTrade
+		}
+		return categories[i].Class < categories[j].Class
+	})
+	return categories, nil
+}
+
+// pools lista os grupos de compensação presentes, em ordem estável
+func pools(categories []CategoryResult) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, c := range categories {
+		if !seen[c.LossPool] {
+			seen[c.LossPool] = true
+			out = append(out, c.LossPool)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,216 @@
+package irpf
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+func stock(date string, gross, result float64) Operation {
+	return Operation{Date: date, Symbol: "PETR4", Class: domain.AssetStock, Gross: gross, Result: result}
+}
+
+func fii(date string, gross, result float64) Operation {
+	return Operation{Date: date, Symbol: "HGLG11", Class: domain.AssetFII, Gross: gross, Result: result}
+}
+
+func dayTrade(date string, gross, result float64) Operation {
+	return Operation{Date: date, Symbol: "VALE3", Class: domain.AssetStock, DayTrade: true, Gross: gross, Result: result}
+}
+
+// monthWant é o esperado de um mês da apuração
+type monthWant struct {
+	month      string
+	tax        float64
+	withheld   float64
+	exempt     float64
+	darf       float64
+	deferred   float64
+	carriedIn  float64
+	dueDate    string
+	lossCommon float64
+	lossFII    float64
+}
+
+func assertClose(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 0.005 {
+		t.Errorf("%s = %.2f, esperado %.2f", name, got, want)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	tests := []struct {
+		name string
+		ops  []Operation
+		want []monthWant
+	}{
+		{
+			name: "isenção com vendas de exatamente R$ 20 mil",
+			ops:  []Operation{stock("2024-03-05", 20000, 1000)},
+			want: []monthWant{{month: "2024-03", exempt: 1000}},
+		},
+		{
+			name: "vendas acima de R$ 20 mil tributadas; IRRF até R$ 1 dispensado",
+			ops:  []Operation{stock("2024-03-05", 20000.01, 1000)},
+			want: []monthWant{{month: "2024-03", tax: 150, darf: 150, dueDate: "2024-04-30"}},
+		},
+		{
+			name: "IRRF de 0,005% acima de R$ 1 descontado do imposto",
+			ops:  []Operation{stock("2024-03-05", 30000, 1000)},
+			want: []monthWant{{month: "2024-03", tax: 150, withheld: 1.5, darf: 148.5, dueDate: "2024-04-30"}},
+		},
+		{
+			name: "IRRF de 1% no day trade sobre o resultado do dia",
+			ops:  []Operation{dayTrade("2024-03-05", 50000, 1000)},
+			want: []monthWant{{month: "2024-03", tax: 200, withheld: 10, darf: 190, dueDate: "2024-04-30"}},
+		},
+		{
+			name: "prejuízos compensados entre anos e apenas no mesmo grupo",
+			ops: []Operation{
+				stock("2023-11-10", 30000, -500),
+				fii("2023-11-10", 10000, -300),
+				stock("2024-02-06", 30000, 2000),
+				fii("2024-03-06", 10000, 1000),
+			},
+			want: []monthWant{
+				// IRRF de 2023 (1,50) não passa para 2024
+				{month: "2023-11", withheld: 1.5, lossCommon: 500, lossFII: 300},
+				// Fevereiro de 2024 vence em 28/03: 29/03 é Sexta-feira Santa
+				{month: "2024-02", tax: 225, withheld: 1.5, darf: 223.5, dueDate: "2024-03-28", lossFII: 300},
+				{month: "2024-03", tax: 140, darf: 140, dueDate: "2024-04-30"},
+			},
+		},
+		{
+			name: "prejuízo de mês isento continua compensável",
+			ops: []Operation{
+				stock("2024-01-10", 5000, -400),
+				stock("2024-02-06", 25000, 1000),
+			},
+			want: []monthWant{
+				{month: "2024-01", lossCommon: 400},
+				{month: "2024-02", tax: 90, withheld: 1.25, darf: 88.75, dueDate: "2024-03-28"},
+			},
+		},
+		{
+			name: "DARF abaixo de R$ 10 acumulado para o mês seguinte",
+			ops: []Operation{
+				stock("2024-04-10", 20001, 40),
+				stock("2024-05-10", 20001, 40),
+			},
+			want: []monthWant{
+				{month: "2024-04", tax: 6, deferred: 6},
+				{month: "2024-05", tax: 6, carriedIn: 6, darf: 12, dueDate: "2024-06-28"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := Compute(tt.ops, DefaultRules())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(report.Months) != len(tt.want) {
+				t.Fatalf("%d meses, esperado %d", len(report.Months), len(tt.want))
+			}
+			for i, want := range tt.want {
+				got := report.Months[i]
+				if got.Month != want.month {
+					t.Fatalf("mês %d = %s, esperado %s", i, got.Month, want.month)
+				}
+				assertClose(t, want.month+" tax", got.Tax, want.tax)
+				assertClose(t, want.month+" withheld", got.Withheld, want.withheld)
+				assertClose(t, want.month+" exemptGains", got.ExemptGains, want.exempt)
+				assertClose(t, want.month+" darf", got.DarfAmount, want.darf)
+				assertClose(t, want.month+" deferred", got.Deferred, want.deferred)
+				assertClose(t, want.month+" carriedIn", got.CarriedIn, want.carriedIn)
+				assertClose(t, want.month+" loss common", got.LossCarryforward[PoolCommon], want.lossCommon)
+				assertClose(t, want.month+" loss fii", got.LossCarryforward[PoolFII], want.lossFII)
+				if got.DueDate != want.dueDate {
+					t.Errorf("%s dueDate = %q, esperado %q", want.month, got.DueDate, want.dueDate)
+				}
+			}
+		})
+	}
+}
+
+func TestComputeWithheldCarryWithinYear(t *testing.T) {
+	// IRRF de um mês sem imposto abate o imposto do mês seguinte
+	report, err := Compute([]Operation{
+		stock("2024-01-10", 40000, -100),
+		stock("2024-02-06", 25000, 1100),
+	}, DefaultRules())
+	if err != nil {
+		t.Fatal(err)
+	}
+	jan, feb := report.Months[0], report.Months[1]
+	assertClose(t, "jan withheldCarry", jan.WithheldCarry, 2)
+	// (1100 - 100) * 15% = 150; IRRF 2,00 de janeiro + 1,25 de fevereiro
+	assertClose(t, "feb tax", feb.Tax, 150)
+	assertClose(t, "feb withheldUsed", feb.WithheldUsed, 3.25)
+	assertClose(t, "feb darf", feb.DarfAmount, 146.75)
+}
+
+func TestComputeUnknownClass(t *testing.T) {
+	_, err := Compute([]Operation{{Date: "2024-01-10", Symbol: "XPTO", Class: "crypto", Gross: 1, Result: 1}}, DefaultRules())
+	if err == nil {
+		t.Fatal("esperado erro para classe sem regra")
+	}
+}
+
+func TestDueDate(t *testing.T) {
+	tests := []struct {
+		month string
+		want  string
+	}{
+		{"2023-12", "2024-01-31"},
+		{"2024-02", "2024-03-28"}, // Sexta-feira Santa em 29/03
+		{"2024-08", "2024-09-30"},
+		{"2024-10", "2024-11-29"}, // 30/11 é sábado
+	}
+	for _, tt := range tests {
+		month, _ := time.ParseInLocation("2006-01", tt.month, calendar.Location)
+		if got := DueDate(month).Format("2006-01-02"); got != tt.want {
+			t.Errorf("DueDate(%s) = %s, esperado %s", tt.month, got, tt.want)
+		}
+	}
+}
+
+func TestRulesOverride(t *testing.T) {
+	overrides, err := ParseRules([]byte(`{
+		"categories": [
+			{"class": "stock", "rate": 0.175, "exemptionLimit": 20000, "withholding": 0.00005,
+			 "lossPool": "common", "effectiveFrom": "2025-01-01T00:00:00-03:00"}
+		],
+		"darfMinimum": 20
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules := DefaultRules().Merge(overrides)
+
+	report, err := Compute([]Operation{
+		stock("2024-12-10", 30000, 1000),
+		stock("2025-01-10", 30000, 1000),
+	}, rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// A nova alíquota vale só a partir da vigência
+	assertClose(t, "2024-12 tax", report.Months[0].Tax, 150)
+	assertClose(t, "2025-01 tax", report.Months[1].Tax, 175)
+	if rules.DarfMinimum != 20 || rules.WithholdingMinimum != 1 {
+		t.Errorf("mínimos = %v/%v, esperado 20/1", rules.DarfMinimum, rules.WithholdingMinimum)
+	}
+
+	if _, err := ParseRules([]byte(`{"categories": [{"class": "crypto", "rate": 0.15, "lossPool": "x"}]}`)); err == nil {
+		t.Error("esperado erro para classe desconhecida")
+	}
+	if _, err := ParseRules([]byte(`{"categories": [{"class": "stock", "rate": 0.15}]}`)); err == nil {
+		t.Error("esperado erro sem lossPool")
+	}
+}
@@ -0,0 +1,150 @@
+// Package irpf apura o imposto de renda mensal sobre ganhos em renda
+// variável (B3) a partir das vendas da carteira: separa swing trade e day
+// trade, aplica a isenção de vendas, compensa prejuízos por categoria,
+// desconta o IRRF ("dedo-duro") e calcula o DARF do mês.
+//
+// As alíquotas, limites e grupos de compensação vêm de Rules, que podem
+// ser sobrescritas por um arquivo JSON sem mudança de código.
+package irpf
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// Rule define a tributação de uma classe de ativo em uma modalidade
+// (swing trade ou day trade) a partir de uma data
+type Rule struct {
+	Class    string  `json:"class"`
+	DayTrade bool    `json:"dayTrade"`
+	Rate     float64 `json:"rate"`
+	// ExemptionLimit é o limite de vendas no mês até o qual o ganho é
+	// isento (0 = sem isenção)
+	ExemptionLimit float64 `json:"exemptionLimit,omitempty"`
+	// Withholding é a alíquota do IRRF: sobre o valor de venda no swing
+	// trade e sobre o resultado positivo no day trade
+	Withholding float64 `json:"withholding"`
+	// LossPool agrupa as categorias cujos prejuízos se compensam entre si
+	LossPool      string    `json:"lossPool"`
+	EffectiveFrom time.Time `json:"effectiveFrom"`
+}
+
+// Rules é o conjunto de regras de apuração
+type Rules struct {
+	Categories []Rule `json:"categories"`
+	// DarfMinimum: valores menores são acumulados para o mês seguinte
+	DarfMinimum float64 `json:"darfMinimum"`
+	// WithholdingMinimum: IRRF de swing trade até esse valor no dia não é retido
+	WithholdingMinimum float64 `json:"withholdingMinimum"`
+	DarfCode           string  `json:"darfCode"`
+}
+
+// Grupos de compensação de prejuízos
+const (
+	PoolCommon   = "common"   // operações comuns (ações, ETFs, BDRs)
+	PoolDayTrade = "daytrade" // day trade (ações, ETFs, BDRs)
+	PoolFII      = "fii"      // fundos imobiliários
+)
+
+// DefaultRules segue a legislação vigente para pessoa física:
+//   - ações: 15% no swing trade, isento com vendas de até R$ 20 mil no mês;
+//   - day trade: 20%, IRRF de 1% sobre o resultado;
+//   - FIIs: 20% sem isenção, prejuízos compensáveis apenas com FIIs;
+//   - ETFs e BDRs: 15%/20% sem isenção;
+//   - IRRF de 0,005% sobre vendas no swing trade, dispensado até R$ 1;
+//   - DARF (código 6015) abaixo de R$ 10 é acumulado.
+func DefaultRules() Rules {
+	return Rules{
+		Categories: []Rule{
+			{Class: domain.AssetStock, Rate: 0.15, ExemptionLimit: 20000, Withholding: 0.00005, LossPool: PoolCommon},
+			{Class: domain.AssetStock, DayTrade: true, Rate: 0.20, Withholding: 0.01, LossPool: PoolDayTrade},
+			{Class: domain.AssetETF, Rate: 0.15, Withholding: 0.00005, LossPool: PoolCommon},
+			{Class: domain.AssetETF, DayTrade: true, Rate: 0.20, Withholding: 0.01, LossPool: PoolDayTrade},
+			{Class: domain.AssetBDR, Rate: 0.15, Withholding: 0.00005, LossPool: PoolCommon},
+			{Class: domain.AssetBDR, DayTrade: true, Rate: 0.20, Withholding: 0.01, LossPool: PoolDayTrade},
+			{Class: domain.AssetFII, Rate: 0.20, Withholding: 0.00005, LossPool: PoolFII},
+			{Class: domain.AssetFII, DayTrade: true, Rate: 0.20, Withholding: 0.01, LossPool: PoolFII},
+		},
+		DarfMinimum:        10,
+		WithholdingMinimum: 1,
+		DarfCode:           "6015",
+	}
+}
+
+// ParseRules interpreta overrides em JSON no mesmo formato de Rules.
+// Campos numéricos globais ausentes (zero) mantêm o valor padrão.
+func ParseRules(data []byte) (Rules, error) {
+	var rules Rules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return Rules{}, fmt.Errorf("regras de IR inválidas: %w", err)
+	}
+	for i, rule := range rules.Categories {
+		switch rule.Class {
+		case domain.AssetStock, domain.AssetFII, domain.AssetETF, domain.AssetBDR:
+		default:
+			return Rules{}, fmt.Errorf("regra de IR %d: classe %q desconhecida", i+1, rule.Class)
+		}
+		if rule.Rate < 0 || rule.Rate >= 1 || rule.Withholding < 0 || rule.Withholding >= 1 {
+			return Rules{}, fmt.Errorf("regra de IR %d: alíquota inválida", i+1)
+		}
+		if rule.ExemptionLimit < 0 {
+			return Rules{}, fmt.Errorf("regra de IR %d: exemptionLimit inválido", i+1)
+		}
+		if rule.LossPool == "" {
+			return Rules{}, fmt.Errorf("regra de IR %d: informe lossPool", i+1)
+		}
+	}
+	return rules, nil
+}
+
+// Merge devolve as regras atuais com os overrides aplicados; para a mesma
+// classe, modalidade e vigência, o override prevalece
+func (r Rules) Merge(overrides Rules) Rules {
+	merged := r
+	merged.Categories = make([]Rule, 0, len(r.Categories)+len(overrides.Categories))
+	for _, rule := range r.Categories {
+		replaced := false
+		for _, o := range overrides.Categories {
+			if o.Class == rule.Class && o.DayTrade == rule.DayTrade && o.EffectiveFrom.Equal(rule.EffectiveFrom) {
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			merged.Categories = append(merged.Categories, rule)
+		}
+	}
+	merged.Categories = append(merged.Categories, overrides.Categories...)
+
+	if overrides.DarfMinimum > 0 {
+		merged.DarfMinimum = overrides.DarfMinimum
+	}
+	if overrides.WithholdingMinimum > 0 {
+		merged.WithholdingMinimum = overrides.WithholdingMinimum
+	}
+	if overrides.DarfCode != "" {
+		merged.DarfCode = overrides.DarfCode
+	}
+	return merged
+}
+
+// RuleFor retorna a regra vigente para a classe e modalidade na data
+func (r Rules) RuleFor(class string, dayTrade bool, date time.Time) (Rule, bool) {
+	candidates := make([]Rule, 0)
+	for _, rule := range r.Categories {
+		if rule.Class == class && rule.DayTrade == dayTrade && !rule.EffectiveFrom.After(date) {
+			candidates = append(candidates, rule)
+		}
+	}
+	if len(candidates) == 0 {
+		return Rule{}, false
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].EffectiveFrom.Before(candidates[j].EffectiveFrom)
+	})
+	return candidates[len(candidates)-1], true
+}
@@ -0,0 +1,117 @@
+package market
+
+import (
+	"fmt"
+	"strings"
+
+	"cotacoes/internal/domain"
+)
+
+// Tipos de lista de /market/movers
+const (
+	MoversGainers = "gainers"
+	MoversLosers  = "losers"
+	MoversActive  = "active"
+)
+
+// ParseMoversKind valida o tipo de lista
+func ParseMoversKind(kind string) (string, error) {
+	switch k := strings.ToLower(strings.TrimSpace(kind)); k {
+	case "":
+		return MoversGainers, nil
+	case MoversGainers, MoversLosers, MoversActive:
+		return k, nil
+	}
+	return "", fmt.Errorf("tipo %q inválido (use gainers, losers ou active)", kind)
+}
+
+// Movers retorna as maiores altas, baixas ou os mais negociados
+func Movers(items []domain.StockListItem, kind string, limit int) []Mover {
+	switch kind {
+	case MoversLosers:
+		return Top(items, limit,
+			func(a, b domain.StockListItem) bool { return a.Change < b.Change },
+			func(i domain.StockListItem) bool { return i.Change < 0 })
+	case MoversActive:
+		return Top(items, limit,
+			func(a, b domain.StockListItem) bool { return a.Volume > b.Volume },
+			nil)
+	}
+	return Top(items, limit,
+		func(a, b domain.StockListItem) bool { return a.Change > b.Change },
+		func(i domain.StockListItem) bool { return i.Change > 0 })
+}
+
+// Range é a máxima e a mínima (intradiárias) das 52 semanas anteriores ao
+// pregão atual
+type Range struct {
+	High float64
+	Low  float64
+}
+
+// Breadth mede a amplitude do mercado: quantos ativos subiram ou caíram e
+// quanto do volume foi negociado nos que subiram
+type Breadth struct {
+	Total     int `json:"total"`
+	Advancers int `json:"advancers"`
+	Decliners int `json:"decliners"`
+	Unchanged int `json:"unchanged"`
+
+	AdvanceDeclineRatio *float64 `json:"advanceDeclineRatio"`
+
+	// Novas máximas/mínimas de 52 semanas, contadas apenas entre os
+	// RangeCoverage ativos com histórico local suficiente; com cobertura
+	// menor que Total, os números subestimam o mercado
+	NewHighs      int `json:"newHighs"`
+	NewLows       int `json:"newLows"`
+	RangeCoverage int `json:"rangeCoverage"`
+
+	AdvancingVolume int64    `json:"advancingVolume"`
+	DecliningVolume int64    `json:"decliningVolume"`
+	UnchangedVolume int64    `json:"unchangedVolume"`
+	UpVolumeShare   *float64 `json:"upVolumeShare"`
+}
+
+// ComputeBreadth calcula a amplitude; ranges traz a faixa de 52 semanas
+// dos ativos que a tiverem
+func ComputeBreadth(items []domain.StockListItem, ranges map[string]Range) Breadth {
+	b := Breadth{Total: len(items)}
+
+	var totalVolume int64
+	for _, item := range items {
+		volume := max(item.Volume, 0)
+		totalVolume += volume
+
+		switch {
+		case item.Change > 0:
+			b.Advancers++
+			b.AdvancingVolume += volume
+		case item.Change < 0:
+			b.Decliners++
+			b.DecliningVolume += volume
+		default:
+			b.Unchanged++
+			b.UnchangedVolume += volume
+		}
+
+		r, ok := ranges[item.Stock]
+		if !ok || item.Close <= 0 {
+			continue
+		}
+		b.RangeCoverage++
+		if item.Close > r.High {
+			b.NewHighs++
+		}
+		if item.Close < r.Low {
+			b.NewLows++
+		}
+	}
+
+	if b.Decliners > 0 {
+		b.AdvanceDeclineRatio = number(float64(b.Advancers) / float64(b.Decliners))
+	}
+	if totalVolume > 0 {
+		b.UpVolumeShare = number(float64(b.AdvancingVolume) / float64(totalVolume))
+	}
+	return b
+}
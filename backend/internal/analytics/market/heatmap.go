@@ -0,0 +1,282 @@
+package market
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"cotacoes/internal/domain"
+)
+
+// Opções do heatmap
+const (
+	GroupBySector = "sector"
+	GroupByType   = "type"
+
+	SizeByMarketCap = "market_cap"
+	SizeByVolume    = "volume"
+	SizeByEqual     = "equal"
+
+	ColorByChange = "change"
+
+	// OthersName é o nome do grupo que junta as folhas além do limite
+	OthersName = "Outros"
+)
+
+// HeatmapOptions configura a árvore. MaxLeaves é o máximo de nós por
+// grupo, já contando o "Outros". ColorRange é a variação (em %) que satura
+// a cor: -ColorRange fica vermelho puro e +ColorRange verde puro.
+type HeatmapOptions struct {
+	GroupBy    string  `json:"groupBy"`
+	SizeBy     string  `json:"sizeBy"`
+	ColorBy    string  `json:"colorBy"`
+	MaxLeaves  int     `json:"maxLeaves"`
+	ColorRange float64 `json:"colorRange"`
+}
+
+// Validate confere as opções e preenche os padrões
+func (o *HeatmapOptions) Validate() error {
+	o.GroupBy = strings.ToLower(o.GroupBy)
+	o.SizeBy = strings.ToLower(o.SizeBy)
+	o.ColorBy = strings.ToLower(o.ColorBy)
+
+	switch o.GroupBy {
+	case "":
+		o.GroupBy = GroupBySector
+	case GroupBySector, GroupByType:
+	default:
+		return fmt.Errorf("groupBy %q inválido (use sector ou type)", o.GroupBy)
+	}
+	switch o.SizeBy {
+	case "":
+		o.SizeBy = SizeByMarketCap
+	case SizeByMarketCap, SizeByVolume, SizeByEqual:
+	default:
+		return fmt.Errorf("sizeBy %q inválido (use market_cap, volume ou equal)", o.SizeBy)
+	}
+	switch o.ColorBy {
+	case "":
+		o.ColorBy = ColorByChange
+	case ColorByChange:
+	default:
+		return fmt.Errorf("colorBy %q inválido (use change)", o.ColorBy)
+	}
+	switch {
+	case o.MaxLeaves == 0:
+		o.MaxLeaves = 30
+	case o.MaxLeaves < 1 || o.MaxLeaves > 500:
+		return fmt.Errorf("maxLeaves deve estar entre 1 e 500")
+	}
+	switch {
+	case o.ColorRange == 0:
+		o.ColorRange = 3
+	case o.ColorRange < 0 || math.IsNaN(o.ColorRange) || math.IsInf(o.ColorRange, 0):
+		return fmt.Errorf("colorRange deve ser um número positivo")
+	}
+	return nil
+}
+
+// HeatmapNode é um nó da árvore (mercado → grupo → ativo). Size é o
+// tamanho absoluto e Weight a fração do nó pai; Value é a métrica de cor
+// (média ponderada pelo tamanho nos grupos) e Percentile a posição de
+// Value entre todas as folhas (0 a 100).
+type HeatmapNode struct {
+	Name       string         `json:"name"`
+	Symbol     string         `json:"symbol,omitempty"`
+	Logo       string         `json:"logo,omitempty"`
+	Size       float64        `json:"size"`
+	Weight     float64        `json:"weight"`
+	Value      float64        `json:"value"`
+	Color      string         `json:"color"`
+	Percentile float64        `json:"percentile"`
+	Count      int            `json:"count"`
+	Others     bool           `json:"others,omitempty"`
+	Children   []*HeatmapNode `json:"children,omitempty"`
+}
+
+// Heatmap monta a árvore. Ativos sem tamanho (ex: valor de mercado
+// desconhecido) ficam de fora e são contados em excluded.
+func Heatmap(items []domain.StockListItem, opts HeatmapOptions) (root *HeatmapNode, excluded int) {
+	leaves := make([]*HeatmapNode, 0, len(items))
+	groupOf := make(map[*HeatmapNode]string, len(items))
+
+	for _, item := range items {
+		size := sizeOf(item, opts.SizeBy)
+		if size <= 0 {
+			excluded++
+			continue
+		}
+		name := item.Name
+		if name == "" {
+			name = item.Stock
+		}
+		leaf := &HeatmapNode{
+			Name:   name,
+			Symbol: item.Stock,
+			Logo:   item.Logo,
+			Size:   size,
+			Value:  item.Change,
+			Count:  1,
+		}
+		leaves = append(leaves, leaf)
+		groupOf[leaf] = groupName(item, opts.GroupBy)
+	}
+
+	percentiles := rankPercentiles(leaves)
+
+	groups := make(map[string][]*HeatmapNode)
+	for _, leaf := range leaves {
+		leaf.Percentile = percentiles[leaf]
+		leaf.Color = color(leaf.Value, opts.ColorRange)
+		groups[groupOf[leaf]] = append(groups[groupOf[leaf]], leaf)
+	}
+
+	root = &HeatmapNode{Name: "B3"}
+	for name, members := range groups {
+		root.Children = append(root.Children, group(name, members, opts.MaxLeaves, opts.ColorRange))
+	}
+	summarize(root, opts.ColorRange)
+
+	// Percentil dos grupos e do mercado: posição da média entre as folhas
+	for _, g := range append(root.Children, root) {
+		g.Percentile = percentileOf(g.Value, leaves)
+	}
+	for _, g := range root.Children {
+		for _, child := range g.Children {
+			if child.Others {
+				child.Percentile = percentileOf(child.Value, leaves)
+			}
+		}
+	}
+	return root, excluded
+}
+
+// group ordena as folhas por tamanho e junta as que passam de maxLeaves
+// em um nó "Outros"
+func group(name string, members []*HeatmapNode, maxLeaves int, colorRange float64) *HeatmapNode {
+	sortBySize(members)
+
+	node := &HeatmapNode{Name: name}
+	if len(members) <= maxLeaves {
+		node.Children = members
+	} else {
+		others := &HeatmapNode{Name: OthersName, Others: true, Children: members[maxLeaves-1:]}
+		summarize(others, colorRange)
+		// As folhas do "Outros" não são enviadas: o grupo já resume o que
+		// não cabe no mapa
+		others.Children = nil
+		node.Children = append(members[:maxLeaves-1:maxLeaves-1], others)
+	}
+	summarize(node, colorRange)
+	return node
+}
+
+// summarize soma tamanhos e contagens, calcula a média ponderada de Value
+// e o peso de cada filho
+func summarize(node *HeatmapNode, colorRange float64) {
+	if len(node.Children) == 0 {
+		return
+	}
+
+	node.Size, node.Count = 0, 0
+	weighted := 0.0
+	for _, child := range node.Children {
+		node.Size += child.Size
+		node.Count += child.Count
+		weighted += child.Value * child.Size
+	}
+
+	sortBySize(node.Children)
+	for _, child := range node.Children {
+		child.Weight = round(child.Size / node.Size)
+	}
+	node.Value = round(weighted / node.Size)
+	node.Color = color(node.Value, colorRange)
+	node.Weight = 1
+}
+
+// sortBySize ordena do maior para o menor; "Outros" sempre por último
+func sortBySize(nodes []*HeatmapNode) {
+	sort.SliceStable(nodes, func(i, j int) bool {
+		if nodes[i].Others != nodes[j].Others {
+			return nodes[j].Others
+		}
+		if nodes[i].Size != nodes[j].Size {
+			return nodes[i].Size > nodes[j].Size
+		}
+		return nodes[i].Name < nodes[j].Name
+	})
+}
+
+func sizeOf(item domain.StockListItem, sizeBy string) float64 {
+	switch sizeBy {
+	case SizeByVolume:
+		return float64(item.Volume)
+	case SizeByEqual:
+		return 1
+	}
+	return float64(item.MarketCap)
+}
+
+func groupName(item domain.StockListItem, groupBy string) string {
+	if groupBy == GroupByType {
+		if t := strings.TrimSpace(item.Type); t != "" {
+			return t
+		}
+		return "Sem tipo"
+	}
+	return SectorOf(item)
+}
+
+// rankPercentiles calcula o percentil de cada folha pela métrica de cor
+// (empates recebem o mesmo percentil)
+func rankPercentiles(leaves []*HeatmapNode) map[*HeatmapNode]float64 {
+	out := make(map[*HeatmapNode]float64, len(leaves))
+	for _, leaf := range leaves {
+		out[leaf] = percentileOf(leaf.Value, leaves)
+	}
+	return out
+}
+
+// percentileOf é a fração das folhas com valor menor, contando empates
+// pela metade
+func percentileOf(v float64, leaves []*HeatmapNode) float64 {
+	if len(leaves) == 0 {
+		return 0
+	}
+	below, equal := 0, 0
+	for _, leaf := range leaves {
+		switch {
+		case leaf.Value < v:
+			below++
+		case leaf.Value == v:
+			equal++
+		}
+	}
+	return math.Round((float64(below)+float64(equal)/2)/float64(len(leaves))*1000) / 10
+}
+
+// color converte a variação em uma cor hexadecimal numa escala divergente:
+// vermelho (queda) → cinza (estável) → verde (alta)
+func color(change, colorRange float64) string {
+	neutral := [3]float64{0x41, 0x45, 0x54}
+	red := [3]float64{0xf6, 0x35, 0x38}
+	green := [3]float64{0x30, 0xcc, 0x5a}
+
+	t := math.Max(-1, math.Min(1, change/colorRange))
+	target := green
+	if t < 0 {
+		target, t = red, -t
+	}
+
+	var rgb [3]int
+	for i := range rgb {
+		rgb[i] = int(math.Round(neutral[i] + (target[i]-neutral[i])*t))
+	}
+	return fmt.Sprintf("#%02x%02x%02x", rgb[0], rgb[1], rgb[2])
+}
+
+func round(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
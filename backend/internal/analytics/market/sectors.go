@@ -0,0 +1,173 @@
+// Package market agrega a listagem de ações (StockListItem) em visões do
+// mercado inteiro: resumo por setor, maiores altas e baixas, amplitude e
+// heatmap.
+package market
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"cotacoes/internal/domain"
+)
+
+// NoSector agrupa os ativos sem setor informado
+const NoSector = "Sem setor"
+
+// Mover é um ativo em uma lista de maiores altas/baixas
+type Mover struct {
+	Symbol    string  `json:"symbol"`
+	Name      string  `json:"name"`
+	Sector    string  `json:"sector"`
+	Type      string  `json:"type"`
+	Logo      string  `json:"logo"`
+	Close     float64 `json:"close"`
+	Change    float64 `json:"change"`
+	Volume    int64   `json:"volume"`
+	MarketCap int64   `json:"marketCap"`
+}
+
+// SectorSummary agrega os ativos de um setor. As médias ponderadas são
+// nil quando nenhum ativo tem peso (volume ou valor de mercado).
+type SectorSummary struct {
+	Sector               string   `json:"sector"`
+	Count                int      `json:"count"`
+	TotalMarketCap       int64    `json:"totalMarketCap"`
+	TotalVolume          int64    `json:"totalVolume"`
+	AverageChange        *float64 `json:"averageChange"`
+	VolumeWeightedChange *float64 `json:"volumeWeightedChange"`
+	CapWeightedChange    *float64 `json:"capWeightedChange"`
+	TopGainers           []Mover  `json:"topGainers"`
+	TopLosers            []Mover  `json:"topLosers"`
+}
+
+// SectorOf normaliza o setor do ativo
+func SectorOf(item domain.StockListItem) string {
+	if s := strings.TrimSpace(item.Sector); s != "" {
+		return s
+	}
+	return NoSector
+}
+
+// MoverOf converte o item da listagem
+func MoverOf(item domain.StockListItem) Mover {
+	return Mover{
+		Symbol:    item.Stock,
+		Name:      item.Name,
+		Sector:    SectorOf(item),
+		Type:      item.Type,
+		Logo:      item.Logo,
+		Close:     item.Close,
+		Change:    item.Change,
+		Volume:    item.Volume,
+		MarketCap: item.MarketCap,
+	}
+}
+
+// Sectors resume cada setor, ordenados por valor de mercado total.
+// top é o tamanho das listas de maiores altas e baixas.
+func Sectors(items []domain.StockListItem, top int) []SectorSummary {
+	groups := make(map[string][]domain.StockListItem)
+	for _, item := range items {
+		sector := SectorOf(item)
+		groups[sector] = append(groups[sector], item)
+	}
+
+	out := make([]SectorSummary, 0, len(groups))
+	for sector, group := range groups {
+		out = append(out, Sector(sector, group, top))
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].TotalMarketCap != out[j].TotalMarketCap {
+			return out[i].TotalMarketCap > out[j].TotalMarketCap
+		}
+		return out[i].Sector < out[j].Sector
+	})
+	return out
+}
+
+// Sector resume um grupo de ativos
+func Sector(sector string, items []domain.StockListItem, top int) SectorSummary {
+	summary := SectorSummary{
+		Sector:     sector,
+		Count:      len(items),
+		TopGainers: []Mover{},
+		TopLosers:  []Mover{},
+	}
+
+	var sum, volumeSum, volumeWeight, capSum, capWeight float64
+	for _, item := range items {
+		summary.TotalMarketCap += max(item.MarketCap, 0)
+		summary.TotalVolume += max(item.Volume, 0)
+
+		sum += item.Change
+		if item.Volume > 0 {
+			volumeSum += item.Change * float64(item.Volume)
+			volumeWeight += float64(item.Volume)
+		}
+		if item.MarketCap > 0 {
+			capSum += item.Change * float64(item.MarketCap)
+			capWeight += float64(item.MarketCap)
+		}
+	}
+
+	if len(items) > 0 {
+		summary.AverageChange = number(sum / float64(len(items)))
+	}
+	if volumeWeight > 0 {
+		summary.VolumeWeightedChange = number(volumeSum / volumeWeight)
+	}
+	if capWeight > 0 {
+		summary.CapWeightedChange = number(capSum / capWeight)
+	}
+
+	summary.TopGainers = Movers(items, MoversGainers, top)
+	summary.TopLosers = Movers(items, MoversLosers, top)
+	return summary
+}
+
+// Top ordena uma cópia dos itens e retorna os "limit" primeiros que passam
+// no filtro. Empates são desfeitos pelo volume e depois pelo símbolo.
+func Top(
+	items []domain.StockListItem,
+	limit int,
+	less func(a, b domain.StockListItem) bool,
+	keep func(domain.StockListItem) bool,
+) []Mover {
+	sorted := make([]domain.StockListItem, 0, len(items))
+	for _, item := range items {
+		if keep == nil || keep(item) {
+			sorted = append(sorted, item)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		if a.Volume != b.Volume {
+			return a.Volume > b.Volume
+		}
+		return a.Stock < b.Stock
+	})
+
+	if limit >= 0 && len(sorted) > limit {
+		sorted = sorted[:limit]
+	}
+	out := make([]Mover, len(sorted))
+	for i, item := range sorted {
+		out[i] = MoverOf(item)
+	}
+	return out
+}
+
+func number(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	v = math.Round(v*1e4) / 1e4
+	return &v
+}
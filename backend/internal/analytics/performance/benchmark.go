@@ -0,0 +1,134 @@
+package performance
+
+import (
+	"math"
+	"time"
+
+	"cotacoes/internal/analytics/stats"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// CDI acumula a taxa diária do CDI (% a.d.) da data base até cada pregão:
+// de um pregão ao seguinte rende a taxa do pregão de partida. Pregões sem
+// taxa publicada (ex: o dia corrente) repetem a última e marcam estimated.
+func CDI(base time.Time, dates []time.Time, rates []domain.RatePoint) (index []float64, estimated bool) {
+	index = make([]float64, len(dates))
+	if len(rates) == 0 {
+		for i := range index {
+			index[i] = math.NaN()
+		}
+		return index, true
+	}
+
+	factor, j := 1.0, -1
+	from := base
+	for i, date := range dates {
+		for j+1 < len(rates) && !rates[j+1].Date.After(from) {
+			j++
+		}
+		if j < 0 {
+			factor = math.NaN()
+		} else {
+			if rates[j].Date.Before(calendar.StartOfDay(from)) {
+				estimated = true
+			}
+			factor *= 1 + rates[j].Value/100
+		}
+		index[i] = factor
+		from = date
+	}
+	return index, estimated
+}
+
+// IPCAPlus acumula IPCA + spread (% a.a.) da data base até cada pregão. A
+// inflação do mês (% a.m.) é distribuída igualmente entre os pregões do
+// mês e o spread capitaliza em 252 pregões por ano. Meses ainda sem IPCA
+// publicado repetem o último e marcam estimated.
+func IPCAPlus(base time.Time, dates []time.Time, ipca []domain.RatePoint, spread float64) (index []float64, estimated bool) {
+	index = make([]float64, len(dates))
+	monthly := make(map[string]float64, len(ipca))
+	var lastMonth string
+	for _, p := range ipca {
+		month := p.Date.Format("2006-01")
+		monthly[month] = p.Value
+		if month > lastMonth {
+			lastMonth = month
+		}
+	}
+	if len(monthly) == 0 {
+		for i := range index {
+			index[i] = math.NaN()
+		}
+		return index, true
+	}
+
+	real := math.Pow(1+spread/100, 1.0/TradingDaysPerYear)
+	days := make(map[string]int)
+	factor := 1.0
+	for i, date := range dates {
+		month := date.Format("2006-01")
+		rate, ok := monthly[month]
+		if !ok {
+			rate = monthly[lastMonth]
+			estimated = true
+		}
+		n, ok := days[month]
+		if !ok {
+			n = tradingDaysInMonth(date)
+			days[month] = n
+		}
+		factor *= math.Pow(1+rate/100, 1/float64(n)) * real
+		index[i] = factor
+	}
+	return index, estimated
+}
+
+// Prices converte uma série de preços em índice: cada pregão usa o último
+// preço até a data (preenchendo dias sem negócio) dividido pelo preço na
+// data base. Se a série começa depois da base, parte do primeiro preço e
+// os pregões anteriores ficam NaN.
+func Prices(base time.Time, dates []time.Time, points []stats.Point) []float64 {
+	index := make([]float64, len(dates))
+	j := -1
+	advance := func(date time.Time) {
+		limit := calendar.StartOfDay(date).Unix()
+		for j+1 < len(points) && stats.DateKey(points[j+1].Date, "1d") <= limit {
+			j++
+		}
+	}
+
+	advance(base)
+	start := math.NaN()
+	if j >= 0 {
+		start = points[j].Value
+	}
+	for i, date := range dates {
+		advance(date)
+		if j < 0 {
+			index[i] = math.NaN()
+			continue
+		}
+		if math.IsNaN(start) {
+			start = points[j].Value
+		}
+		index[i] = points[j].Value / start
+	}
+	return index
+}
+
+// tradingDaysInMonth conta os pregões do mês da data
+func tradingDaysInMonth(date time.Time) int {
+	date = date.In(calendar.Location)
+	day := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, calendar.Location)
+	n := 0
+	for ; day.Month() == date.Month(); day = day.AddDate(0, 0, 1) {
+		if calendar.Default.IsTradingDay(day) {
+			n++
+		}
+	}
+	if n == 0 {
+		return 1
+	}
+	return n
+}
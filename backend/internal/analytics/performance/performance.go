@@ -0,0 +1,112 @@
+// Package performance calcula a rentabilidade de carteiras: retorno
+// ponderado pelo tempo (TWR, pelo método de cotas), retorno ponderado pelo
+// dinheiro (XIRR) e índices de referência (CDI, IPCA+X, preços) na mesma
+// grade de pregões.
+//
+// Os índices são fatores acumulados em relação a uma data base (1 = sem
+// variação); NaN indica pregão sem dado disponível.
+package performance
+
+import (
+	"math"
+	"time"
+)
+
+const epsilon = 1e-9
+
+// TradingDaysPerYear é a base de dias úteis das taxas brasileiras
+const TradingDaysPerYear = 252
+
+// Valuation é o valor da carteira ao fim de um pregão e os fluxos do dia:
+// Inflow são os aportes (compras) e Outflow as retiradas (vendas e
+// proventos recebidos)
+type Valuation struct {
+	Date    time.Time
+	Value   float64
+	Inflow  float64
+	Outflow float64
+}
+
+// Index calcula o índice de cotas da carteira (TWR) a partir do valor
+// "start" no fim do pregão anterior ao primeiro. O retorno de cada dia é
+// (V + retiradas) / (V anterior + aportes) - 1: aportes rendem desde a
+// abertura e retiradas até o fechamento, então os fluxos não distorcem o
+// retorno. Dias sem capital investido não alteram o índice.
+func Index(start float64, vals []Valuation) []float64 {
+	index := make([]float64, len(vals))
+	factor, prev := 1.0, start
+	for i, v := range vals {
+		if base := prev + v.Inflow; base > epsilon {
+			factor *= (v.Value + v.Outflow) / base
+		}
+		index[i] = factor
+		prev = v.Value
+	}
+	return index
+}
+
+// Annualize converte o retorno de "periods" pregões em taxa anual.
+// Períodos menores que um ano não são anualizados (retorna nil).
+func Annualize(ret float64, periods int) *float64 {
+	if periods < TradingDaysPerYear || ret <= -1 || math.IsNaN(ret) {
+		return nil
+	}
+	v := math.Pow(1+ret, TradingDaysPerYear/float64(periods)) - 1
+	return &v
+}
+
+// MonthReturn é o retorno de um mês (AAAA-MM). Return é nil quando o
+// índice não tem dados no mês.
+type MonthReturn struct {
+	Month  string
+	Return *float64
+}
+
+// Monthly calcula os retornos mensais do índice: cada mês parte do último
+// valor do mês anterior com dado (o primeiro mês parte da base, 1).
+func Monthly(dates []time.Time, index []float64) []MonthReturn {
+	var months []MonthReturn
+	prev := 1.0
+	for i := 0; i < len(dates); {
+		month := dates[i].Format("2006-01")
+		last := math.NaN()
+		for ; i < len(dates) && dates[i].Format("2006-01") == month; i++ {
+			if !math.IsNaN(index[i]) {
+				last = index[i]
+			}
+		}
+
+		row := MonthReturn{Month: month}
+		if !math.IsNaN(last) {
+			v := last/prev - 1
+			row.Return = &v
+			prev = last
+		}
+		months = append(months, row)
+	}
+	return months
+}
+
+// Cumulative converte o índice em retorno acumulado (%), com nil nos
+// pregões sem dado
+func Cumulative(index []float64) []*float64 {
+	out := make([]*float64, len(index))
+	for i, v := range index {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+		pct := math.Round((v-1)*100*10000) / 10000
+		out[i] = &pct
+	}
+	return out
+}
+
+// Last devolve o último valor disponível do índice (NaN se nenhum)
+func Last(index []float64) float64 {
+	for i := len(index) - 1; i >= 0; i-- {
+		if !math.IsNaN(index[i]) {
+			return index[i]
+		}
+	}
+	return math.NaN()
+}
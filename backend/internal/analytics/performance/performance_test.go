@@ -0,0 +1,252 @@
+package performance
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+func date(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02", s, calendar.Location)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func assertNear(t *testing.T, name string, got, want, tol float64) {
+	t.Helper()
+	if math.IsNaN(got) || math.Abs(got-want) > tol {
+		t.Errorf("%s = %.10f, esperado %.10f", name, got, want)
+	}
+}
+
+func TestXIRR(t *testing.T) {
+	tests := []struct {
+		name  string
+		flows []Flow
+		want  float64
+	}{
+		{
+			name: "um ano exato",
+			flows: []Flow{
+				{Date: date("2023-01-01"), Amount: -1000},
+				{Date: date("2024-01-01"), Amount: 1100},
+			},
+			want: 0.10,
+		},
+		{
+			// Exemplo da documentação da função XIRR do Excel
+			name: "fluxos irregulares",
+			flows: []Flow{
+				{Date: date("2008-01-01"), Amount: -10000},
+				{Date: date("2008-03-01"), Amount: 2750},
+				{Date: date("2008-10-30"), Amount: 4250},
+				{Date: date("2009-02-15"), Amount: 3250},
+				{Date: date("2009-04-01"), Amount: 2750},
+			},
+			want: 0.373362535,
+		},
+		{
+			name: "fluxos fora de ordem",
+			flows: []Flow{
+				{Date: date("2024-01-01"), Amount: 1100},
+				{Date: date("2023-01-01"), Amount: -1000},
+			},
+			want: 0.10,
+		},
+		{
+			// Newton sai do domínio (taxa <= -100%) e a bisseção resolve
+			name: "perda quase total pela bisseção",
+			flows: []Flow{
+				{Date: date("2023-01-01"), Amount: -1000},
+				{Date: date("2024-01-01"), Amount: 1},
+			},
+			want: -0.999,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := XIRR(tt.flows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertNear(t, "xirr", got, tt.want, 1e-6)
+		})
+	}
+}
+
+func TestXIRRFallback(t *testing.T) {
+	// VPL de -1000 hoje e +1 em um ano
+	npv := func(rate float64) (float64, float64) {
+		return -1000 + 1/(1+rate), -1 / ((1 + rate) * (1 + rate))
+	}
+	if _, ok := newton(npv, 0.1); ok {
+		t.Fatal("esperado que Newton-Raphson não convergisse")
+	}
+	got, err := bisect(npv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNear(t, "bisseção", got, -0.999, 1e-6)
+}
+
+func TestXIRRWithoutSolution(t *testing.T) {
+	_, err := XIRR([]Flow{
+		{Date: date("2023-01-01"), Amount: -1000},
+		{Date: date("2024-01-01"), Amount: -100},
+	})
+	if !errors.Is(err, ErrNoXIRR) {
+		t.Errorf("erro = %v, esperado ErrNoXIRR", err)
+	}
+}
+
+func TestIndexWithFlows(t *testing.T) {
+	vals := []Valuation{
+		{Value: 1100},
+		// Aporte de 1100 rende desde a abertura: 2200 → 2420 (+10%)
+		{Value: 2420, Inflow: 1100},
+		// Venda de 420 no dia sem variação de preço
+		{Value: 2000, Outflow: 420},
+		// Resgate total
+		{Value: 0, Outflow: 2000},
+		// Sem capital investido: o índice não muda
+		{Value: 0},
+	}
+	want := []float64{1.1, 1.21, 1.21, 1.21, 1.21}
+
+	index := Index(1000, vals)
+	for i := range want {
+		assertNear(t, "índice", index[i], want[i], 1e-12)
+	}
+	if got := Last(index); got != index[len(index)-1] {
+		t.Errorf("Last = %v", got)
+	}
+}
+
+func TestMonthly(t *testing.T) {
+	dates := []time.Time{
+		date("2024-01-30"), date("2024-01-31"),
+		date("2024-02-01"), date("2024-02-29"),
+		date("2024-03-01"),
+		date("2024-04-01"), date("2024-04-30"),
+	}
+	index := []float64{1.01, 1.02, 1.05, 1.071, math.NaN(), 1.0, 1.1781}
+
+	months := Monthly(dates, index)
+	want := []struct {
+		month string
+		ret   *float64
+	}{
+		{"2024-01", ptr(0.02)},
+		{"2024-02", ptr(0.05)},
+		{"2024-03", nil},
+		// Abril parte do último valor com dado (fevereiro)
+		{"2024-04", ptr(0.1)},
+	}
+	if len(months) != len(want) {
+		t.Fatalf("%d meses, esperado %d", len(months), len(want))
+	}
+	for i, w := range want {
+		got := months[i]
+		if got.Month != w.month {
+			t.Fatalf("mês %d = %s, esperado %s", i, got.Month, w.month)
+		}
+		if w.ret == nil {
+			if got.Return != nil {
+				t.Errorf("%s = %v, esperado sem dado", w.month, *got.Return)
+			}
+			continue
+		}
+		if got.Return == nil {
+			t.Fatalf("%s sem retorno", w.month)
+		}
+		assertNear(t, w.month, *got.Return, *w.ret, 1e-12)
+	}
+}
+
+func TestAnnualize(t *testing.T) {
+	if Annualize(0.1, 100) != nil {
+		t.Error("período menor que um ano não deve ser anualizado")
+	}
+	got := Annualize(0.21, 2*TradingDaysPerYear)
+	if got == nil {
+		t.Fatal("esperado retorno anualizado")
+	}
+	assertNear(t, "anualizado", *got, 0.1, 1e-12)
+}
+
+func TestCDI(t *testing.T) {
+	rates := []domain.RatePoint{
+		{Date: date("2024-03-01"), Value: 0.05},
+		{Date: date("2024-03-04"), Value: 0.04},
+		{Date: date("2024-03-05"), Value: 0.03},
+	}
+	dates := []time.Time{date("2024-03-04"), date("2024-03-05"), date("2024-03-06")}
+
+	// Cada pregão rende a taxa do pregão anterior
+	index, estimated := CDI(date("2024-03-01"), dates, rates)
+	assertNear(t, "04/03", index[0], 1.0005, 1e-12)
+	assertNear(t, "05/03", index[1], 1.0005*1.0004, 1e-12)
+	assertNear(t, "06/03", index[2], 1.0005*1.0004*1.0003, 1e-12)
+	if estimated {
+		t.Error("todas as taxas publicadas: não deve ser estimado")
+	}
+
+	// Sem a taxa de 05/03, repete a de 04/03
+	index, estimated = CDI(date("2024-03-01"), dates, rates[:2])
+	assertNear(t, "06/03 estimado", index[2], 1.0005*1.0004*1.0004, 1e-12)
+	if !estimated {
+		t.Error("esperado estimated quando falta a taxa")
+	}
+}
+
+func TestCDIYear(t *testing.T) {
+	// CDI de 10,65% a.a. equivale a 0,0401675% ao dia útil
+	daily := (math.Pow(1.1065, 1.0/TradingDaysPerYear) - 1) * 100
+	base := date("2023-01-02")
+	var rates []domain.RatePoint
+	var dates []time.Time
+	day := base
+	for len(dates) < TradingDaysPerYear {
+		rates = append(rates, domain.RatePoint{Date: day, Value: daily})
+		day = calendar.Default.NextTradingDay(day)
+		dates = append(dates, day)
+	}
+
+	assertNear(t, "taxa diária", daily, 0.0401675, 1e-7)
+	index, _ := CDI(base, dates, rates)
+	assertNear(t, "CDI em 252 pregões", Last(index), 1.1065, 1e-9)
+}
+
+func TestIPCAPlus(t *testing.T) {
+	// Março de 2024 tem 20 pregões (29/03 é Sexta-feira Santa)
+	var dates []time.Time
+	for day := date("2024-03-01"); day.Month() == time.March; day = day.AddDate(0, 0, 1) {
+		if calendar.Default.IsTradingDay(day) {
+			dates = append(dates, day)
+		}
+	}
+	if len(dates) != 20 {
+		t.Fatalf("%d pregões em março, esperado 20", len(dates))
+	}
+	dates = append(dates, date("2024-04-01"))
+
+	ipca := []domain.RatePoint{{Date: date("2024-03-01"), Value: 0.16}}
+	index, estimated := IPCAPlus(date("2024-02-29"), dates, ipca, 6)
+
+	// Fim de março: IPCA do mês inteiro e 20/252 do spread anual
+	assertNear(t, "IPCA+6 em março", index[19], 1.0016*math.Pow(1.06, 20.0/252), 1e-12)
+	if !estimated {
+		t.Error("abril sem IPCA publicado: esperado estimated")
+	}
+}
+
+func ptr(v float64) *float64 {
+	return &v
+}
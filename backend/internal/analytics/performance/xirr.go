@@ -0,0 +1,108 @@
+package performance
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+// ErrNoXIRR indica fluxos sem taxa interna de retorno (ex: só aportes)
+var ErrNoXIRR = errors.New("xirr: fluxos sem solução")
+
+// Flow é um fluxo de caixa do ponto de vista do investidor: aportes são
+// negativos e resgates (vendas, proventos, valor final) positivos
+type Flow struct {
+	Date   time.Time
+	Amount float64
+}
+
+// XIRR calcula a taxa interna de retorno anual (base 365 dias corridos)
+// de fluxos em datas irregulares. Usa Newton-Raphson e, se não convergir,
+// bisseção.
+func XIRR(flows []Flow) (float64, error) {
+	var hasIn, hasOut bool
+	for _, f := range flows {
+		hasIn = hasIn || f.Amount < -epsilon
+		hasOut = hasOut || f.Amount > epsilon
+	}
+	if !hasIn || !hasOut {
+		return 0, ErrNoXIRR
+	}
+
+	start := flows[0].Date
+	for _, f := range flows {
+		if f.Date.Before(start) {
+			start = f.Date
+		}
+	}
+	years := make([]float64, len(flows))
+	for i, f := range flows {
+		years[i] = f.Date.Sub(start).Hours() / 24 / 365
+	}
+
+	npv := func(rate float64) (value, derivative float64) {
+		for i, f := range flows {
+			d := math.Pow(1+rate, years[i])
+			value += f.Amount / d
+			derivative -= years[i] * f.Amount / (d * (1 + rate))
+		}
+		return value, derivative
+	}
+
+	if rate, ok := newton(npv, 0.1); ok {
+		return rate, nil
+	}
+	return bisect(npv)
+}
+
+// newton procura a raiz do VPL por Newton-Raphson a partir de guess. ok é
+// false quando não converge (derivada nula ou taxa fora do domínio).
+func newton(npv func(float64) (float64, float64), guess float64) (rate float64, ok bool) {
+	rate = guess
+	for i := 0; i < 50; i++ {
+		v, d := npv(rate)
+		if math.Abs(v) < 1e-7 {
+			return rate, true
+		}
+		if d == 0 || math.IsNaN(d) {
+			return 0, false
+		}
+		next := rate - v/d
+		if next <= -1 || math.IsNaN(next) || math.IsInf(next, 0) {
+			return 0, false
+		}
+		if math.Abs(next-rate) < 1e-10 {
+			return next, true
+		}
+		rate = next
+	}
+	return 0, false
+}
+
+// bisect procura a raiz do VPL por bisseção: amplia o limite superior até
+// haver troca de sinal
+func bisect(npv func(float64) (float64, float64)) (float64, error) {
+	lo, hi := -0.999999, 1.0
+	vLo, _ := npv(lo)
+	vHi, _ := npv(hi)
+	for vLo*vHi > 0 && hi < 1e6 {
+		hi *= 10
+		vHi, _ = npv(hi)
+	}
+	if vLo*vHi > 0 {
+		return 0, ErrNoXIRR
+	}
+	for i := 0; i < 200; i++ {
+		mid := (lo + hi) / 2
+		v, _ := npv(mid)
+		if math.Abs(v) < 1e-7 || hi-lo < 1e-12 {
+			return mid, nil
+		}
+		if v*vLo > 0 {
+			lo, vLo = mid, v
+		} else {
+			hi = mid
+		}
+	}
+	return (lo + hi) / 2, nil
+}
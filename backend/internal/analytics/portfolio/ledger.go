@@ -0,0 +1,373 @@
+// Package portfolio reprocessa os lançamentos de uma carteira e calcula
+// posições, preço médio e resultados realizados pelas regras brasileiras:
+//   - o preço médio inclui as taxas da compra e não muda nas vendas;
+//   - compras e vendas do mesmo ativo no mesmo dia formam day trade, com
+//     resultado apurado pelos preços médios do dia, sem afetar a posição;
+//   - desdobramentos/grupamentos alteram a quantidade mas não o custo total;
+//   - bonificações entram com o custo atribuído pela empresa.
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// DateLayout é o formato das datas dos lançamentos
+const DateLayout = "2006-01-02"
+
+// epsilon descarta resíduos de arredondamento nas quantidades
+const epsilon = 1e-9
+
+var ErrInvalidTransaction = errors.New("lançamento inválido")
+
+// fractionalSymbol reconhece o código do mercado fracionário (ex: PETR4F)
+var fractionalSymbol = regexp.MustCompile(`^([A-Z0-9]{4}[0-9]{1,2})F$`)
+
+// NormalizeSymbol padroniza o código do ativo: maiúsculas, sem espaços e
+// sem o sufixo "F" do fracionário, que é o mesmo ativo do lote padrão
+func NormalizeSymbol(symbol string) string {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	if m := fractionalSymbol.FindStringSubmatch(symbol); m != nil {
+		return m[1]
+	}
+	return symbol
+}
+
+// Position é a posição em um ativo após todos os lançamentos
+type Position struct {
+	Symbol       string  `json:"symbol"`
+	Quantity     float64 `json:"quantity"`
+	AveragePrice float64 `json:"averagePrice"`
+	TotalCost    float64 `json:"totalCost"`
+	RealizedPnL  float64 `json:"realizedPnL"`
+	Dividends    float64 `json:"dividends"`
+	FirstDate    string  `json:"firstDate"`
+	LastDate     string  `json:"lastDate"`
+}
+
+// Sale é uma venda com resultado apurado. Proceeds já desconta as taxas
+// da venda e Cost é o custo médio da quantidade vendida.
+type Sale struct {
+	Date     string  `json:"date"`
+	Symbol   string  `json:"symbol"`
+	DayTrade bool    `json:"dayTrade"`
+	Quantity float64 `json:"quantity"`
+	Gross    float64 `json:"gross"`
+	Proceeds float64 `json:"proceeds"`
+	Cost     float64 `json:"cost"`
+	Result   float64 `json:"result"`
+}
+
+// Income é um provento recebido
+type Income struct {
+	Date   string  `json:"date"`
+	Symbol string  `json:"symbol"`
+	Amount float64 `json:"amount"`
+}
+
+// Ledger é o resultado do reprocessamento dos lançamentos
+type Ledger struct {
+	// Positions inclui as posições encerradas (Quantity 0)
+	Positions []Position
+	Sales     []Sale
+	Incomes   []Income
+}
+
+// Normalize valida o lançamento e padroniza símbolo, tipo e data
+func Normalize(tx *domain.Transaction) error {
+	tx.Type = strings.ToLower(strings.TrimSpace(tx.Type))
+	tx.Symbol = NormalizeSymbol(tx.Symbol)
+	tx.Date = strings.TrimSpace(tx.Date)
+	tx.Note = strings.TrimSpace(tx.Note)
+	tx.AssetClass = strings.ToLower(strings.TrimSpace(tx.AssetClass))
+
+	if tx.Symbol == "" {
+		return fmt.Errorf("%w: informe symbol", ErrInvalidTransaction)
+	}
+	if _, err := time.Parse(DateLayout, tx.Date); err != nil {
+		return fmt.Errorf("%w: date %q (use AAAA-MM-DD)", ErrInvalidTransaction, tx.Date)
+	}
+	for name, v := range map[string]float64{
+		"quantity": tx.Quantity, "price": tx.Price, "fees": tx.Fees, "ratio": tx.Ratio, "amount": tx.Amount,
+	} {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("%w: %s deve ser um número não negativo", ErrInvalidTransaction, name)
+		}
+	}
+
+	switch tx.AssetClass {
+	case "", domain.AssetStock, domain.AssetFII, domain.AssetETF, domain.AssetBDR:
+	default:
+		return fmt.Errorf("%w: assetClass %q (use stock, fii, etf ou bdr)", ErrInvalidTransaction, tx.AssetClass)
+	}
+
+	switch tx.Type {
+	case domain.TransactionBuy, domain.TransactionSell:
+		if tx.Quantity == 0 {
+			return fmt.Errorf("%w: informe quantity", ErrInvalidTransaction)
+		}
+		if tx.Price == 0 {
+			return fmt.Errorf("%w: informe price", ErrInvalidTransaction)
+		}
+	case domain.TransactionSplit:
+		if tx.Ratio == 0 && tx.Quantity == 0 {
+			return fmt.Errorf("%w: informe ratio (ex: 2 para desdobramento 1:2) ou quantity recebida", ErrInvalidTransaction)
+		}
+	case domain.TransactionBonus:
+		if tx.Ratio == 0 && tx.Quantity == 0 {
+			return fmt.Errorf("%w: informe ratio ou quantity da bonificação", ErrInvalidTransaction)
+		}
+	case domain.TransactionDividend:
+		if tx.Amount == 0 && tx.Quantity*tx.Price == 0 {
+			return fmt.Errorf("%w: informe amount (ou quantity e price por ação)", ErrInvalidTransaction)
+		}
+	default:
+		return fmt.Errorf("%w: type %q (use buy, sell, split, bonus ou dividend)", ErrInvalidTransaction, tx.Type)
+	}
+	return nil
+}
+
+// Day é o estado da carteira ao fim de um dia com lançamentos. Inflow são
+// os aportes (compras com taxas) e Outflow as retiradas (vendas líquidas
+// de taxas e proventos recebidos). TradePrices guarda o último preço
+// negociado de cada ativo no dia.
+type Day struct {
+	Date        string
+	Quantities  map[string]float64
+	Inflow      float64
+	Outflow     float64
+	TradePrices map[string]float64
+}
+
+// Replay reprocessa os lançamentos em ordem cronológica. Em cada dia os
+// eventos societários são aplicados antes das negociações. Retorna erro
+// se uma venda exceder a posição ou um evento ocorrer sem posição.
+func Replay(txs []domain.Transaction) (*Ledger, error) {
+	return replay(txs, nil)
+}
+
+// Days reprocessa os lançamentos e devolve o estado ao fim de cada dia
+// com lançamentos, para a valorização histórica da carteira
+func Days(txs []domain.Transaction) ([]Day, error) {
+	var days []Day
+	_, err := replay(txs, func(day Day) {
+		days = append(days, day)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return days, nil
+}
+
+func replay(txs []domain.Transaction, onDay func(Day)) (*Ledger, error) {
+	sorted := make([]domain.Transaction, len(txs))
+	copy(sorted, txs)
+	// Lançamentos gravados antes da normalização podem ter o código do
+	// fracionário (PETR4F), que é a mesma posição do lote padrão
+	for i := range sorted {
+		sorted[i].Symbol = NormalizeSymbol(sorted[i].Symbol)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Date != sorted[j].Date {
+			return sorted[i].Date < sorted[j].Date
+		}
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	ledger := &Ledger{}
+	positions := make(map[string]*Position)
+	position := func(tx domain.Transaction) *Position {
+		p, ok := positions[tx.Symbol]
+		if !ok {
+			p = &Position{Symbol: tx.Symbol, FirstDate: tx.Date}
+			positions[tx.Symbol] = p
+		}
+		p.LastDate = tx.Date
+		return p
+	}
+
+	for start := 0; start < len(sorted); {
+		end := start
+		for end < len(sorted) && sorted[end].Date == sorted[start].Date {
+			end++
+		}
+		day := sorted[start:end]
+		start = end
+
+		// Eventos societários e proventos
+		for _, tx := range day {
+			switch tx.Type {
+			case domain.TransactionSplit:
+				p := position(tx)
+				if p.Quantity <= epsilon {
+					return nil, fmt.Errorf("%w: desdobramento de %s em %s sem posição", ErrInvalidTransaction, tx.Symbol, tx.Date)
+				}
+				if tx.Ratio > 0 {
+					p.Quantity *= tx.Ratio
+				} else {
+					p.Quantity += tx.Quantity
+				}
+				p.AveragePrice = p.TotalCost / p.Quantity
+
+			case domain.TransactionBonus:
+				p := position(tx)
+				if p.Quantity <= epsilon {
+					return nil, fmt.Errorf("%w: bonificação de %s em %s sem posição", ErrInvalidTransaction, tx.Symbol, tx.Date)
+				}
+				quantity := tx.Quantity
+				if quantity == 0 {
+					quantity = math.Floor(p.Quantity*tx.Ratio + epsilon)
+				}
+				p.Quantity += quantity
+				p.TotalCost += quantity * tx.Price
+				p.AveragePrice = p.TotalCost / p.Quantity
+
+			case domain.TransactionDividend:
+				p := position(tx)
+				amount := dividendAmount(tx)
+				p.Dividends += amount
+				ledger.Incomes = append(ledger.Incomes, Income{Date: tx.Date, Symbol: tx.Symbol, Amount: amount})
+			}
+		}
+
+		// Negociações agrupadas por ativo
+		trades := make(map[string]*dayTrades)
+		var symbols []string
+		state := Day{Date: day[0].Date, TradePrices: make(map[string]float64)}
+		for _, tx := range day {
+			if tx.Type == domain.TransactionDividend {
+				state.Outflow += dividendAmount(tx)
+			}
+			if tx.Type != domain.TransactionBuy && tx.Type != domain.TransactionSell {
+				continue
+			}
+			state.TradePrices[tx.Symbol] = tx.Price
+			t, ok := trades[tx.Symbol]
+			if !ok {
+				t = &dayTrades{first: tx}
+				trades[tx.Symbol] = t
+				symbols = append(symbols, tx.Symbol)
+			}
+			t.add(tx)
+		}
+
+		for _, symbol := range symbols {
+			t := trades[symbol]
+			p := position(t.first)
+			if err := t.apply(p, ledger); err != nil {
+				return nil, err
+			}
+			state.Inflow += t.buyCost
+			state.Outflow += t.sellNet
+		}
+
+		if onDay != nil {
+			state.Quantities = make(map[string]float64, len(positions))
+			for symbol, p := range positions {
+				if p.Quantity > epsilon {
+					state.Quantities[symbol] = p.Quantity
+				}
+			}
+			onDay(state)
+		}
+	}
+
+	for _, p := range positions {
+		if p.Quantity <= epsilon {
+			p.Quantity, p.AveragePrice, p.TotalCost = 0, 0, 0
+		}
+		ledger.Positions = append(ledger.Positions, *p)
+	}
+	sort.Slice(ledger.Positions, func(i, j int) bool {
+		return ledger.Positions[i].Symbol < ledger.Positions[j].Symbol
+	})
+	return ledger, nil
+}
+
+// dividendAmount é o valor recebido no provento
+func dividendAmount(tx domain.Transaction) float64 {
+	if tx.Amount > 0 {
+		return tx.Amount
+	}
+	return tx.Quantity * tx.Price
+}
+
+// dayTrades acumula as compras e vendas de um ativo em um dia
+type dayTrades struct {
+	first                    domain.Transaction
+	bought, buyCost          float64 // quantidade e custo com taxas
+	sold, sellGross, sellNet float64 // quantidade, valor bruto e líquido de taxas
+}
+
+func (t *dayTrades) add(tx domain.Transaction) {
+	gross := tx.Quantity * tx.Price
+	if tx.Type == domain.TransactionBuy {
+		t.bought += tx.Quantity
+		t.buyCost += gross + tx.Fees
+		return
+	}
+	t.sold += tx.Quantity
+	t.sellGross += gross
+	t.sellNet += gross - tx.Fees
+}
+
+// apply separa o day trade (menor entre compras e vendas do dia) e aplica
+// o saldo à posição
+func (t *dayTrades) apply(p *Position, ledger *Ledger) error {
+	date, symbol := t.first.Date, t.first.Symbol
+
+	dayTrade := math.Min(t.bought, t.sold)
+	if dayTrade > epsilon {
+		buyAvg := t.buyCost / t.bought
+		sellAvg := t.sellNet / t.sold
+		sale := Sale{
+			Date:     date,
+			Symbol:   symbol,
+			DayTrade: true,
+			Quantity: dayTrade,
+			Gross:    dayTrade * t.sellGross / t.sold,
+			Proceeds: dayTrade * sellAvg,
+			Cost:     dayTrade * buyAvg,
+		}
+		sale.Result = sale.Proceeds - sale.Cost
+		p.RealizedPnL += sale.Result
+		ledger.Sales = append(ledger.Sales, sale)
+	}
+
+	if bought := t.bought - dayTrade; bought > epsilon {
+		p.Quantity += bought
+		p.TotalCost += bought * t.buyCost / t.bought
+		p.AveragePrice = p.TotalCost / p.Quantity
+	}
+
+	if sold := t.sold - dayTrade; sold > epsilon {
+		if sold > p.Quantity+epsilon {
+			return fmt.Errorf("%w: venda de %g %s em %s maior que a posição (%g)", ErrInvalidTransaction, sold, symbol, date, p.Quantity)
+		}
+		sale := Sale{
+			Date:     date,
+			Symbol:   symbol,
+			Quantity: sold,
+			Gross:    sold * t.sellGross / t.sold,
+			Proceeds: sold * t.sellNet / t.sold,
+			Cost:     sold * p.AveragePrice,
+		}
+		sale.Result = sale.Proceeds - sale.Cost
+		p.RealizedPnL += sale.Result
+		ledger.Sales = append(ledger.Sales, sale)
+
+		p.Quantity -= sold
+		p.TotalCost -= sale.Cost
+		if p.Quantity <= epsilon {
+			p.Quantity, p.AveragePrice, p.TotalCost = 0, 0, 0
+		}
+	}
+	return nil
+}
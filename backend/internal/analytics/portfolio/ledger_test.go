@@ -0,0 +1,189 @@
+package portfolio
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"cotacoes/internal/domain"
+)
+
+func buy(date, symbol string, quantity, price, fees float64) domain.Transaction {
+	return domain.Transaction{Type: domain.TransactionBuy, Date: date, Symbol: symbol, Quantity: quantity, Price: price, Fees: fees}
+}
+
+func sell(date, symbol string, quantity, price, fees float64) domain.Transaction {
+	return domain.Transaction{Type: domain.TransactionSell, Date: date, Symbol: symbol, Quantity: quantity, Price: price, Fees: fees}
+}
+
+func assertNear(t *testing.T, name string, got, want float64) {
+	t.Helper()
+	if math.Abs(got-want) > 1e-9 {
+		t.Errorf("%s = %v, esperado %v", name, got, want)
+	}
+}
+
+func replayOne(t *testing.T, txs ...domain.Transaction) (*Ledger, Position) {
+	t.Helper()
+	ledger, err := Replay(txs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ledger.Positions) != 1 {
+		t.Fatalf("%d posições, esperado 1: %+v", len(ledger.Positions), ledger.Positions)
+	}
+	return ledger, ledger.Positions[0]
+}
+
+func TestReplayAverageCost(t *testing.T) {
+	ledger, p := replayOne(t,
+		buy("2024-01-10", "PETR4", 100, 10, 10),
+		buy("2024-02-10", "PETR4", 100, 12, 0),
+		sell("2024-03-10", "PETR4", 50, 15, 5),
+	)
+
+	// Taxas entram no custo; a venda não muda o preço médio
+	assertNear(t, "quantity", p.Quantity, 150)
+	assertNear(t, "averagePrice", p.AveragePrice, 11.05)
+	assertNear(t, "totalCost", p.TotalCost, 1657.5)
+	if p.FirstDate != "2024-01-10" || p.LastDate != "2024-03-10" {
+		t.Errorf("datas = %s/%s", p.FirstDate, p.LastDate)
+	}
+
+	if len(ledger.Sales) != 1 {
+		t.Fatalf("%d vendas, esperado 1", len(ledger.Sales))
+	}
+	s := ledger.Sales[0]
+	assertNear(t, "gross", s.Gross, 750)
+	assertNear(t, "proceeds", s.Proceeds, 745)
+	assertNear(t, "cost", s.Cost, 552.5)
+	assertNear(t, "result", s.Result, 192.5)
+	assertNear(t, "realizedPnL", p.RealizedPnL, 192.5)
+}
+
+func TestReplayDayTrade(t *testing.T) {
+	ledger, p := replayOne(t,
+		buy("2024-01-10", "PETR4", 100, 10, 0),
+		// 50 compradas e 80 vendidas no dia: 50 são day trade e 30 saem da posição
+		sell("2024-01-11", "PETR4", 80, 13, 8),
+		buy("2024-01-11", "PETR4", 50, 12, 0),
+	)
+
+	assertNear(t, "quantity", p.Quantity, 70)
+	assertNear(t, "averagePrice", p.AveragePrice, 10)
+
+	if len(ledger.Sales) != 2 {
+		t.Fatalf("%d vendas, esperado 2", len(ledger.Sales))
+	}
+	dt, common := ledger.Sales[0], ledger.Sales[1]
+	if !dt.DayTrade || common.DayTrade {
+		t.Fatalf("day trade = %v/%v, esperado a primeira venda", dt.DayTrade, common.DayTrade)
+	}
+	// Preço médio de venda do dia: (1040 - 8) / 80 = 12,90
+	assertNear(t, "day trade quantity", dt.Quantity, 50)
+	assertNear(t, "day trade gross", dt.Gross, 650)
+	assertNear(t, "day trade proceeds", dt.Proceeds, 645)
+	assertNear(t, "day trade cost", dt.Cost, 600)
+	assertNear(t, "day trade result", dt.Result, 45)
+	assertNear(t, "comum quantity", common.Quantity, 30)
+	assertNear(t, "comum proceeds", common.Proceeds, 387)
+	assertNear(t, "comum cost", common.Cost, 300)
+	assertNear(t, "realizedPnL", p.RealizedPnL, 132)
+}
+
+func TestReplaySplitAndBonus(t *testing.T) {
+	_, p := replayOne(t,
+		buy("2024-01-10", "ITSA4", 100, 20, 0),
+		// Desdobramento 1:2 antes da compra do mesmo dia
+		buy("2024-02-01", "ITSA4", 5, 11, 0),
+		domain.Transaction{Type: domain.TransactionSplit, Date: "2024-02-01", Symbol: "ITSA4", Ratio: 2},
+		// Bonificação de 10% a R$ 5: 205 × 0,1 = 20,5 → 20 ações
+		domain.Transaction{Type: domain.TransactionBonus, Date: "2024-03-01", Symbol: "ITSA4", Ratio: 0.1, Price: 5},
+	)
+
+	assertNear(t, "quantity", p.Quantity, 225)
+	assertNear(t, "totalCost", p.TotalCost, 2000+55+100)
+	assertNear(t, "averagePrice", p.AveragePrice, 2155.0/225)
+
+	// Grupamento 10:1 informado pela razão
+	_, p = replayOne(t,
+		buy("2024-01-10", "MGLU3", 1000, 2, 0),
+		domain.Transaction{Type: domain.TransactionSplit, Date: "2024-02-01", Symbol: "MGLU3", Ratio: 0.1},
+	)
+	assertNear(t, "grupamento quantity", p.Quantity, 100)
+	assertNear(t, "grupamento averagePrice", p.AveragePrice, 20)
+}
+
+func TestReplayFractionalSymbol(t *testing.T) {
+	// Lançamento antigo gravado com o código do fracionário
+	_, p := replayOne(t,
+		buy("2024-01-10", "PETR4F", 10, 30, 0),
+		buy("2024-01-11", "PETR4", 100, 30, 0),
+		sell("2024-01-12", "petr4f", 5, 35, 0),
+	)
+	if p.Symbol != "PETR4" {
+		t.Errorf("symbol = %s, esperado PETR4", p.Symbol)
+	}
+	assertNear(t, "quantity", p.Quantity, 105)
+}
+
+func TestReplayErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		txs  []domain.Transaction
+	}{
+		{"venda maior que a posição", []domain.Transaction{
+			buy("2024-01-10", "PETR4", 10, 30, 0),
+			sell("2024-01-11", "PETR4", 11, 30, 0),
+		}},
+		{"desdobramento sem posição", []domain.Transaction{
+			{Type: domain.TransactionSplit, Date: "2024-01-10", Symbol: "PETR4", Ratio: 2},
+		}},
+		{"bonificação sem posição", []domain.Transaction{
+			{Type: domain.TransactionBonus, Date: "2024-01-10", Symbol: "PETR4", Ratio: 0.1},
+		}},
+	}
+	for _, tt := range tests {
+		if _, err := Replay(tt.txs); !errors.Is(err, ErrInvalidTransaction) {
+			t.Errorf("%s: erro = %v, esperado ErrInvalidTransaction", tt.name, err)
+		}
+	}
+}
+
+func TestDays(t *testing.T) {
+	days, err := Days([]domain.Transaction{
+		buy("2024-01-10", "PETR4", 100, 10, 1),
+		buy("2024-01-10", "VALE3", 10, 60, 0),
+		{Type: domain.TransactionDividend, Date: "2024-01-15", Symbol: "PETR4", Amount: 25},
+		sell("2024-01-15", "VALE3", 10, 65, 2),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(days) != 2 {
+		t.Fatalf("%d dias, esperado 2", len(days))
+	}
+
+	assertNear(t, "inflow 10/01", days[0].Inflow, 1601)
+	assertNear(t, "PETR4 10/01", days[0].Quantities["PETR4"], 100)
+	assertNear(t, "outflow 15/01", days[1].Outflow, 25+648)
+	if _, ok := days[1].Quantities["VALE3"]; ok {
+		t.Error("posição encerrada não deve aparecer nas quantidades")
+	}
+	assertNear(t, "preço negociado", days[1].TradePrices["VALE3"], 65)
+}
+
+func TestNormalizeSymbol(t *testing.T) {
+	tests := map[string]string{
+		" petr4f ": "PETR4",
+		"TAEE11F":  "TAEE11",
+		"PETR4":    "PETR4",
+		"BOVA11":   "BOVA11",
+		"CDI":      "CDI",
+	}
+	for in, want := range tests {
+		if got := NormalizeSymbol(in); got != want {
+			t.Errorf("NormalizeSymbol(%q) = %q, esperado %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,233 @@
+// Package ratios calcula indicadores fundamentalistas a partir do balanço
+// (BalanceSheet), dos dados financeiros (FinancialData), do preço atual e
+// da quantidade de ações.
+package ratios
+
+import (
+	"math"
+	"sort"
+	"strings"
+
+	"cotacoes/internal/domain"
+)
+
+// Alíquota de IR + CSLL usada no NOPAT do ROIC
+const nopatTaxRate = 0.34
+
+// Ratio é um indicador calculado. Value é null quando falta algum dado,
+// com o motivo em Reason; nunca é preenchido com zero por falta de dado.
+type Ratio struct {
+	Name   string             `json:"name"`
+	Value  *float64           `json:"value"`
+	Reason string             `json:"reason,omitempty"`
+	Period string             `json:"period,omitempty"`
+	Inputs map[string]float64 `json:"inputs,omitempty"`
+}
+
+// Input é o conjunto de dados de entrada
+type Input struct {
+	Price             float64
+	MarketCap         float64
+	SharesOutstanding float64
+	EarningsPerShare  float64
+	DividendsTTM      float64 // proventos por ação nos últimos 12 meses
+	BalanceSheets     []domain.BalanceSheet
+	FinancialData     domain.FinancialData
+}
+
+// FromStock monta a entrada a partir do detalhe da ação. Sem a quantidade
+// de ações informada, ela é derivada de valor de mercado / preço.
+func FromStock(stock *domain.Stock, dividendsTTM float64) Input {
+	shares := float64(stock.SharesOutstanding)
+	if shares <= 0 && stock.MarketCap > 0 && stock.RegularMarketPrice > 0 {
+		shares = float64(stock.MarketCap) / stock.RegularMarketPrice
+	}
+
+	return Input{
+		Price:             stock.RegularMarketPrice,
+		MarketCap:         float64(stock.MarketCap),
+		SharesOutstanding: shares,
+		EarningsPerShare:  stock.EarningsPerShare,
+		DividendsTTM:      dividendsTTM,
+		BalanceSheets:     stock.BalanceSheetHistory,
+		FinancialData:     stock.FinancialData,
+	}
+}
+
+// builder acumula entradas e motivos de ausência de um indicador
+type builder struct {
+	ratio   Ratio
+	missing []string
+}
+
+func newRatio(name, period string) *builder {
+	return &builder{ratio: Ratio{Name: name, Period: period, Inputs: map[string]float64{}}}
+}
+
+// need registra uma entrada; zero, NaN e ausência contam como falta de dado
+func (b *builder) need(name string, v float64) float64 {
+	if v == 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+		b.missing = append(b.missing, name)
+		return math.NaN()
+	}
+	b.ratio.Inputs[name] = v
+	return v
+}
+
+func (b *builder) done(value float64) Ratio {
+	switch {
+	case len(b.missing) > 0:
+		b.ratio.Reason = "dados ausentes: " + strings.Join(b.missing, ", ")
+		b.ratio.Inputs = nil
+	case math.IsNaN(value) || math.IsInf(value, 0):
+		b.ratio.Reason = "divisão por zero"
+	default:
+		b.ratio.Value = &value
+	}
+	return b.ratio
+}
+
+// Compute calcula todos os indicadores
+func Compute(in Input) []Ratio {
+	fd := in.FinancialData
+	bs, bsPeriod := LatestBalanceSheet(in.BalanceSheets)
+	fdPeriod := financialPeriod(fd)
+
+	out := make([]Ratio, 0, 11)
+
+	// P/L: preço / lucro por ação
+	{
+		b := newRatio("pe", "ttm")
+		price := b.need("price", in.Price)
+		eps := b.need("earningsPerShare", in.EarningsPerShare)
+		out = append(out, b.done(price/eps))
+	}
+
+	// P/VP: preço / patrimônio líquido por ação
+	{
+		b := newRatio("pb", bsPeriod)
+		price := b.need("price", in.Price)
+		equity := b.need("totalStockholderEquity", float64(bs.TotalStockholderEquity))
+		shares := b.need("sharesOutstanding", in.SharesOutstanding)
+		out = append(out, b.done(price/(equity/shares)))
+	}
+
+	// EV/EBITDA: (valor de mercado + dívida - caixa) / EBITDA
+	{
+		b := newRatio("evEbitda", fdPeriod)
+		marketCap := b.need("marketCap", in.MarketCap)
+		debt := optional(b, "totalDebt", float64(fd.TotalDebt))
+		cash := optional(b, "totalCash", float64(fd.TotalCash))
+		ebitda := b.need("ebitda", float64(fd.EBITDA))
+		out = append(out, b.done((marketCap+debt-cash)/ebitda))
+	}
+
+	// ROE: lucro líquido / patrimônio líquido
+	{
+		b := newRatio("roe", joinPeriods(fdPeriod, bsPeriod))
+		margin := b.need("profitMargins", fd.ProfitMargins)
+		revenue := b.need("totalRevenue", float64(fd.TotalRevenue))
+		equity := b.need("totalStockholderEquity", float64(bs.TotalStockholderEquity))
+		out = append(out, b.done(margin*revenue/equity))
+	}
+
+	// ROIC: EBIT * (1 - 34%) / (patrimônio + dívida - caixa)
+	{
+		b := newRatio("roic", joinPeriods(fdPeriod, bsPeriod))
+		margin := b.need("operatingMargins", fd.OperatingMargins)
+		revenue := b.need("totalRevenue", float64(fd.TotalRevenue))
+		equity := b.need("totalStockholderEquity", float64(bs.TotalStockholderEquity))
+		debt := optional(b, "totalDebt", float64(fd.TotalDebt))
+		cash := optional(b, "totalCash", float64(fd.TotalCash))
+		nopat := margin * revenue * (1 - nopatTaxRate)
+		b.ratio.Inputs["nopatTaxRate"] = nopatTaxRate
+		out = append(out, b.done(nopat/(equity+debt-cash)))
+	}
+
+	// Dívida líquida / EBITDA
+	{
+		b := newRatio("netDebtEbitda", fdPeriod)
+		debt := b.need("totalDebt", float64(fd.TotalDebt))
+		cash := optional(b, "totalCash", float64(fd.TotalCash))
+		ebitda := b.need("ebitda", float64(fd.EBITDA))
+		out = append(out, b.done((debt-cash)/ebitda))
+	}
+
+	// Liquidez corrente: ativo circulante / passivo circulante
+	{
+		b := newRatio("currentRatio", bsPeriod)
+		assets := b.need("totalCurrentAssets", float64(bs.TotalCurrentAssets))
+		liabilities := b.need("totalCurrentLiabilities", float64(bs.TotalCurrentLiabilities))
+		out = append(out, b.done(assets/liabilities))
+	}
+
+	// Margem bruta: lucro bruto / receita
+	{
+		b := newRatio("grossMargin", fdPeriod)
+		gross := b.need("grossProfits", float64(fd.GrossProfits))
+		revenue := b.need("totalRevenue", float64(fd.TotalRevenue))
+		out = append(out, b.done(gross/revenue))
+	}
+
+	// Margem líquida
+	{
+		b := newRatio("netMargin", fdPeriod)
+		margin := b.need("profitMargins", fd.ProfitMargins)
+		out = append(out, b.done(margin))
+	}
+
+	// Payout: proventos por ação (12 meses) / lucro por ação
+	{
+		b := newRatio("payout", "ttm")
+		dps := b.need("dividendsPerShareTTM", in.DividendsTTM)
+		eps := b.need("earningsPerShare", in.EarningsPerShare)
+		out = append(out, b.done(dps/eps))
+	}
+
+	return out
+}
+
+// optional registra uma entrada que pode ser zero de verdade (ex: empresa
+// sem dívida); ausência vira zero sem invalidar o indicador
+func optional(b *builder, name string, v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	b.ratio.Inputs[name] = v
+	return v
+}
+
+// LatestBalanceSheet escolhe o balanço mais recente pela data de encerramento
+// e descreve o período de origem
+func LatestBalanceSheet(sheets []domain.BalanceSheet) (domain.BalanceSheet, string) {
+	if len(sheets) == 0 {
+		return domain.BalanceSheet{}, ""
+	}
+	sorted := make([]domain.BalanceSheet, len(sheets))
+	copy(sorted, sheets)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].EndDate > sorted[j].EndDate })
+
+	bs := sorted[0]
+	period := "balanceSheet " + bs.EndDate
+	if bs.Type != "" {
+		period += " (" + bs.Type + ")"
+	}
+	return bs, period
+}
+
+func financialPeriod(fd domain.FinancialData) string {
+	if fd.UpdatedAt == "" {
+		return "financialData ttm"
+	}
+	return "financialData ttm " + fd.UpdatedAt
+}
+
+func joinPeriods(a, b string) string {
+	switch {
+	case a == "":
+		return b
+	case b == "":
+		return a
+	}
+	return a + "; " + b
+}
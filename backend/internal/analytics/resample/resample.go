@@ -0,0 +1,122 @@
+// Package resample agrega candles em barras semanais, mensais, trimestrais
+// ou anuais ancoradas no calendário de pregões da B3.
+package resample
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+var ErrInvalidPeriod = errors.New("invalid resample period")
+
+// Periods aceitos no parâmetro "resample"
+const (
+	Weekly    = "1wk"
+	Monthly   = "1mo"
+	Quarterly = "3mo"
+	Yearly    = "1y"
+)
+
+// Validate verifica se o período é suportado
+func Validate(period string) error {
+	switch period {
+	case Weekly, Monthly, Quarterly, Yearly:
+		return nil
+	}
+	return fmt.Errorf("%w: %q (use 1wk, 1mo, 3mo ou 1y)", ErrInvalidPeriod, period)
+}
+
+// Resample agrega os candles no período pedido: abertura do primeiro
+// candle, máxima das máximas, mínima das mínimas, fechamento (e fechamento
+// ajustado) do último e soma dos volumes. Cada barra é datada no primeiro
+// pregão do período segundo o calendário da B3, mesmo que o primeiro candle
+// disponível seja posterior (ex: feriado ou histórico começando no meio do
+// mês).
+func Resample(candles []domain.HistoricalDataPrice, period string, cal *calendar.B3) ([]domain.HistoricalDataPrice, error) {
+	if err := Validate(period); err != nil {
+		return nil, err
+	}
+
+	sorted := make([]domain.HistoricalDataPrice, 0, len(candles))
+	for _, c := range candles {
+		if c.Close > 0 {
+			sorted = append(sorted, c)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Date < sorted[j].Date })
+
+	bars := make([]domain.HistoricalDataPrice, 0)
+	var current *domain.HistoricalDataPrice
+	var currentStart time.Time
+
+	for _, c := range sorted {
+		start := periodStart(time.Unix(c.Date, 0), period)
+
+		if current == nil || !start.Equal(currentStart) {
+			if current != nil {
+				bars = append(bars, *current)
+			}
+			currentStart = start
+			current = &domain.HistoricalDataPrice{
+				Date:          firstSession(start, cal).Unix(),
+				Open:          valueOr(c.Open, c.Close),
+				High:          valueOr(c.High, c.Close),
+				Low:           valueOr(c.Low, c.Close),
+				Close:         c.Close,
+				Volume:        c.Volume,
+				AdjustedClose: c.AdjustedClose,
+			}
+			continue
+		}
+
+		current.High = max(current.High, valueOr(c.High, c.Close))
+		current.Low = min(current.Low, valueOr(c.Low, c.Close))
+		current.Close = c.Close
+		current.AdjustedClose = c.AdjustedClose
+		current.Volume += c.Volume
+	}
+
+	if current != nil {
+		bars = append(bars, *current)
+	}
+	return bars, nil
+}
+
+// periodStart retorna o início de calendário do período que contém t
+func periodStart(t time.Time, period string) time.Time {
+	day := calendar.StartOfDay(t)
+
+	switch period {
+	case Weekly:
+		// Semana de pregão começa na segunda-feira
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case Monthly:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, calendar.Location)
+	case Quarterly:
+		month := time.Month((int(day.Month())-1)/3*3 + 1)
+		return time.Date(day.Year(), month, 1, 0, 0, 0, 0, calendar.Location)
+	default:
+		return time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, calendar.Location)
+	}
+}
+
+// firstSession retorna o primeiro pregão a partir de start
+func firstSession(start time.Time, cal *calendar.B3) time.Time {
+	if cal.IsTradingDay(start) {
+		return start
+	}
+	return cal.NextTradingDay(start)
+}
+
+func valueOr(v, fallback float64) float64 {
+	if v <= 0 {
+		return fallback
+	}
+	return v
+}
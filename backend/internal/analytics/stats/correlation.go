@@ -0,0 +1,60 @@
+package stats
+
+import (
+	"math"
+	"sort"
+)
+
+// Pearson calcula a correlação linear entre duas séries de mesmo tamanho
+func Pearson(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) < 3 {
+		return math.NaN()
+	}
+	cov, varA, varB := covariance(a, b)
+	if varA == 0 || varB == 0 {
+		return math.NaN()
+	}
+	return cov / math.Sqrt(varA*varB)
+}
+
+// Spearman calcula a correlação de postos (Pearson sobre os ranks, com
+// empates recebendo o rank médio)
+func Spearman(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) < 3 {
+		return math.NaN()
+	}
+	return Pearson(ranks(a), ranks(b))
+}
+
+// PairReturns alinha duas séries de preços pelas datas em comum e devolve
+// os retornos de cada uma sobre essas datas
+func PairReturns(interval string, a, b []Point) ([]float64, []float64) {
+	_, aligned := Align(interval, a, b)
+	if len(aligned) < 2 {
+		return []float64{}, []float64{}
+	}
+	return Returns(aligned[0]), Returns(aligned[1])
+}
+
+func ranks(values []float64) []float64 {
+	idx := make([]int, len(values))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool { return values[idx[i]] < values[idx[j]] })
+
+	out := make([]float64, len(values))
+	for i := 0; i < len(idx); {
+		j := i
+		for j+1 < len(idx) && values[idx[j+1]] == values[idx[i]] {
+			j++
+		}
+		// Posições i..j empatadas recebem o rank médio (1-based)
+		rank := float64(i+j)/2 + 1
+		for k := i; k <= j; k++ {
+			out[idx[k]] = rank
+		}
+		i = j + 1
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package stats
+
+import "sort"
+
+// Rebase alinha as séries pela data do pregão e as normaliza para "base"
+// na primeira data em que todas têm preço. Datas anteriores a essa são
+// descartadas; depois dela, datas em que uma série não negociou ficam nil.
+// Retorna nil quando as séries não têm nenhuma data em comum.
+func Rebase(interval string, base float64, series ...[]Point) ([]int64, [][]*float64) {
+	if len(series) == 0 {
+		return nil, nil
+	}
+
+	maps := make([]map[int64]float64, len(series))
+	union := make(map[int64]bool)
+	for i, s := range series {
+		maps[i] = make(map[int64]float64, len(s))
+		for _, p := range s {
+			key := DateKey(p.Date, interval)
+			maps[i][key] = p.Value
+			union[key] = true
+		}
+	}
+
+	dates := make([]int64, 0, len(union))
+	for date := range union {
+		dates = append(dates, date)
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i] < dates[j] })
+
+	start := -1
+	for i, date := range dates {
+		inAll := true
+		for _, m := range maps {
+			if _, ok := m[date]; !ok {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil, nil
+	}
+	dates = dates[start:]
+
+	values := make([][]*float64, len(series))
+	for i, m := range maps {
+		first := m[dates[0]]
+		values[i] = make([]*float64, len(dates))
+		for j, date := range dates {
+			if v, ok := m[date]; ok {
+				values[i][j] = number(v / first * base)
+			}
+		}
+	}
+	return dates, values
+}
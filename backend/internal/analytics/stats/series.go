@@ -0,0 +1,151 @@
+// Package stats calcula estatísticas de retorno e risco sobre séries de
+// preços (HistoricalDataPrice).
+package stats
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// Point é um preço em uma data
+type Point struct {
+	Date  int64
+	Value float64
+}
+
+// Prices extrai a série de preços a partir do fechamento. Quando um modo
+// de ajuste é pedido, adjust.Apply já reescreve o Close; sem ajuste a série
+// é a bruta, sem misturar o AdjustedClose de fontes diferentes.
+// Candles sem preço são descartados.
+func Prices(candles []domain.HistoricalDataPrice) []Point {
+	points := make([]Point, 0, len(candles))
+	for _, c := range candles {
+		v := c.Close
+		if v <= 0 || math.IsNaN(v) {
+			continue
+		}
+		points = append(points, Point{Date: c.Date, Value: v})
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].Date < points[j].Date })
+	return points
+}
+
+// DateKey normaliza a data para alinhar séries de fontes diferentes:
+// candles diários (ou maiores) são alinhados pelo dia do pregão
+func DateKey(unix int64, interval string) int64 {
+	if isIntraday(interval) {
+		return unix - unix%60
+	}
+	return calendar.StartOfDay(time.Unix(unix, 0)).Unix()
+}
+
+// Align mantém apenas as datas presentes em todas as séries
+func Align(interval string, series ...[]Point) ([]int64, [][]float64) {
+	if len(series) == 0 {
+		return nil, nil
+	}
+
+	maps := make([]map[int64]float64, len(series))
+	for i, s := range series {
+		maps[i] = make(map[int64]float64, len(s))
+		for _, p := range s {
+			maps[i][DateKey(p.Date, interval)] = p.Value
+		}
+	}
+
+	dates := make([]int64, 0, len(maps[0]))
+	for date := range maps[0] {
+		inAll := true
+		for _, m := range maps[1:] {
+			if _, ok := m[date]; !ok {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			dates = append(dates, date)
+		}
+	}
+	sort.Slice(dates, func(i, j int) bool { return dates[i] < dates[j] })
+
+	values := make([][]float64, len(series))
+	for i, m := range maps {
+		values[i] = make([]float64, len(dates))
+		for j, date := range dates {
+			values[i][j] = m[date]
+		}
+	}
+	return dates, values
+}
+
+// Returns calcula os retornos simples entre preços consecutivos
+func Returns(prices []float64) []float64 {
+	if len(prices) < 2 {
+		return []float64{}
+	}
+	out := make([]float64, len(prices)-1)
+	for i := 1; i < len(prices); i++ {
+		out[i-1] = prices[i]/prices[i-1] - 1
+	}
+	return out
+}
+
+// PeriodsPerYear retorna quantos candles do intervalo cabem em um ano
+func PeriodsPerYear(interval string) float64 {
+	switch interval {
+	case "1wk", "5d":
+		return 52
+	case "1mo":
+		return 12
+	case "3mo":
+		return 4
+	case "1y":
+		return 1
+	}
+	if isIntraday(interval) {
+		// Aproximação: pregão de 7 horas
+		minutes := map[string]float64{"1m": 1, "2m": 2, "5m": 5, "15m": 15, "30m": 30, "60m": 60, "90m": 90, "1h": 60}
+		if m, ok := minutes[interval]; ok {
+			return 252 * 7 * 60 / m
+		}
+	}
+	return 252
+}
+
+func isIntraday(interval string) bool {
+	return strings.HasSuffix(interval, "m") && !strings.HasSuffix(interval, "mo") ||
+		strings.HasSuffix(interval, "h")
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return math.NaN()
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stdDev é o desvio-padrão amostral
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return math.NaN()
+	}
+	m := mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
+
+func toTime(unix int64) time.Time {
+	return time.Unix(unix, 0).In(calendar.Location)
+}
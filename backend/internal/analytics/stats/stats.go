@@ -0,0 +1,162 @@
+package stats
+
+import (
+	"math"
+	"time"
+)
+
+// Drawdown descreve a maior queda entre um pico e o vale seguinte
+type Drawdown struct {
+	Value      float64   `json:"value"`
+	PeakDate   time.Time `json:"peakDate"`
+	TroughDate time.Time `json:"troughDate"`
+	// RecoveryDate é nil quando o preço ainda não voltou ao pico
+	RecoveryDate *time.Time `json:"recoveryDate"`
+}
+
+// Report reúne as estatísticas de retorno e risco de uma série.
+// Campos nil indicam dados insuficientes.
+type Report struct {
+	From             time.Time `json:"from"`
+	To               time.Time `json:"to"`
+	Observations     int       `json:"observations"`
+	TotalReturn      *float64  `json:"totalReturn"`
+	AnnualizedReturn *float64  `json:"annualizedReturn"`
+	Volatility       *float64  `json:"volatility"`
+	MaxDrawdown      *Drawdown `json:"maxDrawdown"`
+	Sharpe           *float64  `json:"sharpe"`
+	Sortino          *float64  `json:"sortino"`
+	RiskFreeRate     float64   `json:"riskFreeRate"`
+
+	Benchmark             string   `json:"benchmark,omitempty"`
+	Beta                  *float64 `json:"beta"`
+	Correlation           *float64 `json:"correlation"`
+	BenchmarkObservations int      `json:"benchmarkObservations"`
+}
+
+// Compute calcula o relatório de uma série de preços.
+// riskFreeRate é a taxa livre de risco anual (ex: 0.1 = 10% a.a.).
+func Compute(prices []Point, interval string, riskFreeRate float64) Report {
+	report := Report{RiskFreeRate: riskFreeRate, Observations: len(prices)}
+	if len(prices) < 2 {
+		return report
+	}
+
+	report.From = toTime(prices[0].Date)
+	report.To = toTime(prices[len(prices)-1].Date)
+
+	values := make([]float64, len(prices))
+	for i, p := range prices {
+		values[i] = p.Value
+	}
+	returns := Returns(values)
+	ppy := PeriodsPerYear(interval)
+
+	total := values[len(values)-1]/values[0] - 1
+	report.TotalReturn = number(total)
+	report.AnnualizedReturn = number(math.Pow(1+total, ppy/float64(len(returns))) - 1)
+
+	std := stdDev(returns)
+	report.Volatility = number(std * math.Sqrt(ppy))
+
+	// Taxa livre de risco convertida para o período do candle
+	rfPeriod := math.Pow(1+riskFreeRate, 1/ppy) - 1
+	excess := make([]float64, len(returns))
+	downside := 0.0
+	for i, r := range returns {
+		excess[i] = r - rfPeriod
+		if excess[i] < 0 {
+			downside += excess[i] * excess[i]
+		}
+	}
+	excessMean := mean(excess)
+	excessStd := stdDev(excess)
+	if excessStd > 0 {
+		report.Sharpe = number(excessMean / excessStd * math.Sqrt(ppy))
+	}
+	downsideDev := math.Sqrt(downside / float64(len(excess)))
+	if downsideDev > 0 {
+		report.Sortino = number(excessMean / downsideDev * math.Sqrt(ppy))
+	}
+
+	report.MaxDrawdown = maxDrawdown(prices)
+	return report
+}
+
+// WithBenchmark calcula beta e correlação contra o benchmark, alinhando as
+// séries pelas datas em comum
+func (r *Report) WithBenchmark(name string, prices, benchmark []Point, interval string) {
+	r.Benchmark = name
+
+	_, aligned := Align(interval, prices, benchmark)
+	if len(aligned) < 2 || len(aligned[0]) < 3 {
+		return
+	}
+
+	asset := Returns(aligned[0])
+	bench := Returns(aligned[1])
+	r.BenchmarkObservations = len(asset)
+
+	cov, varAsset, varBench := covariance(asset, bench)
+	if varBench > 0 {
+		r.Beta = number(cov / varBench)
+	}
+	if varAsset > 0 && varBench > 0 {
+		r.Correlation = number(cov / math.Sqrt(varAsset*varBench))
+	}
+}
+
+func maxDrawdown(prices []Point) *Drawdown {
+	peak := prices[0]
+	var worst *Drawdown
+	var worstPeak Point
+
+	for _, p := range prices {
+		if p.Value > peak.Value {
+			peak = p
+		}
+		dd := p.Value/peak.Value - 1
+		if worst == nil || dd < worst.Value {
+			worst = &Drawdown{
+				Value:      dd,
+				PeakDate:   toTime(peak.Date),
+				TroughDate: toTime(p.Date),
+			}
+			worstPeak = peak
+		}
+	}
+
+	if worst == nil || worst.Value == 0 {
+		return &Drawdown{Value: 0, PeakDate: toTime(prices[0].Date), TroughDate: toTime(prices[0].Date)}
+	}
+
+	// Recuperação: primeiro preço após o vale que alcança o pico anterior
+	for _, p := range prices {
+		if p.Date > worst.TroughDate.Unix() && p.Value >= worstPeak.Value {
+			recovery := toTime(p.Date)
+			worst.RecoveryDate = &recovery
+			break
+		}
+	}
+	return worst
+}
+
+func covariance(a, b []float64) (cov, varA, varB float64) {
+	ma, mb := mean(a), mean(b)
+	for i := range a {
+		da, db := a[i]-ma, b[i]-mb
+		cov += da * db
+		varA += da * da
+		varB += db * db
+	}
+	n := float64(len(a) - 1)
+	return cov / n, varA / n, varB / n
+}
+
+// number converte NaN/Inf em nil para serializar como null
+func number(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
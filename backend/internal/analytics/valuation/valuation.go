@@ -0,0 +1,283 @@
+// Package valuation estima o "preço justo" de uma ação por modelos simples
+// de valor intrínseco: número de Graham, método Bazin e fluxo de caixa
+// descontado (DCF) sobre o fluxo de caixa livre.
+package valuation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"cotacoes/internal/analytics/dividends"
+	"cotacoes/internal/analytics/ratios"
+	"cotacoes/internal/domain"
+)
+
+var ErrInvalidAssumption = errors.New("premissa de valuation inválida")
+
+// Assumptions são as premissas dos modelos; todas podem ser sobrescritas
+// pela query e são devolvidas na resposta
+type Assumptions struct {
+	// Graham: preço justo = sqrt(multiplicador * LPA * VPA)
+	GrahamMultiplier float64 `json:"grahamMultiplier"`
+	// Bazin: preço justo = média dos proventos anuais / yield desejado
+	BazinYield float64 `json:"bazinYield"`
+	BazinYears int     `json:"bazinYears"`
+	// DCF: crescimento do FCL por DCFYears anos, perpetuidade a DCFTerminalGrowth
+	DCFGrowth         float64 `json:"dcfGrowth"`
+	DCFYears          int     `json:"dcfYears"`
+	DCFDiscountRate   float64 `json:"dcfDiscountRate"`
+	DCFTerminalGrowth float64 `json:"dcfTerminalGrowth"`
+}
+
+// Params são os nomes aceitos em Set (e na query da rota)
+var Params = []string{
+	"grahamMultiplier",
+	"bazinYield",
+	"bazinYears",
+	"dcfGrowth",
+	"dcfYears",
+	"dcfDiscountRate",
+	"dcfTerminalGrowth",
+}
+
+// DefaultAssumptions retorna as premissas usuais: 22,5 de Graham
+// (P/L 15 * P/VP 1,5), 6% de yield em 5 anos de Bazin e DCF de 5 anos
+// crescendo 5% a.a., descontado a 12% com perpetuidade de 3%
+func DefaultAssumptions() Assumptions {
+	return Assumptions{
+		GrahamMultiplier:  22.5,
+		BazinYield:        0.06,
+		BazinYears:        5,
+		DCFGrowth:         0.05,
+		DCFYears:          5,
+		DCFDiscountRate:   0.12,
+		DCFTerminalGrowth: 0.03,
+	}
+}
+
+// Set sobrescreve uma premissa pelo nome
+func (a *Assumptions) Set(name string, v float64) error {
+	if (name == "bazinYears" || name == "dcfYears") && v != math.Trunc(v) {
+		return fmt.Errorf("%w: %s deve ser inteiro", ErrInvalidAssumption, name)
+	}
+
+	switch name {
+	case "grahamMultiplier":
+		a.GrahamMultiplier = v
+	case "bazinYield":
+		a.BazinYield = v
+	case "bazinYears":
+		a.BazinYears = int(v)
+	case "dcfGrowth":
+		a.DCFGrowth = v
+	case "dcfYears":
+		a.DCFYears = int(v)
+	case "dcfDiscountRate":
+		a.DCFDiscountRate = v
+	case "dcfTerminalGrowth":
+		a.DCFTerminalGrowth = v
+	default:
+		return fmt.Errorf("%w: %q desconhecida", ErrInvalidAssumption, name)
+	}
+	return nil
+}
+
+// Validate confere se as premissas fazem sentido
+func (a Assumptions) Validate() error {
+	switch {
+	case a.GrahamMultiplier <= 0:
+		return fmt.Errorf("%w: grahamMultiplier deve ser positivo", ErrInvalidAssumption)
+	case a.BazinYield <= 0 || a.BazinYield >= 1:
+		return fmt.Errorf("%w: bazinYield deve estar entre 0 e 1", ErrInvalidAssumption)
+	case a.BazinYears < 1 || a.BazinYears > 20:
+		return fmt.Errorf("%w: bazinYears deve estar entre 1 e 20", ErrInvalidAssumption)
+	case a.DCFYears < 1 || a.DCFYears > 30:
+		return fmt.Errorf("%w: dcfYears deve estar entre 1 e 30", ErrInvalidAssumption)
+	case a.DCFGrowth <= -1:
+		return fmt.Errorf("%w: dcfGrowth deve ser maior que -1", ErrInvalidAssumption)
+	case a.DCFDiscountRate <= a.DCFTerminalGrowth:
+		return fmt.Errorf("%w: dcfDiscountRate deve ser maior que dcfTerminalGrowth", ErrInvalidAssumption)
+	}
+	return nil
+}
+
+// Model é o resultado de um modelo. FairValue é null quando o modelo não
+// se aplica, com o motivo em Reason.
+type Model struct {
+	Name      string             `json:"name"`
+	FairValue *float64           `json:"fairValue"`
+	Upside    *float64           `json:"upside"`
+	Reason    string             `json:"reason,omitempty"`
+	Period    string             `json:"period,omitempty"`
+	Inputs    map[string]float64 `json:"inputs,omitempty"`
+}
+
+// Input é o conjunto de dados de entrada dos modelos
+type Input struct {
+	Price             float64
+	EarningsPerShare  float64
+	SharesOutstanding float64
+	BalanceSheets     []domain.BalanceSheet
+	FinancialData     domain.FinancialData
+	CashDividends     []domain.CashDividend
+}
+
+// FromStock monta a entrada a partir do detalhe da ação
+func FromStock(stock *domain.Stock) Input {
+	in := ratios.FromStock(stock, 0)
+	return Input{
+		Price:             in.Price,
+		EarningsPerShare:  in.EarningsPerShare,
+		SharesOutstanding: in.SharesOutstanding,
+		BalanceSheets:     stock.BalanceSheetHistory,
+		FinancialData:     stock.FinancialData,
+		CashDividends:     stock.DividendsData.CashDividends,
+	}
+}
+
+// Compute roda os três modelos com as premissas dadas
+func Compute(in Input, a Assumptions, now time.Time) []Model {
+	return []Model{
+		withUpside(Graham(in, a), in.Price),
+		withUpside(Bazin(in, a, now), in.Price),
+		withUpside(DCF(in, a), in.Price),
+	}
+}
+
+// Graham calcula sqrt(multiplicador * LPA * VPA); só se aplica a empresas
+// com lucro e patrimônio positivos
+func Graham(in Input, a Assumptions) Model {
+	bs, period := ratios.LatestBalanceSheet(in.BalanceSheets)
+	m := Model{Name: "graham", Period: period, Inputs: map[string]float64{}}
+	equity := float64(bs.TotalStockholderEquity)
+
+	switch {
+	case in.EarningsPerShare == 0:
+		return missing(m, "lucro por ação ausente")
+	case in.EarningsPerShare < 0:
+		return missing(m, "lucro por ação negativo")
+	case equity == 0:
+		return missing(m, "patrimônio líquido ausente")
+	case equity < 0:
+		return missing(m, "patrimônio líquido negativo")
+	case in.SharesOutstanding <= 0:
+		return missing(m, "quantidade de ações ausente")
+	}
+
+	bvps := equity / in.SharesOutstanding
+	m.Inputs["earningsPerShare"] = in.EarningsPerShare
+	m.Inputs["bookValuePerShare"] = round(bvps)
+	m.Inputs["multiplier"] = a.GrahamMultiplier
+
+	value := math.Sqrt(a.GrahamMultiplier * in.EarningsPerShare * bvps)
+	m.FairValue = number(value)
+	return m
+}
+
+// Bazin divide a média dos proventos anuais por ação (líquidos de IR,
+// pelo NetRate de dividends.ApplyWithholding) dos últimos BazinYears anos
+// completos pelo yield desejado. Anos sem pagamento entram como zero na
+// média.
+func Bazin(in Input, a Assumptions, now time.Time) Model {
+	last := now.Year() - 1
+	first := last - a.BazinYears + 1
+	m := Model{Name: "bazin", Period: fmt.Sprintf("%d-%d", first, last), Inputs: map[string]float64{}}
+
+	if len(in.CashDividends) == 0 {
+		return missing(m, "sem histórico de proventos")
+	}
+
+	summary := dividends.Summarize(in.CashDividends, in.Price, now)
+
+	total := 0.0
+	for _, yt := range summary.YearlyTotals {
+		if yt.Year >= first && yt.Year <= last {
+			total += yt.NetTotal
+		}
+	}
+	if total <= 0 {
+		return missing(m, fmt.Sprintf("sem proventos entre %d e %d", first, last))
+	}
+
+	average := total / float64(a.BazinYears)
+	m.Inputs["averageDividends"] = round(average)
+	m.Inputs["yield"] = a.BazinYield
+
+	m.FairValue = number(average / a.BazinYield)
+	return m
+}
+
+// DCF projeta o fluxo de caixa livre por DCFYears anos crescendo a
+// DCFGrowth, soma a perpetuidade de Gordon e desconta tudo a
+// DCFDiscountRate. Do valor da firma subtrai a dívida líquida e divide
+// pela quantidade de ações.
+func DCF(in Input, a Assumptions) Model {
+	fd := in.FinancialData
+	m := Model{Name: "dcf", Period: strings.TrimSpace("financialData ttm " + fd.UpdatedAt), Inputs: map[string]float64{}}
+	fcf := float64(fd.FreeCashflow)
+
+	switch {
+	case fcf == 0:
+		return missing(m, "fluxo de caixa livre ausente")
+	case fcf < 0:
+		return missing(m, "fluxo de caixa livre negativo")
+	case in.SharesOutstanding <= 0:
+		return missing(m, "quantidade de ações ausente")
+	}
+
+	present := 0.0
+	projected := fcf
+	for year := 1; year <= a.DCFYears; year++ {
+		projected *= 1 + a.DCFGrowth
+		present += projected / math.Pow(1+a.DCFDiscountRate, float64(year))
+	}
+
+	terminal := projected * (1 + a.DCFTerminalGrowth) / (a.DCFDiscountRate - a.DCFTerminalGrowth)
+	terminalPresent := terminal / math.Pow(1+a.DCFDiscountRate, float64(a.DCFYears))
+
+	enterprise := present + terminalPresent
+	netDebt := float64(fd.TotalDebt - fd.TotalCash)
+	equity := enterprise - netDebt
+
+	m.Inputs["freeCashflow"] = fcf
+	m.Inputs["netDebt"] = netDebt
+	m.Inputs["sharesOutstanding"] = in.SharesOutstanding
+	m.Inputs["presentValueCashflows"] = round(present)
+	m.Inputs["presentValueTerminal"] = round(terminalPresent)
+	m.Inputs["enterpriseValue"] = round(enterprise)
+
+	if equity <= 0 {
+		return missing(m, "dívida líquida maior que o valor da firma")
+	}
+
+	m.FairValue = number(equity / in.SharesOutstanding)
+	return m
+}
+
+func missing(m Model, reason string) Model {
+	m.Reason = reason
+	return m
+}
+
+// withUpside calcula a margem entre o preço justo e o preço atual
+func withUpside(m Model, price float64) Model {
+	if m.FairValue != nil && price > 0 {
+		m.Upside = number(*m.FairValue/price - 1)
+	}
+	return m
+}
+
+func number(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	v = round(v)
+	return &v
+}
+
+func round(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
@@ -0,0 +1,152 @@
+package calendar
+
+import (
+	"time"
+)
+
+// Location é o fuso da B3 (Brasília, sem horário de verão desde 2019)
+var Location = time.FixedZone("BRT", -3*60*60)
+
+// Session define o horário do pregão regular
+type Session struct {
+	OpenHour, OpenMinute   int
+	CloseHour, CloseMinute int
+}
+
+// DefaultSession é o pregão regular de ações (10h às 18h)
+var DefaultSession = Session{OpenHour: 10, CloseHour: 18}
+
+// B3 é o calendário de pregões da bolsa
+type B3 struct {
+	Session Session
+	extra   map[string]bool // feriados adicionais (AAAA-MM-DD)
+}
+
+// NewB3 cria o calendário com os feriados nacionais e da B3.
+// extraHolidays permite incluir dias sem pregão não previstos (AAAA-MM-DD).
+func NewB3(session Session, extraHolidays ...string) *B3 {
+	extra := make(map[string]bool, len(extraHolidays))
+	for _, d := range extraHolidays {
+		extra[d] = true
+	}
+	return &B3{Session: session, extra: extra}
+}
+
+// Default é o calendário padrão usado pelos pacotes de análise
+var Default = NewB3(DefaultSession)
+
+// IsTradingDay informa se há pregão na data
+func (c *B3) IsTradingDay(t time.Time) bool {
+	t = t.In(Location)
+	if t.Weekday() == time.Saturday || t.Weekday() == time.Sunday {
+		return false
+	}
+	if c.extra[t.Format("2006-01-02")] {
+		return false
+	}
+	return !isHoliday(t)
+}
+
+// SessionBounds retorna abertura e fechamento do pregão do dia de t
+func (c *B3) SessionBounds(t time.Time) (time.Time, time.Time) {
+	t = t.In(Location)
+	open := time.Date(t.Year(), t.Month(), t.Day(), c.Session.OpenHour, c.Session.OpenMinute, 0, 0, Location)
+	close := time.Date(t.Year(), t.Month(), t.Day(), c.Session.CloseHour, c.Session.CloseMinute, 0, 0, Location)
+	return open, close
+}
+
+// InSession informa se t está dentro do pregão regular
+func (c *B3) InSession(t time.Time) bool {
+	if !c.IsTradingDay(t) {
+		return false
+	}
+	open, close := c.SessionBounds(t)
+	return !t.Before(open) && t.Before(close)
+}
+
+// LastClosedSession retorna a data (meia-noite) do último pregão encerrado
+func (c *B3) LastClosedSession(now time.Time) time.Time {
+	now = now.In(Location)
+	day := StartOfDay(now)
+	if _, close := c.SessionBounds(now); !c.IsTradingDay(now) || now.Before(close) {
+		day = c.PreviousTradingDay(day)
+	}
+	return day
+}
+
+// PreviousTradingDay retorna o pregão anterior a t
+func (c *B3) PreviousTradingDay(t time.Time) time.Time {
+	day := StartOfDay(t).AddDate(0, 0, -1)
+	for !c.IsTradingDay(day) {
+		day = day.AddDate(0, 0, -1)
+	}
+	return day
+}
+
+// NextTradingDay retorna o pregão seguinte a t
+func (c *B3) NextTradingDay(t time.Time) time.Time {
+	day := StartOfDay(t).AddDate(0, 0, 1)
+	for !c.IsTradingDay(day) {
+		day = day.AddDate(0, 0, 1)
+	}
+	return day
+}
+
+// StartOfDay retorna a meia-noite (horário de Brasília) do dia de t
+func StartOfDay(t time.Time) time.Time {
+	t = t.In(Location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, Location)
+}
+
+// isHoliday cobre os feriados nacionais e os dias sem pregão da B3
+func isHoliday(t time.Time) bool {
+	y, m, d := t.Date()
+
+	switch {
+	case m == time.January && d == 1, // Confraternização Universal
+		m == time.April && d == 21,    // Tiradentes
+		m == time.May && d == 1,       // Dia do Trabalho
+		m == time.September && d == 7, // Independência
+		m == time.October && d == 12,  // Nossa Senhora Aparecida
+		m == time.November && d == 2,  // Finados
+		m == time.November && d == 15, // Proclamação da República
+		m == time.December && d == 24, // Véspera de Natal (sem pregão)
+		m == time.December && d == 25, // Natal
+		m == time.December && d == 31: // Último dia do ano (sem pregão)
+		return true
+	case m == time.November && d == 20 && y >= 2024: // Consciência Negra (nacional desde 2024)
+		return true
+	}
+
+	easter := easterSunday(y)
+	day := time.Date(y, m, d, 0, 0, 0, 0, Location)
+	switch day.Sub(easter) / (24 * time.Hour) {
+	case -48, -47: // Carnaval
+		return true
+	case -2: // Sexta-feira Santa
+		return true
+	case 60: // Corpus Christi
+		return true
+	}
+
+	return false
+}
+
+// easterSunday calcula o domingo de Páscoa (algoritmo de Meeus/Jones/Butcher)
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b := year / 100
+	c := year % 100
+	d := b / 4
+	e := b % 4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i := c / 4
+	k := c % 4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := ((h + l - 7*m + 114) % 31) + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, Location)
+}
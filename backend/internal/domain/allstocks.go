@@ -1,5 +1,9 @@
 package domain
 
+import "errors"
+
+var ErrSectorNotFound = errors.New("sector not found")
+
 // AllStocksResponse representa a resposta da rota /cotacoes
 type AllStocksResponse struct {
 	Indexes             []MarketIndex   `json:"indexes"`
@@ -7,6 +11,9 @@ type AllStocksResponse struct {
 	AvailableSectors    []string        `json:"availableSectors"`
 	AvailableStockTypes []string        `json:"availableStockTypes"`
 	Pagination          Pagination      `json:"pagination"`
+
+	// Degraded indica que os dados vieram do snapshot (provider indisponível)
+	Degraded bool `json:"degraded,omitempty"`
 }
 
 // MarketIndex representa um índice de mercado (ex: IBOVESPA)
@@ -0,0 +1,55 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrIndexNotFound = errors.New("custom index not found")
+
+// Ponderação dos índices customizados
+const (
+	WeightingEqual  = "equal"  // pesos iguais
+	WeightingCap    = "cap"    // pelo valor de mercado
+	WeightingCustom = "custom" // pesos informados pelo usuário
+)
+
+// Frequência de rebalanceamento dos índices customizados
+const (
+	RebalanceNone      = "none"
+	RebalanceMonthly   = "monthly"
+	RebalanceQuarterly = "quarterly"
+	RebalanceYearly    = "yearly"
+)
+
+// IndexConstituent é um ativo do índice; Weight só vale na ponderação custom
+type IndexConstituent struct {
+	Symbol string  `json:"symbol"`
+	Weight float64 `json:"weight,omitempty"`
+}
+
+// CustomIndex é um índice definido pelo usuário. Só o dono altera ou
+// remove o índice; a leitura é pelo id (símbolo custom:<id>), para que o
+// índice possa ser usado como benchmark
+type CustomIndex struct {
+	ID           string             `json:"id"`
+	Owner        string             `json:"-"`
+	Name         string             `json:"name"`
+	Description  string             `json:"description,omitempty"`
+	Weighting    string             `json:"weighting"`
+	Rebalance    string             `json:"rebalance"`
+	BaseValue    float64            `json:"baseValue"`
+	Constituents []IndexConstituent `json:"constituents"`
+	CreatedAt    time.Time          `json:"createdAt"`
+	UpdatedAt    time.Time          `json:"updatedAt"`
+}
+
+type CustomIndexRepository interface {
+	List(owner string) ([]CustomIndex, error)
+	Get(id string) (*CustomIndex, error)
+	// Create grava um novo índice usando index.ID como base do id, com
+	// sufixo numérico (-2, -3...) quando já existir, e retorna o id usado
+	Create(index CustomIndex) (string, error)
+	Save(index CustomIndex) error
+	Delete(owner, id string) error
+}
@@ -0,0 +1,31 @@
+package domain
+
+import "time"
+
+// ProviderHealth representa o estado de saúde de um provider externo
+type ProviderHealth struct {
+	Name                string     `json:"name"`
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutiveFailures"`
+	LastError           string     `json:"lastError,omitempty"`
+	LastFailureAt       *time.Time `json:"lastFailureAt,omitempty"`
+	OpenedAt            *time.Time `json:"openedAt,omitempty"`
+	RetryAt             *time.Time `json:"retryAt,omitempty"`
+}
+
+// HealthReporter é implementado por componentes que expõem estado no /health
+type HealthReporter interface {
+	Health() ProviderHealth
+}
+
+// ChainStatus representa a ordem efetiva de uma cadeia de fallback
+type ChainStatus struct {
+	Name    string   `json:"name"`
+	Order   []string `json:"order"`
+	Demoted []string `json:"demoted"`
+}
+
+// ChainReporter é implementado pelas cadeias de providers
+type ChainReporter interface {
+	ChainStatus() ChainStatus
+}
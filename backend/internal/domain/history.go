@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+var ErrInvalidRange = errors.New("invalid range")
+
+// HistoryCoverage descreve o intervalo de datas disponível no store local
+type HistoryCoverage struct {
+	From  time.Time `json:"from"`
+	To    time.Time `json:"to"`
+	Count int       `json:"count"`
+}
+
+// HistoryStore guarda candles localmente por símbolo e intervalo
+type HistoryStore interface {
+	Append(symbol, interval string, candles []HistoricalDataPrice) error
+	Range(symbol, interval string, from, to time.Time) ([]HistoricalDataPrice, error)
+	Coverage(symbol, interval string) (HistoryCoverage, error)
+}
+
+// RangeStart converte o parâmetro "range" da brapi (1d, 5d, 1mo, 3mo, 6mo,
+// 1y, 2y, 5y, 10y, ytd, max) na data inicial correspondente.
+// Para "max" retorna o tempo zero.
+func RangeStart(rangeParam string, now time.Time) (time.Time, error) {
+	switch strings.ToLower(strings.TrimSpace(rangeParam)) {
+	case "1d":
+		return now.AddDate(0, 0, -1), nil
+	case "5d":
+		return now.AddDate(0, 0, -5), nil
+	case "1mo":
+		return now.AddDate(0, -1, 0), nil
+	case "3mo":
+		return now.AddDate(0, -3, 0), nil
+	case "6mo":
+		return now.AddDate(0, -6, 0), nil
+	case "1y":
+		return now.AddDate(-1, 0, 0), nil
+	case "2y":
+		return now.AddDate(-2, 0, 0), nil
+	case "5y":
+		return now.AddDate(-5, 0, 0), nil
+	case "10y":
+		return now.AddDate(-10, 0, 0), nil
+	case "ytd":
+		return time.Date(now.Year(), time.January, 1, 0, 0, 0, 0, now.Location()), nil
+	case "max":
+		return time.Time{}, nil
+	}
+
+	return time.Time{}, fmt.Errorf("%w: %q", ErrInvalidRange, rangeParam)
+}
@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrPortfolioNotFound   = errors.New("portfolio not found")
+	ErrTransactionNotFound = errors.New("transaction not found")
+)
+
+// Tipos de lançamento da carteira
+const (
+	TransactionBuy      = "buy"      // compra
+	TransactionSell     = "sell"     // venda
+	TransactionSplit    = "split"    // desdobramento/grupamento (Ratio)
+	TransactionBonus    = "bonus"    // bonificação em ações
+	TransactionDividend = "dividend" // proventos recebidos em dinheiro
+)
+
+// Classes de ativo, usadas na apuração do IR
+const (
+	AssetStock = "stock" // ações e units
+	AssetFII   = "fii"   // fundos imobiliários
+	AssetETF   = "etf"   // fundos de índice
+	AssetBDR   = "bdr"   // recibos de ações estrangeiras
+)
+
+// Portfolio é uma carteira de um usuário. Os lançamentos são guardados
+// junto da carteira, mas expostos por rotas próprias.
+type Portfolio struct {
+	ID           string        `json:"id"`
+	Owner        string        `json:"-"`
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	Transactions []Transaction `json:"-"`
+	CreatedAt    time.Time     `json:"createdAt"`
+	UpdatedAt    time.Time     `json:"updatedAt"`
+}
+
+// Transaction é um lançamento da carteira. Os campos usados dependem do tipo:
+//   - buy/sell: Quantity, Price e Fees (corretagem, emolumentos)
+//   - split: Ratio (2 = desdobramento 1:2, 0.1 = grupamento 10:1) ou
+//     Quantity de ações recebidas no desdobramento
+//   - bonus: Ratio (0.1 = 10%) ou Quantity, e Price como custo atribuído
+//   - dividend: Amount recebido (ou Quantity x Price por ação)
+type Transaction struct {
+	ID             string    `json:"id"`
+	Type           string    `json:"type"`
+	Symbol         string    `json:"symbol"`
+	Date           string    `json:"date"` // AAAA-MM-DD
+	Quantity       float64   `json:"quantity,omitempty"`
+	Price          float64   `json:"price,omitempty"`
+	Fees           float64   `json:"fees,omitempty"`
+	Ratio          float64   `json:"ratio,omitempty"`
+	Amount         float64   `json:"amount,omitempty"`
+	AssetClass     string    `json:"assetClass,omitempty"` // opcional; sem ela, vem do cadastro
+	Note           string    `json:"note,omitempty"`
+	IdempotencyKey string    `json:"idempotencyKey,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+type PortfolioRepository interface {
+	List(owner string) ([]Portfolio, error)
+	Get(owner, id string) (*Portfolio, error)
+	Save(portfolio Portfolio) error
+	// Update aplica fn e grava a carteira atomicamente (read-modify-write)
+	Update(owner, id string, fn func(*Portfolio) error) (*Portfolio, error)
+	Delete(owner, id string) error
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// Séries de taxas de referência
+const (
+	RateCDI  = "cdi"  // % ao dia útil
+	RateIPCA = "ipca" // % ao mês (data = primeiro dia do mês)
+)
+
+// RatePoint é a taxa de um período, em %
+type RatePoint struct {
+	Date  time.Time
+	Value float64
+}
+
+// RateProvider fornece séries históricas de taxas (CDI, IPCA)
+type RateProvider interface {
+	Series(name string, from, to time.Time) ([]RatePoint, error)
+}
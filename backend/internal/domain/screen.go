@@ -0,0 +1,26 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrScreenNotFound = errors.New("screen not found")
+
+// SavedScreen é um filtro do screener salvo com nome
+type SavedScreen struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description,omitempty"`
+	Expression  string    `json:"expression"`
+	Sort        string    `json:"sort,omitempty"`
+	Order       string    `json:"order,omitempty"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+type ScreenRepository interface {
+	List() ([]SavedScreen, error)
+	Get(name string) (*SavedScreen, error)
+	Save(screen SavedScreen) error
+	Delete(name string) error
+}
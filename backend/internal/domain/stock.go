@@ -33,6 +33,8 @@ type Stock struct {
 
 	UsedInterval string `json:"usedInterval"`
 	UsedRange    string `json:"usedRange"`
+	// Adjustment indica o ajuste aplicado ao histórico (none, splits, total)
+	Adjustment string `json:"adjustment,omitempty"`
 
 	HistoricalDataPrice []HistoricalDataPrice `json:"historicalDataPrice"`
 	ValidRanges         []string              `json:"validRanges"`
@@ -42,9 +44,14 @@ type Stock struct {
 	SummaryProfile      SummaryProfile `json:"summaryProfile"`
 	FinancialData       FinancialData  `json:"financialData"`
 
+	SharesOutstanding int64 `json:"sharesOutstanding,omitempty"`
+
 	PriceEarnings    float64       `json:"priceEarnings"`
 	EarningsPerShare float64       `json:"earningsPerShare"`
 	DividendsData    DividendsData `json:"dividendsData"`
+
+	// Degraded indica que os dados vieram do cache (provider indisponível)
+	Degraded bool `json:"degraded,omitempty"`
 }
 
 type HistoricalDataPrice struct {
@@ -152,6 +159,10 @@ type CashDividend struct {
 	Label         string     `json:"label"`
 	LastDatePrior time.Time  `json:"lastDatePrior"`
 	Remarks       string     `json:"remarks"`
+
+	// Valores calculados: alíquota de IR retida na fonte e valor líquido por ação
+	WithholdingRate float64 `json:"withholdingRate"`
+	NetRate         float64 `json:"netRate"`
 }
 
 type StockDividend struct {
@@ -0,0 +1,34 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrWatchlistNotFound = errors.New("watchlist not found")
+
+// Watchlist é uma lista de acompanhamento de um usuário; a ordem dos
+// itens é a ordem de exibição
+type Watchlist struct {
+	ID        string          `json:"id"`
+	Owner     string          `json:"-"`
+	Name      string          `json:"name"`
+	Items     []WatchlistItem `json:"items"`
+	CreatedAt time.Time       `json:"createdAt"`
+	UpdatedAt time.Time       `json:"updatedAt"`
+}
+
+type WatchlistItem struct {
+	Symbol  string    `json:"symbol"`
+	Note    string    `json:"note,omitempty"`
+	AddedAt time.Time `json:"addedAt"`
+}
+
+type WatchlistRepository interface {
+	List(owner string) ([]Watchlist, error)
+	Get(owner, id string) (*Watchlist, error)
+	Save(watchlist Watchlist) error
+	// Update aplica fn e grava a watchlist atomicamente (read-modify-write)
+	Update(owner, id string, fn func(*Watchlist) error) (*Watchlist, error)
+	Delete(owner, id string) error
+}
@@ -0,0 +1,192 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"cotacoes/internal/domain"
+)
+
+// Colunas do extrato de negociação da Área do Investidor
+const (
+	colTradeDate     = "Data do Negócio"
+	colTradeType     = "Tipo de Movimentação"
+	colTradeSymbol   = "Código de Negociação"
+	colTradeQuantity = "Quantidade"
+	colTradePrice    = "Preço"
+	colTradeValue    = "Valor"
+)
+
+// Colunas do extrato de movimentação da Área do Investidor
+const (
+	colMoveDirection = "Entrada/Saída"
+	colMoveDate      = "Data"
+	colMoveKind      = "Movimentação"
+	colMoveProduct   = "Produto"
+	colMoveQuantity  = "Quantidade"
+	colMovePrice     = "Preço unitário"
+	colMoveValue     = "Valor da Operação"
+)
+
+// parseB3Trades converte o extrato de negociação: cada linha é uma compra
+// ou venda na data do pregão. Códigos do fracionário (PETR4F) entram como
+// o ativo do lote padrão.
+func parseB3Trades(table Table) ([]Row, error) {
+	cols, err := requireColumns(table, colTradeDate, colTradeType, colTradeSymbol, colTradeQuantity, colTradePrice)
+	if err != nil {
+		return nil, err
+	}
+	valueCol := table.Column(colTradeValue)
+	decimal := b3Decimal(table)
+
+	rows := make([]Row, 0, len(table.Rows))
+	for i, r := range table.Rows {
+		line := table.Lines[i]
+
+		var tx domain.Transaction
+		switch fold(cell(r, cols[1])) {
+		case "compra":
+			tx.Type = domain.TransactionBuy
+		case "venda":
+			tx.Type = domain.TransactionSell
+		default:
+			rows = append(rows, Row{Line: line, Error: fmt.Sprintf("tipo de movimentação %q (esperado Compra ou Venda)", cell(r, cols[1]))})
+			continue
+		}
+
+		date, err := parseDate(cell(r, cols[0]), "")
+		if err != nil {
+			rows = append(rows, Row{Line: line, Error: err.Error()})
+			continue
+		}
+		quantity, err1 := parseNumber(cell(r, cols[3]), decimal)
+		price, err2 := parseNumber(cell(r, cols[4]), decimal)
+		if err := firstError(err1, err2); err != nil {
+			rows = append(rows, Row{Line: line, Error: err.Error()})
+			continue
+		}
+		// Sem preço unitário, deriva do valor total
+		if price == 0 && quantity > 0 && valueCol >= 0 {
+			if value, err := parseNumber(cell(r, valueCol), decimal); err == nil {
+				price = value / quantity
+			}
+		}
+
+		tx.Symbol = cell(r, cols[2])
+		tx.Date = date
+		tx.Quantity = quantity
+		tx.Price = price
+		rows = append(rows, finish(line, tx))
+	}
+	return rows, nil
+}
+
+// parseB3Movements converte o extrato de movimentação. Dele vêm os
+// proventos e eventos societários; as liquidações de compra e venda são
+// ignoradas porque chegam com a data da liquidação (D+2) e já constam do
+// extrato de negociação.
+func parseB3Movements(table Table) ([]Row, error) {
+	cols, err := requireColumns(table, colMoveDirection, colMoveDate, colMoveKind, colMoveProduct, colMoveQuantity)
+	if err != nil {
+		return nil, err
+	}
+	priceCol := table.Column(colMovePrice)
+	valueCol := table.Column(colMoveValue)
+	decimal := b3Decimal(table)
+
+	rows := make([]Row, 0, len(table.Rows))
+	for i, r := range table.Rows {
+		line := table.Lines[i]
+		kind := cell(r, cols[2])
+		credit := fold(cell(r, cols[0])) == "credito"
+
+		var tx domain.Transaction
+		switch fold(kind) {
+		case "dividendo", "juros sobre capital proprio", "rendimento", "restituicao de capital":
+			if !credit {
+				rows = append(rows, Row{Line: line, Skipped: kind + " a débito"})
+				continue
+			}
+			tx.Type = domain.TransactionDividend
+		case "desdobro":
+			tx.Type = domain.TransactionSplit
+		case "bonificacao em ativos":
+			tx.Type = domain.TransactionBonus
+		case "transferencia - liquidacao", "compra", "venda":
+			rows = append(rows, Row{Line: line, Skipped: "negociação: importe o extrato de negociação"})
+			continue
+		case "grupamento":
+			rows = append(rows, Row{Line: line, Skipped: "grupamento: registre manualmente com a proporção (ratio)"})
+			continue
+		default:
+			rows = append(rows, Row{Line: line, Skipped: fmt.Sprintf("movimentação %q não altera a carteira", kind)})
+			continue
+		}
+
+		date, err := parseDate(cell(r, cols[1]), "")
+		if err != nil {
+			rows = append(rows, Row{Line: line, Error: err.Error()})
+			continue
+		}
+		quantity, err1 := parseNumber(cell(r, cols[4]), decimal)
+		price, err2 := parseNumber(cell(r, priceCol), decimal)
+		value, err3 := parseNumber(cell(r, valueCol), decimal)
+		if err := firstError(err1, err2, err3); err != nil {
+			rows = append(rows, Row{Line: line, Error: err.Error()})
+			continue
+		}
+
+		tx.Symbol = productSymbol(cell(r, cols[3]))
+		tx.Date = date
+		tx.Note = kind
+		switch tx.Type {
+		case domain.TransactionDividend:
+			tx.Amount = value
+			if tx.Amount == 0 {
+				tx.Quantity, tx.Price = quantity, price
+			}
+		case domain.TransactionSplit:
+			tx.Quantity = quantity
+		case domain.TransactionBonus:
+			tx.Quantity = quantity
+			tx.Price = price
+		}
+		rows = append(rows, finish(line, tx))
+	}
+	return rows, nil
+}
+
+// b3Decimal é o separador decimal das exportações da B3: o CSV usa o
+// formato brasileiro ("1.000" são mil); no XLSX as células numéricas vêm
+// sem formatação e o separador é detectado
+func b3Decimal(table Table) string {
+	if table.Spreadsheet {
+		return ""
+	}
+	return ","
+}
+
+// requireColumns localiza as colunas obrigatórias pelo cabeçalho
+func requireColumns(table Table, names ...string) ([]int, error) {
+	cols := make([]int, len(names))
+	var missing []string
+	for i, name := range names {
+		cols[i] = table.Column(name)
+		if cols[i] < 0 {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("%w: colunas ausentes: %s", ErrInvalidFile, strings.Join(missing, ", "))
+	}
+	return cols, nil
+}
+
+func firstError(errs ...error) error {
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Package importer converte extratos em lançamentos de carteira: as
+// exportações (CSV/XLSX) da Área do Investidor da B3 — movimentação e
+// negociação — e CSVs de corretoras descritos por um mapeamento de colunas.
+//
+// Cada linha vira um Row com o lançamento normalizado, o motivo de ter sido
+// ignorada (eventos que não afetam a carteira) ou o erro encontrado.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"cotacoes/internal/analytics/portfolio"
+	"cotacoes/internal/domain"
+)
+
+// Formatos de importação
+const (
+	FormatB3Movements = "b3-movimentacao"
+	FormatB3Trades    = "b3-negociacao"
+	FormatCSV         = "csv"
+)
+
+// maxXMLSize limita cada parte descompactada do XLSX
+const maxXMLSize = 64 << 20
+
+// xlsxMaxColumn é a última coluna do Excel (XFD)
+const xlsxMaxColumn = 16384
+
+// maxColumns limita a largura de cada linha importada: as planilhas
+// aceitas têm poucas colunas e uma célula distante não deve alocar
+// milhares de campos vazios
+const maxColumns = 256
+
+var (
+	ErrInvalidFile    = errors.New("arquivo inválido")
+	ErrInvalidFormat  = errors.New("formato de importação inválido")
+	ErrInvalidMapping = errors.New("mapeamento inválido")
+)
+
+// Row é uma linha do arquivo após a conversão. Exatamente um entre
+// Transaction, Skipped e Error é preenchido.
+type Row struct {
+	Line        int                 `json:"line"`
+	Transaction *domain.Transaction `json:"transaction,omitempty"`
+	Skipped     string              `json:"skipped,omitempty"`
+	Error       string              `json:"error,omitempty"`
+}
+
+// Parse lê o arquivo no formato indicado. mapping só é usado no formato csv.
+func Parse(format string, data []byte, mapping *Mapping) ([]Row, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case FormatB3Movements:
+		table, err := ReadTable(data)
+		if err != nil {
+			return nil, err
+		}
+		return parseB3Movements(table)
+	case FormatB3Trades:
+		table, err := ReadTable(data)
+		if err != nil {
+			return nil, err
+		}
+		return parseB3Trades(table)
+	case FormatCSV:
+		if mapping == nil {
+			return nil, fmt.Errorf("%w: informe o mapeamento de colunas", ErrInvalidMapping)
+		}
+		if err := mapping.Validate(); err != nil {
+			return nil, err
+		}
+		table, err := readTable(data, mapping.delimiter())
+		if err != nil {
+			return nil, err
+		}
+		return parseMapped(table, *mapping)
+	}
+	return nil, fmt.Errorf("%w: %q (use %s, %s ou %s)", ErrInvalidFormat, format, FormatB3Movements, FormatB3Trades, FormatCSV)
+}
+
+// finish normaliza o lançamento da linha e registra o erro de validação
+func finish(line int, tx domain.Transaction) Row {
+	if err := portfolio.Normalize(&tx); err != nil {
+		return Row{Line: line, Error: err.Error()}
+	}
+	return Row{Line: line, Transaction: &tx}
+}
+
+// cell devolve o valor da coluna na linha (vazio se a coluna não existe)
+func cell(row []string, col int) string {
+	if col < 0 || col >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[col])
+}
+
+// parseNumber aceita "1.234,56", "1234.56", "R$ 10,00" e "-" (zero).
+// Com decimal "," o ponto é separador de milhar e vice-versa; sem decimal
+// informado, o último separador presente é o decimal.
+func parseNumber(raw string, decimal string) (float64, error) {
+	s := strings.TrimSpace(raw)
+	s = strings.TrimPrefix(s, "R$")
+	s = strings.ReplaceAll(s, " ", "")
+	s = strings.ReplaceAll(s, "\u00a0", "")
+	if s == "" || s == "-" {
+		return 0, nil
+	}
+
+	if decimal == "" {
+		decimal = "."
+		if strings.LastIndex(s, ",") > strings.LastIndex(s, ".") {
+			decimal = ","
+		}
+	}
+	if decimal == "," {
+		s = strings.ReplaceAll(s, ".", "")
+		s = strings.ReplaceAll(s, ",", ".")
+	} else {
+		s = strings.ReplaceAll(s, ",", "")
+	}
+
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0, fmt.Errorf("número inválido: %q", raw)
+	}
+	return v, nil
+}
+
+// excelEpoch é a data zero dos números de série de datas do Excel
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// parseDate aceita o layout informado, DD/MM/AAAA, AAAA-MM-DD e o número
+// de série do Excel (células de data do XLSX) e devolve AAAA-MM-DD
+func parseDate(raw, layout string) (string, error) {
+	s := strings.TrimSpace(raw)
+	layouts := []string{"02/01/2006", portfolio.DateLayout, "02/01/06", "2006-01-02T15:04:05"}
+	if layout != "" {
+		layouts = append([]string{layout}, layouts...)
+	}
+	for _, l := range layouts {
+		if t, err := time.Parse(l, s); err == nil {
+			return t.Format(portfolio.DateLayout), nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(s, 64); err == nil && serial > 0 && serial < 100000 {
+		return excelEpoch.AddDate(0, 0, int(serial)).Format(portfolio.DateLayout), nil
+	}
+	return "", fmt.Errorf("data inválida: %q", raw)
+}
+
+// productSymbol extrai o código do ativo de descrições como
+// "PETR4 - PETROLEO BRASILEIRO S.A. PETROBRAS"
+func productSymbol(product string) string {
+	code, _, _ := strings.Cut(strings.TrimSpace(product), " - ")
+	if fields := strings.Fields(code); len(fields) > 0 {
+		return portfolio.NormalizeSymbol(fields[0])
+	}
+	return ""
+}
+
+// fold normaliza textos de cabeçalhos e categorias para comparação:
+// minúsculas, sem acentos e com espaços simples
+func fold(s string) string {
+	replacer := strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+		"é", "e", "ê", "e", "è", "e",
+		"í", "i", "î", "i",
+		"ó", "o", "ô", "o", "õ", "o", "ö", "o",
+		"ú", "u", "û", "u", "ü", "u",
+		"ç", "c",
+	)
+	return strings.Join(strings.Fields(replacer.Replace(strings.ToLower(s))), " ")
+}
@@ -0,0 +1,368 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"cotacoes/internal/domain"
+)
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		raw     string
+		decimal string
+		want    float64
+	}{
+		{"1.000", ",", 1000},
+		{"1.234,56", ",", 1234.56},
+		{"R$ 10,00", ",", 10},
+		{"-", ",", 0},
+		{"", ",", 0},
+		{"1,234.56", ".", 1234.56},
+		{"1.234,56", "", 1234.56},
+		{"1234.56", "", 1234.56},
+		{"1000.5", "", 1000.5},
+		{"-12,5", ",", -12.5},
+	}
+	for _, tt := range tests {
+		got, err := parseNumber(tt.raw, tt.decimal)
+		if err != nil {
+			t.Errorf("parseNumber(%q, %q): %v", tt.raw, tt.decimal, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("parseNumber(%q, %q) = %v, esperado %v", tt.raw, tt.decimal, got, tt.want)
+		}
+	}
+
+	if _, err := parseNumber("abc", ","); err == nil {
+		t.Error("parseNumber(abc): esperado erro")
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		raw    string
+		layout string
+		want   string
+	}{
+		{"05/03/2024", "", "2024-03-05"},
+		{"2024-03-05", "", "2024-03-05"},
+		{"05/03/24", "", "2024-03-05"},
+		{"45292", "", "2024-01-01"}, // número de série do Excel
+		{"03-05-2024", "01-02-2006", "2024-03-05"},
+	}
+	for _, tt := range tests {
+		got, err := parseDate(tt.raw, tt.layout)
+		if err != nil || got != tt.want {
+			t.Errorf("parseDate(%q) = %q, %v; esperado %q", tt.raw, got, err, tt.want)
+		}
+	}
+	if _, err := parseDate("31/02/2024", ""); err == nil {
+		t.Error("parseDate(31/02/2024): esperado erro")
+	}
+}
+
+func TestNormalizeFractionalSymbol(t *testing.T) {
+	tests := map[string]string{
+		"PETR4F":                           "PETR4",
+		"taee11f":                          "TAEE11",
+		"PETR4":                            "PETR4",
+		"PETR4 - PETROLEO BRASILEIRO S.A.": "PETR4",
+		"BOVA11F - ISHARES BOVA CI":        "BOVA11",
+	}
+	for product, want := range tests {
+		if got := productSymbol(product); got != want {
+			t.Errorf("productSymbol(%q) = %q, esperado %q", product, got, want)
+		}
+	}
+}
+
+// txRows devolve os lançamentos das linhas, falhando em erros inesperados
+func txRows(t *testing.T, rows []Row) []domain.Transaction {
+	t.Helper()
+	var txs []domain.Transaction
+	for _, r := range rows {
+		if r.Error != "" {
+			t.Errorf("linha %d: %s", r.Line, r.Error)
+		}
+		if r.Transaction != nil {
+			txs = append(txs, *r.Transaction)
+		}
+	}
+	return txs
+}
+
+func TestParseB3Trades(t *testing.T) {
+	csv := "\xef\xbb\xbfData do Negócio;Tipo de Movimentação;Mercado;Código de Negociação;Quantidade;Preço;Valor\n" +
+		"05/03/2024;Compra;Mercado à Vista;PETR4;1.000;R$ 38,50;R$ 38.500,00\n" +
+		"05/03/2024;Compra;Mercado Fracionário;PETR4F;15;38,40;576,00\n" +
+		"\n" +
+		"12/03/2024;Venda;Mercado à Vista;PETR4;200;;8.200,00\n" +
+		"13/03/2024;Transferência;Mercado à Vista;PETR4;10;40,00;400,00\n"
+
+	rows, err := Parse(FormatB3Trades, []byte(csv), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 4 {
+		t.Fatalf("%d linhas, esperado 4", len(rows))
+	}
+	if rows[3].Error == "" || rows[3].Line != 6 {
+		t.Errorf("linha de transferência: %+v, esperado erro na linha 6", rows[3])
+	}
+	rows = rows[:3]
+
+	txs := txRows(t, rows)
+	want := []domain.Transaction{
+		{Type: domain.TransactionBuy, Symbol: "PETR4", Date: "2024-03-05", Quantity: 1000, Price: 38.5},
+		{Type: domain.TransactionBuy, Symbol: "PETR4", Date: "2024-03-05", Quantity: 15, Price: 38.4},
+		// Sem preço: valor / quantidade
+		{Type: domain.TransactionSell, Symbol: "PETR4", Date: "2024-03-12", Quantity: 200, Price: 41},
+	}
+	for i, w := range want {
+		got := txs[i]
+		if got.Type != w.Type || got.Symbol != w.Symbol || got.Date != w.Date || got.Quantity != w.Quantity || got.Price != w.Price {
+			t.Errorf("lançamento %d = %+v, esperado %+v", i, got, w)
+		}
+	}
+}
+
+func TestParseB3Movements(t *testing.T) {
+	csv := "Entrada/Saída,Data,Movimentação,Produto,Instituição,Quantidade,Preço unitário,Valor da Operação\n" +
+		"Credito,15/03/2024,Dividendo,PETR4 - PETROLEO BRASILEIRO S.A. PETROBRAS,XP,1015,\"0,50\",\"507,50\"\n" +
+		"Credito,20/03/2024,Juros Sobre Capital Próprio,ITSA4 - ITAUSA S.A.,XP,\"1.000\",-,\"85,00\"\n" +
+		"Debito,20/03/2024,Juros Sobre Capital Próprio,ITSA4 - ITAUSA S.A.,XP,\"1.000\",-,\"12,75\"\n" +
+		"Credito,22/03/2024,Desdobro,ITSA4 - ITAUSA S.A.,XP,100,-,-\n" +
+		"Credito,25/03/2024,Bonificação em Ativos,ITSA4 - ITAUSA S.A.,XP,11,\"18,00\",-\n" +
+		"Credito,07/03/2024,Transferência - Liquidação,PETR4 - PETROLEO BRASILEIRO S.A. PETROBRAS,XP,1000,\"38,50\",\"38.500,00\"\n" +
+		"Credito,28/03/2024,Grupamento,MGLU3 - MAGAZINE LUIZA S.A.,XP,10,-,-\n"
+
+	rows, err := Parse(FormatB3Movements, []byte(csv), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 7 {
+		t.Fatalf("%d linhas, esperado 7", len(rows))
+	}
+	for _, i := range []int{2, 5, 6} {
+		if rows[i].Skipped == "" {
+			t.Errorf("linha %d: %+v, esperado ignorada", rows[i].Line, rows[i])
+		}
+	}
+
+	txs := txRows(t, rows)
+	if len(txs) != 4 {
+		t.Fatalf("%d lançamentos, esperado 4", len(txs))
+	}
+	checks := []struct {
+		typ      string
+		symbol   string
+		amount   float64
+		quantity float64
+		price    float64
+	}{
+		{domain.TransactionDividend, "PETR4", 507.5, 0, 0},
+		{domain.TransactionDividend, "ITSA4", 85, 0, 0},
+		{domain.TransactionSplit, "ITSA4", 0, 100, 0},
+		{domain.TransactionBonus, "ITSA4", 0, 11, 18},
+	}
+	for i, c := range checks {
+		got := txs[i]
+		if got.Type != c.typ || got.Symbol != c.symbol || got.Amount != c.amount || got.Quantity != c.quantity || got.Price != c.price {
+			t.Errorf("lançamento %d = %+v, esperado %+v", i, got, c)
+		}
+	}
+}
+
+func TestParseB3MissingColumns(t *testing.T) {
+	_, err := Parse(FormatB3Trades, []byte("Data;Ativo\n05/03/2024;PETR4\n"), nil)
+	if err == nil || !strings.Contains(err.Error(), "Código de Negociação") {
+		t.Errorf("erro = %v, esperado colunas ausentes", err)
+	}
+	if _, err := Parse("nota-de-corretagem", []byte("x"), nil); err == nil {
+		t.Error("esperado erro de formato")
+	}
+}
+
+// xlsx monta uma planilha mínima: células com "s:" viram shared strings,
+// as demais são numéricas
+func xlsx(t *testing.T, rows [][]string) []byte {
+	t.Helper()
+
+	var shared []string
+	var sheet strings.Builder
+	sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for _, row := range rows {
+		sheet.WriteString("<row>")
+		for _, v := range row {
+			if s, ok := strings.CutPrefix(v, "s:"); ok {
+				shared = append(shared, s)
+				sheet.WriteString(`<c t="s"><v>` + strconv.Itoa(len(shared)-1) + `</v></c>`)
+				continue
+			}
+			sheet.WriteString("<c><v>" + v + "</v></c>")
+		}
+		sheet.WriteString("</row>")
+	}
+	sheet.WriteString("</sheetData></worksheet>")
+
+	var sst strings.Builder
+	sst.WriteString(`<sst xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	for _, s := range shared {
+		sst.WriteString("<si><t>" + s + "</t></si>")
+	}
+	sst.WriteString("</sst>")
+
+	return zipFiles(t, map[string]string{
+		"xl/worksheets/sheet1.xml": sheet.String(),
+		"xl/sharedStrings.xml":     sst.String(),
+	})
+}
+
+func zipFiles(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range files {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		w.Write([]byte(content))
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestParseB3TradesXLSX(t *testing.T) {
+	data := xlsx(t, [][]string{
+		{"s:Data do Negócio", "s:Tipo de Movimentação", "s:Código de Negociação", "s:Quantidade", "s:Preço"},
+		{"45356", "s:Compra", "s:VALE3F", "7", "62.35"},
+	})
+
+	rows, err := Parse(FormatB3Trades, data, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := txRows(t, rows)
+	if len(txs) != 1 {
+		t.Fatalf("%d lançamentos, esperado 1", len(txs))
+	}
+	got := txs[0]
+	if got.Symbol != "VALE3" || got.Date != "2024-03-05" || got.Quantity != 7 || got.Price != 62.35 {
+		t.Errorf("lançamento = %+v", got)
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref  string
+		want int
+		ok   bool
+	}{
+		{"A1", 0, true},
+		{"C12", 2, true},
+		{"AA3", 26, true},
+		{"XFD1", 16383, true},
+		{"XFE1", 0, false},
+		{"ZZZZZ1", 0, false},
+		{"a1", 0, false},
+		{"1", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := columnIndex(tt.ref)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("columnIndex(%q) = %d, %v; esperado %d, %v", tt.ref, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestReadXLSXRejectsBadRefs(t *testing.T) {
+	for _, ref := range []string{"a1", "ZZZZZ1", "XFD1"} {
+		data := zipFiles(t, map[string]string{
+			"xl/worksheets/sheet1.xml": `<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>` +
+				`<row r="1"><c r="` + ref + `"><v>1</v></c></row></sheetData></worksheet>`,
+		})
+		if _, _, err := readXLSX(data); !errors.Is(err, ErrInvalidFile) {
+			t.Errorf("ref %s: erro = %v, esperado ErrInvalidFile", ref, err)
+		}
+	}
+}
+
+func TestParseMapped(t *testing.T) {
+	csv := "Data;C/V;Ativo;Qtd;Preço;Taxas\n" +
+		"05/03/2024;C;PETR4F;1.000;38,50;-4,90\n" +
+		"06/03/2024;V;PETR4;200;41,00;1,10\n" +
+		"07/03/2024;X;PETR4;1;1,00;0\n"
+	mapping := &Mapping{
+		Decimal:    ",",
+		DateFormat: "DD/MM/YYYY",
+		Columns:    MappingColumns{Date: "Data", Type: "C/V", Symbol: "Ativo", Quantity: "Qtd", Price: "Preço", Fees: "Taxas"},
+		Types:      map[string]string{"C": "buy", "V": "Sell"},
+	}
+
+	rows, err := Parse(FormatCSV, []byte(csv), mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 || rows[2].Skipped == "" {
+		t.Fatalf("linhas = %+v, esperado a terceira ignorada (tipo não mapeado)", rows)
+	}
+	txs := txRows(t, rows)
+	if txs[0].Type != domain.TransactionBuy || txs[0].Symbol != "PETR4" || txs[0].Quantity != 1000 || txs[0].Fees != 4.9 {
+		t.Errorf("compra = %+v", txs[0])
+	}
+	if txs[1].Type != domain.TransactionSell || txs[1].Price != 41 {
+		t.Errorf("venda = %+v", txs[1])
+	}
+}
+
+func TestParseMappedSignedQuantity(t *testing.T) {
+	csv := "date,ticker,qty,price\n2024-03-05,BBAS3,100,27.5\n2024-03-06,BBAS3,-40,28\n"
+	mapping := &Mapping{
+		Columns:        MappingColumns{Date: "date", Symbol: "ticker", Quantity: "qty", Price: "price"},
+		SignedQuantity: true,
+	}
+
+	rows, err := Parse(FormatCSV, []byte(csv), mapping)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txs := txRows(t, rows)
+	if len(txs) != 2 || txs[0].Type != domain.TransactionBuy || txs[1].Type != domain.TransactionSell || txs[1].Quantity != 40 {
+		t.Errorf("lançamentos = %+v", txs)
+	}
+}
+
+func TestMappingValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		mapping Mapping
+	}{
+		{"sem date", Mapping{Columns: MappingColumns{Symbol: "a"}, DefaultType: "buy"}},
+		{"sem tipo", Mapping{Columns: MappingColumns{Date: "d", Symbol: "a"}}},
+		{"tipo inválido", Mapping{Columns: MappingColumns{Date: "d", Symbol: "a", Type: "t"}, Types: map[string]string{"C": "compra"}}},
+		{"delimitador inválido", Mapping{Columns: MappingColumns{Date: "d", Symbol: "a"}, DefaultType: "buy", Delimiter: "#"}},
+		{"decimal inválido", Mapping{Columns: MappingColumns{Date: "d", Symbol: "a"}, DefaultType: "buy", Decimal: ";"}},
+	}
+	for _, tt := range tests {
+		if err := tt.mapping.Validate(); err == nil {
+			t.Errorf("%s: esperado erro", tt.name)
+		}
+	}
+
+	if _, err := Parse(FormatCSV, []byte("Data;Ativo\n"), &Mapping{
+		Columns:     MappingColumns{Date: "Data", Symbol: "Ativo", Price: "Preço"},
+		DefaultType: "dividend",
+	}); err == nil {
+		t.Error("coluna inexistente: esperado erro")
+	}
+}
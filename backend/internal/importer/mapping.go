@@ -0,0 +1,201 @@
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	"cotacoes/internal/domain"
+)
+
+// Mapping descreve o CSV de uma corretora: quais colunas (pelo nome do
+// cabeçalho) têm cada campo do lançamento e como ler tipos, números e datas.
+//
+//	{"delimiter": ";", "decimal": ",", "dateFormat": "DD/MM/YYYY",
+//	 "columns": {"date": "Data", "type": "C/V", "symbol": "Ativo",
+//	             "quantity": "Qtd", "price": "Preço", "fees": "Taxas"},
+//	 "types": {"C": "buy", "V": "sell"}}
+type Mapping struct {
+	Delimiter string `json:"delimiter"` // vazio detecta ";" ou ","
+	// Decimal é "," ou "."; vazio detecta pelo último separador, o que lê
+	// "1.000" como 1: informe "," para CSVs no formato brasileiro
+	Decimal    string            `json:"decimal"`
+	DateFormat string            `json:"dateFormat"` // ex: DD/MM/YYYY
+	Columns    MappingColumns    `json:"columns"`
+	Types      map[string]string `json:"types"` // valor da coluna type -> tipo
+	// DefaultType vale quando não há coluna de tipo (ex: "dividend")
+	DefaultType string `json:"defaultType"`
+	// SignedQuantity: sem coluna de tipo, quantidade negativa é venda
+	SignedQuantity bool `json:"signedQuantity"`
+}
+
+type MappingColumns struct {
+	Date       string `json:"date"`
+	Type       string `json:"type"`
+	Symbol     string `json:"symbol"`
+	Quantity   string `json:"quantity"`
+	Price      string `json:"price"`
+	Fees       string `json:"fees"`
+	Amount     string `json:"amount"`
+	Ratio      string `json:"ratio"`
+	AssetClass string `json:"assetClass"`
+	Note       string `json:"note"`
+}
+
+// Validate confere os campos obrigatórios e os tipos mapeados
+func (m *Mapping) Validate() error {
+	if strings.TrimSpace(m.Columns.Date) == "" || strings.TrimSpace(m.Columns.Symbol) == "" {
+		return fmt.Errorf("%w: informe as colunas date e symbol", ErrInvalidMapping)
+	}
+	if m.Columns.Type == "" && m.DefaultType == "" && !m.SignedQuantity {
+		return fmt.Errorf("%w: informe columns.type, defaultType ou signedQuantity", ErrInvalidMapping)
+	}
+	switch m.Delimiter {
+	case "", ";", ",", "\t", "|":
+	default:
+		return fmt.Errorf("%w: delimiter %q", ErrInvalidMapping, m.Delimiter)
+	}
+	switch m.Decimal {
+	case "", ",", ".":
+	default:
+		return fmt.Errorf("%w: decimal %q (use \",\" ou \".\")", ErrInvalidMapping, m.Decimal)
+	}
+
+	types := make(map[string]string, len(m.Types))
+	for raw, t := range m.Types {
+		t = strings.ToLower(strings.TrimSpace(t))
+		if !validType(t) {
+			return fmt.Errorf("%w: tipo %q para %q", ErrInvalidMapping, t, raw)
+		}
+		types[fold(raw)] = t
+	}
+	m.Types = types
+
+	m.DefaultType = strings.ToLower(strings.TrimSpace(m.DefaultType))
+	if m.DefaultType != "" && !validType(m.DefaultType) {
+		return fmt.Errorf("%w: defaultType %q", ErrInvalidMapping, m.DefaultType)
+	}
+	return nil
+}
+
+func (m Mapping) delimiter() rune {
+	if m.Delimiter == "" {
+		return 0
+	}
+	return rune(m.Delimiter[0])
+}
+
+// layout converte formatos como DD/MM/YYYY no layout do Go; layouts do
+// Go (02/01/2006) passam sem alteração
+func (m Mapping) layout() string {
+	return strings.NewReplacer("YYYY", "2006", "YY", "06", "MM", "01", "DD", "02").Replace(m.DateFormat)
+}
+
+// parseMapped converte o CSV pelo mapeamento
+func parseMapped(table Table, m Mapping) ([]Row, error) {
+	col := func(name string) (int, error) {
+		if name == "" {
+			return -1, nil
+		}
+		i := table.Column(name)
+		if i < 0 {
+			return -1, fmt.Errorf("%w: coluna %q não encontrada", ErrInvalidFile, name)
+		}
+		return i, nil
+	}
+
+	c := m.Columns
+	names := []string{c.Date, c.Type, c.Symbol, c.Quantity, c.Price, c.Fees, c.Amount, c.Ratio, c.AssetClass, c.Note}
+	idx := make([]int, len(names))
+	for i, name := range names {
+		var err error
+		if idx[i], err = col(name); err != nil {
+			return nil, err
+		}
+	}
+	dateCol, typeCol, symbolCol, quantityCol, priceCol, feesCol, amountCol, ratioCol, classCol, noteCol :=
+		idx[0], idx[1], idx[2], idx[3], idx[4], idx[5], idx[6], idx[7], idx[8], idx[9]
+
+	rows := make([]Row, 0, len(table.Rows))
+	for i, r := range table.Rows {
+		line := table.Lines[i]
+
+		date, err := parseDate(cell(r, dateCol), m.layout())
+		if err != nil {
+			rows = append(rows, Row{Line: line, Error: err.Error()})
+			continue
+		}
+
+		var values [5]float64
+		var numErr error
+		for j, k := range []int{quantityCol, priceCol, feesCol, amountCol, ratioCol} {
+			v, err := parseNumber(cell(r, k), m.Decimal)
+			if err != nil && numErr == nil {
+				numErr = err
+			}
+			values[j] = v
+		}
+		if numErr != nil {
+			rows = append(rows, Row{Line: line, Error: numErr.Error()})
+			continue
+		}
+
+		tx := domain.Transaction{
+			Symbol:     cell(r, symbolCol),
+			Date:       date,
+			Quantity:   values[0],
+			Price:      values[1],
+			Fees:       values[2],
+			Amount:     values[3],
+			Ratio:      values[4],
+			AssetClass: cell(r, classCol),
+			Note:       cell(r, noteCol),
+		}
+
+		switch {
+		case typeCol >= 0:
+			raw := cell(r, typeCol)
+			t, ok := m.Types[fold(raw)]
+			if !ok && validType(strings.ToLower(raw)) {
+				t, ok = strings.ToLower(raw), true
+			}
+			if !ok {
+				if m.DefaultType == "" {
+					rows = append(rows, Row{Line: line, Skipped: fmt.Sprintf("tipo %q não mapeado", raw)})
+					continue
+				}
+				t = m.DefaultType
+			}
+			tx.Type = t
+		case m.SignedQuantity:
+			tx.Type = domain.TransactionBuy
+			if tx.Quantity < 0 {
+				tx.Type = domain.TransactionSell
+			}
+		default:
+			tx.Type = m.DefaultType
+		}
+
+		// Corretoras costumam exportar vendas e taxas com sinal negativo
+		if tx.Quantity < 0 {
+			tx.Quantity = -tx.Quantity
+		}
+		if tx.Fees < 0 {
+			tx.Fees = -tx.Fees
+		}
+		if tx.Amount < 0 {
+			tx.Amount = -tx.Amount
+		}
+
+		rows = append(rows, finish(line, tx))
+	}
+	return rows, nil
+}
+
+func validType(t string) bool {
+	switch t {
+	case domain.TransactionBuy, domain.TransactionSell, domain.TransactionSplit,
+		domain.TransactionBonus, domain.TransactionDividend:
+		return true
+	}
+	return false
+}
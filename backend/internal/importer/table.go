@@ -0,0 +1,312 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Table é uma planilha lida como texto: a primeira linha é o cabeçalho.
+// Lines guarda o número original (a partir de 1) de cada linha de Rows.
+// Spreadsheet indica XLSX, cujas células numéricas vêm sem formatação
+// regional (ex: "1000.5").
+type Table struct {
+	Header      []string
+	Rows        [][]string
+	Lines       []int
+	Spreadsheet bool
+}
+
+// Column devolve o índice da coluna pelo nome do cabeçalho (sem diferenciar
+// maiúsculas, acentos ou espaços extras), ou -1
+func (t Table) Column(name string) int {
+	want := fold(name)
+	for i, h := range t.Header {
+		if fold(h) == want {
+			return i
+		}
+	}
+	return -1
+}
+
+// ReadTable lê um CSV (separado por ";" ou ",") ou a primeira aba de um
+// XLSX. O formato é detectado pelo conteúdo.
+func ReadTable(data []byte) (Table, error) {
+	return readTable(data, 0)
+}
+
+// readTable lê a planilha; delimiter vale apenas para CSV (0 = detectar)
+func readTable(data []byte, delimiter rune) (Table, error) {
+	var rows [][]string
+	var lines []int
+	var err error
+	spreadsheet := bytes.HasPrefix(data, []byte("PK"))
+	if spreadsheet {
+		rows, lines, err = readXLSX(data)
+	} else {
+		rows, lines, err = readCSV(data, delimiter)
+	}
+	if err != nil {
+		return Table{}, err
+	}
+
+	// Linhas em branco são descartadas; a primeira restante é o cabeçalho
+	table := Table{Spreadsheet: spreadsheet}
+	for i, row := range rows {
+		if blank(row) {
+			continue
+		}
+		if table.Header == nil {
+			table.Header = row
+			continue
+		}
+		table.Rows = append(table.Rows, row)
+		table.Lines = append(table.Lines, lines[i])
+	}
+	if table.Header == nil {
+		return Table{}, fmt.Errorf("%w: arquivo vazio", ErrInvalidFile)
+	}
+	return table, nil
+}
+
+// readCSV lê o CSV e o número da linha de cada registro (linhas em branco
+// não viram registros); sem delimitador informado, usa ";" quando a
+// primeira linha tiver mais ";" do que ","
+func readCSV(data []byte, delimiter rune) ([][]string, []int, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // BOM do Excel
+	if !utf8.Valid(data) {
+		data = latin1ToUTF8(data)
+	}
+
+	if delimiter == 0 {
+		first, _, _ := bytes.Cut(data, []byte("\n"))
+		delimiter = ','
+		if bytes.Count(first, []byte(";")) > bytes.Count(first, []byte(",")) {
+			delimiter = ';'
+		}
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = delimiter
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	var rows [][]string
+	var lines []int
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		line, _ := r.FieldPos(0)
+		rows = append(rows, row)
+		lines = append(lines, line)
+	}
+	return rows, lines, nil
+}
+
+// latin1ToUTF8 converte exportações antigas em ISO-8859-1
+func latin1ToUTF8(data []byte) []byte {
+	out := make([]rune, len(data))
+	for i, b := range data {
+		out[i] = rune(b)
+	}
+	return []byte(string(out))
+}
+
+// Estruturas mínimas do SpreadsheetML para ler valores de células
+type xlsxSharedStrings struct {
+	Items []struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	} `xml:"si"`
+}
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Items []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		Number int `xml:"r,attr"`
+		Cells  []struct {
+			Ref    string `xml:"r,attr"`
+			Type   string `xml:"t,attr"`
+			Value  string `xml:"v"`
+			Inline struct {
+				Text string `xml:"t"`
+			} `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX lê a primeira aba do XLSX e o número de cada linha. Datas
+// numéricas (número de série do Excel) são convertidas pelo chamador via
+// parseDate.
+func readXLSX(data []byte) ([][]string, []int, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: xlsx inválido: %v", ErrInvalidFile, err)
+	}
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodeXML(f, &shared); err != nil {
+			return nil, nil, err
+		}
+	}
+	strs := make([]string, len(shared.Items))
+	for i, si := range shared.Items {
+		if si.Text != "" || len(si.Runs) == 0 {
+			strs[i] = si.Text
+			continue
+		}
+		var b strings.Builder
+		for _, r := range si.Runs {
+			b.WriteString(r.Text)
+		}
+		strs[i] = b.String()
+	}
+
+	sheetPath, err := firstSheet(files)
+	if err != nil {
+		return nil, nil, err
+	}
+	var sheet xlsxSheet
+	if err := decodeXML(files[sheetPath], &sheet); err != nil {
+		return nil, nil, err
+	}
+
+	rows := make([][]string, 0, len(sheet.Rows))
+	lines := make([]int, 0, len(sheet.Rows))
+	for i, r := range sheet.Rows {
+		var row []string
+		for i, c := range r.Cells {
+			col := i
+			if c.Ref != "" {
+				var ok bool
+				if col, ok = columnIndex(c.Ref); !ok {
+					return nil, nil, fmt.Errorf("%w: referência de célula inválida: %q", ErrInvalidFile, c.Ref)
+				}
+			}
+			if col >= maxColumns {
+				return nil, nil, fmt.Errorf("%w: mais de %d colunas na linha %d", ErrInvalidFile, maxColumns, r.Number)
+			}
+			for len(row) <= col {
+				row = append(row, "")
+			}
+			switch c.Type {
+			case "s":
+				n, err := strconv.Atoi(c.Value)
+				if err == nil && n >= 0 && n < len(strs) {
+					row[col] = strs[n]
+				}
+			case "inlineStr":
+				row[col] = c.Inline.Text
+			default:
+				row[col] = c.Value
+			}
+		}
+		rows = append(rows, row)
+		line := r.Number
+		if line == 0 {
+			line = i + 1
+		}
+		lines = append(lines, line)
+	}
+	return rows, lines, nil
+}
+
+// firstSheet resolve o arquivo da primeira aba pelo workbook
+func firstSheet(files map[string]*zip.File) (string, error) {
+	const fallback = "xl/worksheets/sheet1.xml"
+
+	var wb xlsxWorkbook
+	var rels xlsxRelationships
+	wbFile, okWb := files["xl/workbook.xml"]
+	relFile, okRel := files["xl/_rels/workbook.xml.rels"]
+	if okWb && okRel && decodeXML(wbFile, &wb) == nil && decodeXML(relFile, &rels) == nil && len(wb.Sheets) > 0 {
+		for _, rel := range rels.Items {
+			if rel.ID != wb.Sheets[0].ID {
+				continue
+			}
+			target := strings.TrimPrefix(rel.Target, "/")
+			if !strings.HasPrefix(target, "xl/") {
+				target = path.Join("xl", target)
+			}
+			if _, ok := files[target]; ok {
+				return target, nil
+			}
+		}
+	}
+	if _, ok := files[fallback]; ok {
+		return fallback, nil
+	}
+	return "", fmt.Errorf("%w: xlsx sem planilhas", ErrInvalidFile)
+}
+
+func decodeXML(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXMLSize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidFile, f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex converte a referência da célula (ex: "C12") no índice da
+// coluna. Retorna false se a referência não começa por uma coluna entre
+// A e XFD, a última do Excel.
+func columnIndex(ref string) (int, bool) {
+	col := 0
+	for _, ch := range ref {
+		if ch < 'A' || ch > 'Z' {
+			break
+		}
+		col = col*26 + int(ch-'A'+1)
+		if col > xlsxMaxColumn {
+			return 0, false
+		}
+	}
+	if col == 0 {
+		return 0, false
+	}
+	return col - 1, true
+}
+
+func blank(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
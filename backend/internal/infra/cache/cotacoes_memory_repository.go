@@ -1,19 +1,21 @@
 package repository
 
 import (
-	"cotacoes/internal/domain"
-	"cotacoes/internal/provider/brapi"
+	"errors"
 	"log"
 	"strings"
+
+	"cotacoes/internal/domain"
+	"cotacoes/internal/infra/resilience"
 )
 
 type CotacoesBrapiRepo struct {
-	Provider *brapi.BrapiProvider
+	Provider domain.StockProvider
 	Snapshot domain.SnapshotRepository
 }
 
 func NewCotacoesBrapiRepo(
-	provider *brapi.BrapiProvider,
+	provider domain.StockProvider,
 	snapshot domain.SnapshotRepository,
 ) *CotacoesBrapiRepo {
 	return &CotacoesBrapiRepo{
@@ -52,7 +54,11 @@ func (r *CotacoesBrapiRepo) ListAllStocks(
 	)
 	if err == nil {
 
-		log.Println("✅ Dados vindos da BRAPI")
+		if data.Degraded {
+			log.Println("📦 Dados vindos do snapshot (cadeia de providers)")
+		} else {
+			log.Println("✅ Dados vindos da BRAPI")
+		}
 
 		// 🔥 FILTRO LOCAL por setor/tipo (se especificado)
 		allStocks := data.Stocks
@@ -103,20 +109,23 @@ func (r *CotacoesBrapiRepo) ListAllStocks(
 			paginatedStocks = allStocks[startIdx:endIdx]
 		}
 
-		// Salva snapshot completo (sem paginação) para cache
-		fullData := &domain.AllStocksResponse{
-			Stocks:              data.Stocks,
-			AvailableSectors:    data.AvailableSectors,
-			AvailableStockTypes: data.AvailableStockTypes,
-			Pagination: domain.Pagination{
-				CurrentPage:  1,
-				TotalPages:   1,
-				ItemsPerPage: totalCount,
-				TotalCount:   totalCount,
-				HasNextPage:  false,
-			},
+		// Salva snapshot completo (sem paginação) para cache.
+		// Dados degradados já vieram do snapshot e não devem sobrescrevê-lo.
+		if !data.Degraded {
+			fullData := &domain.AllStocksResponse{
+				Stocks:              data.Stocks,
+				AvailableSectors:    data.AvailableSectors,
+				AvailableStockTypes: data.AvailableStockTypes,
+				Pagination: domain.Pagination{
+					CurrentPage:  1,
+					TotalPages:   1,
+					ItemsPerPage: totalCount,
+					TotalCount:   totalCount,
+					HasNextPage:  false,
+				},
+			}
+			_ = r.Snapshot.Save(fullData)
 		}
-		_ = r.Snapshot.Save(fullData)
 
 		// Retorna dados paginados
 		return &domain.AllStocksResponse{
@@ -130,10 +139,15 @@ func (r *CotacoesBrapiRepo) ListAllStocks(
 				TotalCount:   totalCount,
 				HasNextPage:  page < totalPages,
 			},
+			Degraded: data.Degraded,
 		}, nil
 	}
 
-	log.Println("📦 BRAPI indisponível — usando snapshot salvo")
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		log.Println("⚡ Circuit breaker aberto — usando snapshot salvo")
+	} else {
+		log.Println("📦 BRAPI indisponível — usando snapshot salvo")
+	}
 
 	cached, _, cacheErr := r.Snapshot.Load()
 	if cacheErr == nil {
@@ -194,6 +208,7 @@ func (r *CotacoesBrapiRepo) ListAllStocks(
 				TotalCount:   totalCount,
 				HasNextPage:  page < totalPages,
 			},
+			Degraded: true,
 		}, nil
 	}
 
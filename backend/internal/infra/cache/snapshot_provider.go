@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"strings"
+
+	"cotacoes/internal/domain"
+)
+
+// SnapshotStockProvider expõe o último snapshot salvo como um StockProvider,
+// permitindo usá-lo como último elo da cadeia de fallback.
+type SnapshotStockProvider struct {
+	Snapshot domain.SnapshotRepository
+}
+
+func NewSnapshotStockProvider(snapshot domain.SnapshotRepository) *SnapshotStockProvider {
+	return &SnapshotStockProvider{Snapshot: snapshot}
+}
+
+// ListAllStocks devolve o snapshot filtrado, sempre marcado como degradado
+func (p *SnapshotStockProvider) ListAllStocks(
+	sector, stockType, sortBy, sortOrder string,
+	page, perPage int,
+) (*domain.AllStocksResponse, error) {
+
+	cached, _, err := p.Snapshot.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	stocks := make([]domain.StockListItem, 0, len(cached.Stocks))
+	for _, s := range cached.Stocks {
+		if sector != "" && s.Sector != sector {
+			continue
+		}
+		if stockType != "" && !strings.EqualFold(s.Type, stockType) {
+			continue
+		}
+		stocks = append(stocks, s)
+	}
+
+	return &domain.AllStocksResponse{
+		Indexes:             cached.Indexes,
+		Stocks:              stocks,
+		AvailableSectors:    cached.AvailableSectors,
+		AvailableStockTypes: cached.AvailableStockTypes,
+		Pagination: domain.Pagination{
+			CurrentPage:  1,
+			TotalPages:   1,
+			ItemsPerPage: len(stocks),
+			TotalCount:   len(stocks),
+			HasNextPage:  false,
+		},
+		Degraded: true,
+	}, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"cotacoes/internal/domain"
+	"cotacoes/internal/infra/resilience"
+)
+
+type cachedStock struct {
+	stock    domain.Stock
+	rng      string
+	interval string
+	cachedAt time.Time
+}
+
+// StockDetailCacheRepo guarda o último detalhe válido de cada ação e o
+// devolve (marcado como degradado) quando o provider está indisponível.
+// Implementa domain.StockRepository.
+type StockDetailCacheRepo struct {
+	Provider domain.StockRepository
+
+	mu       sync.RWMutex
+	bySymbol map[string]map[string]cachedStock // symbol -> range|interval -> stock
+}
+
+func NewStockDetailCacheRepo(provider domain.StockRepository) *StockDetailCacheRepo {
+	return &StockDetailCacheRepo{
+		Provider: provider,
+		bySymbol: make(map[string]map[string]cachedStock),
+	}
+}
+
+// GetBySymbol consulta o provider e, em caso de falha, usa o cache
+func (r *StockDetailCacheRepo) GetBySymbol(
+	symbol, rangeParam, intervalParam string,
+) (*domain.Stock, error) {
+
+	stock, err := r.Provider.GetBySymbol(symbol, rangeParam, intervalParam)
+	if err == nil {
+		r.store(symbol, rangeParam, intervalParam, stock)
+		return stock, nil
+	}
+
+	if errors.Is(err, domain.ErrStockNotFound) {
+		return nil, err
+	}
+
+	cached, ok := r.load(symbol, rangeParam, intervalParam)
+	if !ok {
+		return nil, err
+	}
+
+	if errors.Is(err, resilience.ErrCircuitOpen) {
+		log.Printf("⚡ Circuit breaker aberto — usando cache de %s", symbol)
+	} else {
+		log.Printf("📦 Provider indisponível — usando cache de %s", symbol)
+	}
+
+	cached.Degraded = true
+	return cached, nil
+}
+
+func (r *StockDetailCacheRepo) store(symbol, rangeParam, intervalParam string, stock *domain.Stock) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := strings.ToUpper(symbol)
+	entries, ok := r.bySymbol[key]
+	if !ok {
+		entries = make(map[string]cachedStock)
+		r.bySymbol[key] = entries
+	}
+	entries[rangeParam+"|"+intervalParam] = cachedStock{
+		stock:    *stock,
+		rng:      rangeParam,
+		interval: intervalParam,
+		cachedAt: time.Now(),
+	}
+}
+
+// load procura o range/interval exato; se não houver, usa a entrada mais
+// recente do mesmo interval cujo range cobre o pedido, recortada para o
+// range pedido (melhor dado antigo do que nenhum dado). Entradas de outro
+// interval ou de range menor não são usadas: a resposta pareceria
+// completa sem ser.
+func (r *StockDetailCacheRepo) load(symbol, rangeParam, intervalParam string) (*domain.Stock, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	entries, ok := r.bySymbol[strings.ToUpper(symbol)]
+	if !ok {
+		return nil, false
+	}
+
+	if entry, ok := entries[rangeParam+"|"+intervalParam]; ok {
+		stock := entry.stock
+		return &stock, true
+	}
+
+	from, err := domain.RangeStart(rangeParam, time.Now())
+	if err != nil {
+		return nil, false
+	}
+
+	var latest *cachedStock
+	for _, entry := range entries {
+		if entry.interval != intervalParam {
+			continue
+		}
+		start, err := domain.RangeStart(entry.rng, entry.cachedAt)
+		if err != nil || start.After(from) {
+			continue
+		}
+		if latest == nil || entry.cachedAt.After(latest.cachedAt) {
+			e := entry
+			latest = &e
+		}
+	}
+	if latest == nil {
+		return nil, false
+	}
+
+	stock := latest.stock
+	stock.HistoricalDataPrice = make([]domain.HistoricalDataPrice, 0, len(latest.stock.HistoricalDataPrice))
+	for _, c := range latest.stock.HistoricalDataPrice {
+		if c.Date >= from.Unix() {
+			stock.HistoricalDataPrice = append(stock.HistoricalDataPrice, c)
+		}
+	}
+	stock.UsedRange = rangeParam
+	return &stock, true
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// flakyProvider devolve um histórico diário do range pedido ou, com fail,
+// um erro de indisponibilidade
+type flakyProvider struct {
+	fail bool
+}
+
+func (p *flakyProvider) GetBySymbol(symbol, rangeParam, intervalParam string) (*domain.Stock, error) {
+	if p.fail {
+		return nil, errors.New("provider fora do ar")
+	}
+	now := time.Now()
+	from, err := domain.RangeStart(rangeParam, now)
+	if err != nil {
+		return nil, err
+	}
+	stock := &domain.Stock{Symbol: symbol, UsedRange: rangeParam, UsedInterval: intervalParam}
+	for d := from.AddDate(0, 0, 1); d.Before(now); d = d.AddDate(0, 0, 1) {
+		stock.HistoricalDataPrice = append(stock.HistoricalDataPrice, domain.HistoricalDataPrice{Date: d.Unix(), Close: 10})
+	}
+	return stock, nil
+}
+
+func TestStockDetailCacheFallback(t *testing.T) {
+	provider := &flakyProvider{}
+	repo := NewStockDetailCacheRepo(provider)
+	if _, err := repo.GetBySymbol("PETR4", "1y", "1d"); err != nil {
+		t.Fatal(err)
+	}
+	provider.fail = true
+
+	// Range menor e mesmo interval: recorta o histórico de 1 ano
+	stock, err := repo.GetBySymbol("PETR4", "1mo", "1d")
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Now().AddDate(0, -1, 0).Unix()
+	if !stock.Degraded || stock.UsedRange != "1mo" || len(stock.HistoricalDataPrice) == 0 || stock.HistoricalDataPrice[0].Date < from {
+		t.Errorf("fallback = degradado %v, range %s, %d candles", stock.Degraded, stock.UsedRange, len(stock.HistoricalDataPrice))
+	}
+
+	// Range maior ou outro interval não são servidos pelo cache
+	for _, req := range [][2]string{{"5y", "1d"}, {"1mo", "1wk"}, {"max", "1d"}} {
+		if _, err := repo.GetBySymbol("PETR4", req[0], req[1]); err == nil {
+			t.Errorf("%s/%s: esperado erro do provider, não o cache", req[0], req[1])
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// RetentionPolicy define por quanto tempo cada intervalo é mantido.
+// Intervalos ausentes (ou com duração zero) são mantidos para sempre.
+type RetentionPolicy map[string]time.Duration
+
+// DefaultRetention mantém o intraday por janelas curtas e o diário para sempre
+func DefaultRetention() RetentionPolicy {
+	day := 24 * time.Hour
+	return RetentionPolicy{
+		"1m":  30 * day,
+		"2m":  60 * day,
+		"5m":  60 * day,
+		"15m": 180 * day,
+		"30m": 180 * day,
+		"60m": 730 * day,
+		"90m": 730 * day,
+		"1h":  730 * day,
+	}
+}
+
+// Compact reescreve o arquivo da série ordenado, sem duplicatas e sem os
+// candles fora da janela de retenção. A troca é atômica (arquivo temporário
+// + rename), então leituras concorrentes nunca veem um arquivo parcial.
+func (s *FileStore) Compact(symbol, interval string) error {
+	path, err := s.path(symbol, interval)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, err := readSeries(path)
+	if err != nil {
+		return err
+	}
+	series = s.applyRetention(series, interval, time.Now())
+
+	if len(series) == 0 {
+		delete(s.series, seriesKey(symbol, interval))
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	w := bufio.NewWriter(tmp)
+	enc := json.NewEncoder(w)
+	for _, c := range series {
+		if err := enc.Encode(c); err != nil {
+			tmp.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	s.cache(seriesKey(symbol, interval), path, series)
+	return nil
+}
+
+// CompactAll compacta todas as séries do diretório
+func (s *FileStore) CompactAll() error {
+	symbols, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	compacted := 0
+	for _, symbolDir := range symbols {
+		if !symbolDir.IsDir() {
+			continue
+		}
+		files, err := os.ReadDir(filepath.Join(s.dir, symbolDir.Name()))
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if f.IsDir() || !strings.HasSuffix(f.Name(), ".jsonl") {
+				continue
+			}
+			interval := strings.TrimSuffix(f.Name(), ".jsonl")
+			if err := s.Compact(symbolDir.Name(), interval); err != nil {
+				return err
+			}
+			compacted++
+		}
+	}
+
+	log.Printf("🗜️ Histórico local compactado: %d séries", compacted)
+	return nil
+}
+
+// RunCompaction compacta o store periodicamente até stop ser fechado
+func (s *FileStore) RunCompaction(every time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		if err := s.CompactAll(); err != nil {
+			log.Printf("⚠️ Erro na compactação do histórico: %v", err)
+		}
+
+		select {
+		case <-ticker.C:
+		case <-stop:
+			return
+		}
+	}
+}
+
+func (s *FileStore) applyRetention(series []domain.HistoricalDataPrice, interval string, now time.Time) []domain.HistoricalDataPrice {
+	keep := s.retention[interval]
+	if keep <= 0 {
+		return series
+	}
+
+	cutoff := now.Add(-keep).Unix()
+	for i, c := range series {
+		if c.Date >= cutoff {
+			return series[i:]
+		}
+	}
+	return series[:0]
+}
@@ -0,0 +1,286 @@
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+var validKey = regexp.MustCompile(`^[A-Z0-9^._-]+$`)
+
+// FileStore é um store de séries temporais append-only em arquivos JSON
+// Lines, um por símbolo e intervalo: <dir>/<SYMBOL>/<interval>.jsonl.
+// Cada candle é gravado uma única vez; datas já presentes são ignoradas.
+// A compactação reescreve o arquivo ordenado e aplica a retenção.
+type FileStore struct {
+	dir       string
+	retention RetentionPolicy
+
+	mu     sync.RWMutex
+	series map[string]cachedSeries // cache em memória
+}
+
+// cachedSeries guarda a série ordenada e o estado do arquivo quando foi
+// lida; se outro processo (ex: o worker) gravar no arquivo, a série é relida
+type cachedSeries struct {
+	candles []domain.HistoricalDataPrice
+	size    int64
+	modTime time.Time
+}
+
+func NewFileStore(dir string, retention RetentionPolicy) *FileStore {
+	return &FileStore{
+		dir:       dir,
+		retention: retention,
+		series:    make(map[string]cachedSeries),
+	}
+}
+
+// Append grava no fim do arquivo apenas os candles com datas ainda não
+// armazenadas e atualiza o cache em memória
+func (s *FileStore) Append(symbol, interval string, candles []domain.HistoricalDataPrice) error {
+	if len(candles) == 0 {
+		return nil
+	}
+
+	path, err := s.path(symbol, interval)
+	if err != nil {
+		return err
+	}
+	if _, err := s.load(symbol, interval); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := seriesKey(symbol, interval)
+	existing := s.series[key].candles
+	stored := make(map[int64]bool, len(existing))
+	for _, c := range existing {
+		stored[c.Date] = true
+	}
+
+	fresh := make([]domain.HistoricalDataPrice, 0, len(candles))
+	for _, c := range candles {
+		c.Date = normalizeDate(c.Date, interval)
+		if stored[c.Date] {
+			continue
+		}
+		stored[c.Date] = true
+		fresh = append(fresh, c)
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	w := bufio.NewWriter(file)
+	enc := json.NewEncoder(w)
+	for _, c := range fresh {
+		if err := enc.Encode(c); err != nil {
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	s.cache(key, path, merge(existing, fresh))
+	return nil
+}
+
+// Range retorna os candles entre from e to (inclusivos), ordenados por data.
+// Um from zero significa "desde o início".
+func (s *FileStore) Range(symbol, interval string, from, to time.Time) ([]domain.HistoricalDataPrice, error) {
+	series, err := s.load(symbol, interval)
+	if err != nil {
+		return nil, err
+	}
+
+	start := sort.Search(len(series), func(i int) bool {
+		return from.IsZero() || series[i].Date >= from.Unix()
+	})
+
+	result := make([]domain.HistoricalDataPrice, 0)
+	for _, c := range series[start:] {
+		if !to.IsZero() && c.Date > to.Unix() {
+			break
+		}
+		result = append(result, c)
+	}
+	return result, nil
+}
+
+// Coverage informa o primeiro e o último candle disponíveis
+func (s *FileStore) Coverage(symbol, interval string) (domain.HistoryCoverage, error) {
+	series, err := s.load(symbol, interval)
+	if err != nil {
+		return domain.HistoryCoverage{}, err
+	}
+	if len(series) == 0 {
+		return domain.HistoryCoverage{}, nil
+	}
+
+	return domain.HistoryCoverage{
+		From:  time.Unix(series[0].Date, 0).In(calendar.Location),
+		To:    time.Unix(series[len(series)-1].Date, 0).In(calendar.Location),
+		Count: len(series),
+	}, nil
+}
+
+func (s *FileStore) load(symbol, interval string) ([]domain.HistoricalDataPrice, error) {
+	key := seriesKey(symbol, interval)
+
+	path, err := s.path(symbol, interval)
+	if err != nil {
+		return nil, err
+	}
+	size, modTime := stat(path)
+
+	s.mu.RLock()
+	cached, ok := s.series[key]
+	s.mu.RUnlock()
+	if ok && cached.size == size && cached.modTime.Equal(modTime) {
+		return cached.candles, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	series, err := readSeries(path)
+	if err != nil {
+		return nil, err
+	}
+	s.series[key] = cachedSeries{candles: series, size: size, modTime: modTime}
+	return series, nil
+}
+
+// cache atualiza a série em memória após uma escrita deste processo.
+// Deve ser chamado com o mutex travado.
+func (s *FileStore) cache(key, path string, candles []domain.HistoricalDataPrice) {
+	size, modTime := stat(path)
+	s.series[key] = cachedSeries{candles: candles, size: size, modTime: modTime}
+}
+
+func stat(path string) (int64, time.Time) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return -1, time.Time{}
+	}
+	return info.Size(), info.ModTime()
+}
+
+func (s *FileStore) path(symbol, interval string) (string, error) {
+	symbol = strings.ToUpper(strings.TrimSpace(symbol))
+	interval = strings.TrimSpace(interval)
+	if !validKey.MatchString(symbol) || !validKey.MatchString(strings.ToUpper(interval)) {
+		return "", fmt.Errorf("símbolo/intervalo inválido: %q/%q", symbol, interval)
+	}
+	return filepath.Join(s.dir, symbol, interval+".jsonl"), nil
+}
+
+func readSeries(path string) ([]domain.HistoricalDataPrice, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return []domain.HistoricalDataPrice{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	candles := make([]domain.HistoricalDataPrice, 0)
+	dec := json.NewDecoder(bufio.NewReader(file))
+	for dec.More() {
+		var c domain.HistoricalDataPrice
+		if err := dec.Decode(&c); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		candles = append(candles, c)
+	}
+
+	return dedupe(candles), nil
+}
+
+// dedupe ordena os candles lidos do disco mantendo a primeira gravação de
+// cada data
+func dedupe(candles []domain.HistoricalDataPrice) []domain.HistoricalDataPrice {
+	seen := make(map[int64]bool, len(candles))
+	unique := make([]domain.HistoricalDataPrice, 0, len(candles))
+	for _, c := range candles {
+		if seen[c.Date] {
+			continue
+		}
+		seen[c.Date] = true
+		unique = append(unique, c)
+	}
+	sort.SliceStable(unique, func(i, j int) bool {
+		return unique[i].Date < unique[j].Date
+	})
+	return unique
+}
+
+// merge combina duas séries ordenadas por data; em datas repetidas
+// prevalece o candle já armazenado (escrita única)
+func merge(existing, newer []domain.HistoricalDataPrice) []domain.HistoricalDataPrice {
+	byDate := make(map[int64]domain.HistoricalDataPrice, len(existing)+len(newer))
+	for _, c := range newer {
+		byDate[c.Date] = c
+	}
+	for _, c := range existing {
+		byDate[c.Date] = c
+	}
+
+	merged := make([]domain.HistoricalDataPrice, 0, len(byDate))
+	for _, c := range byDate {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Date < merged[j].Date
+	})
+	return merged
+}
+
+// normalizeDate alinha candles diários (ou maiores) à meia-noite do pregão,
+// para que dados do COTAHIST e da brapi do mesmo dia não se dupliquem.
+func normalizeDate(unix int64, interval string) int64 {
+	t := time.Unix(unix, 0).In(calendar.Location)
+	if isIntraday(interval) {
+		return t.Truncate(time.Minute).Unix()
+	}
+	return calendar.StartOfDay(t).Unix()
+}
+
+func isIntraday(interval string) bool {
+	return strings.HasSuffix(interval, "m") && !strings.HasSuffix(interval, "mo") ||
+		strings.HasSuffix(interval, "h")
+}
+
+func seriesKey(symbol, interval string) string {
+	return strings.ToUpper(strings.TrimSpace(symbol)) + "|" + strings.TrimSpace(interval)
+}
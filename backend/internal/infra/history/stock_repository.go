@@ -0,0 +1,221 @@
+package history
+
+import (
+	"log"
+	"sort"
+	"time"
+
+	"cotacoes/internal/analytics/adjust"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// Ranges aceitos pela brapi, do menor para o maior
+var upstreamRanges = []struct {
+	name string
+	span time.Duration
+}{
+	{"1d", 24 * time.Hour},
+	{"5d", 5 * 24 * time.Hour},
+	{"1mo", 31 * 24 * time.Hour},
+	{"3mo", 92 * 24 * time.Hour},
+	{"6mo", 183 * 24 * time.Hour},
+	{"1y", 366 * 24 * time.Hour},
+	{"2y", 2 * 366 * 24 * time.Hour},
+	{"5y", 5 * 366 * 24 * time.Hour},
+	{"10y", 10 * 366 * 24 * time.Hour},
+}
+
+// StockRepo mantém o histórico de candles no store local e busca no
+// provider apenas a cauda que falta (atualização incremental), mesclando
+// com o que já está armazenado antes de responder.
+// Implementa domain.StockRepository.
+type StockRepo struct {
+	Provider domain.StockRepository
+	Store    domain.HistoryStore
+
+	now func() time.Time
+}
+
+func NewStockRepo(provider domain.StockRepository, store domain.HistoryStore) *StockRepo {
+	return &StockRepo{
+		Provider: provider,
+		Store:    store,
+		now:      time.Now,
+	}
+}
+
+// GetBySymbol devolve o histórico com o AdjustedClose recalculado pelo
+// pacote adjust (retorno total) a partir dos proventos da resposta. Assim
+// o campo tem o mesmo significado vindo do provider ou do store, que só
+// guarda o OHLC bruto.
+func (r *StockRepo) GetBySymbol(symbol, rangeParam, intervalParam string) (*domain.Stock, error) {
+	stock, err := r.load(symbol, rangeParam, intervalParam)
+	if err != nil {
+		return nil, err
+	}
+	stock.HistoricalDataPrice = withAdjustedClose(stock.HistoricalDataPrice, stock.DividendsData)
+	return stock, nil
+}
+
+func (r *StockRepo) load(symbol, rangeParam, intervalParam string) (*domain.Stock, error) {
+	now := r.now().In(calendar.Location)
+
+	from, err := domain.RangeStart(rangeParam, now)
+	if err != nil || rangeParam == "max" {
+		return r.Provider.GetBySymbol(symbol, rangeParam, intervalParam)
+	}
+
+	coverage, err := r.Store.Coverage(symbol, intervalParam)
+	if err != nil {
+		log.Printf("⚠️ Erro ao ler histórico local de %s: %v", symbol, err)
+		return r.Provider.GetBySymbol(symbol, rangeParam, intervalParam)
+	}
+
+	// Sem histórico suficiente: busca o range completo e semeia o store
+	if coverage.Count == 0 || coverage.From.After(from) {
+		stock, err := r.Provider.GetBySymbol(symbol, rangeParam, intervalParam)
+		if err != nil {
+			return nil, err
+		}
+		r.persist(symbol, intervalParam, stock.HistoricalDataPrice, now)
+		if len(stock.HistoricalDataPrice) == 0 && isIntraday(intervalParam) {
+			r.fillIntraday(stock, symbol, intervalParam, from, now)
+		}
+		return stock, nil
+	}
+
+	// Busca só a cauda entre o último candle armazenado e agora.
+	// O detalhe da cotação (preço atual, fundamentos) vem junto.
+	tailRange := rangeCovering(now.Sub(coverage.To))
+	stock, err := r.Provider.GetBySymbol(symbol, tailRange, intervalParam)
+	if err != nil {
+		return nil, err
+	}
+	r.persist(symbol, intervalParam, stock.HistoricalDataPrice, now)
+
+	stored, err := r.Store.Range(symbol, intervalParam, from, now)
+	if err != nil {
+		log.Printf("⚠️ Erro ao ler histórico local de %s: %v", symbol, err)
+		return r.Provider.GetBySymbol(symbol, rangeParam, intervalParam)
+	}
+
+	log.Printf("🗄️ Histórico de %s (%s/%s): %d candles locais + cauda %s",
+		symbol, rangeParam, intervalParam, len(stored), tailRange)
+
+	stock.HistoricalDataPrice = mergeTail(stored, stock.HistoricalDataPrice, intervalParam)
+	stock.UsedRange = rangeParam
+	stock.UsedInterval = intervalParam
+	return stock, nil
+}
+
+// fillIntraday usa os candles montados pelo worker quando o provider não
+// devolve histórico intraday (limitação do plano da brapi)
+func (r *StockRepo) fillIntraday(stock *domain.Stock, symbol, interval string, from, now time.Time) {
+	stored, err := r.Store.Range(symbol, interval, from, now)
+	if err != nil || len(stored) == 0 {
+		return
+	}
+
+	log.Printf("🕯️ Intraday de %s (%s) servido pelos candles do worker: %d candles", symbol, interval, len(stored))
+	stock.HistoricalDataPrice = stored
+}
+
+// persist grava apenas candles de pregões encerrados; o candle em formação
+// mudaria depois de gravado e o store é de escrita única.
+// Só o OHLC bruto é gravado: o fechamento ajustado do provider muda a cada
+// novo provento e ficaria congelado no store. Ajustes são calculados na
+// leitura pelo pacote adjust.
+func (r *StockRepo) persist(symbol, interval string, candles []domain.HistoricalDataPrice, now time.Time) {
+	closed := make([]domain.HistoricalDataPrice, 0, len(candles))
+	cutoff := openCandleStart(interval, now)
+	for _, c := range candles {
+		if c.Date < cutoff.Unix() {
+			c.AdjustedClose = 0
+			closed = append(closed, c)
+		}
+	}
+
+	if err := r.Store.Append(symbol, interval, closed); err != nil {
+		log.Printf("⚠️ Erro ao gravar histórico local de %s: %v", symbol, err)
+	}
+}
+
+// openCandleStart retorna o início do candle que ainda pode mudar
+func openCandleStart(interval string, now time.Time) time.Time {
+	if isIntraday(interval) {
+		return now.Add(-intervalDuration(interval))
+	}
+	// Depois do fechamento o candle do dia já está fechado
+	if _, close := calendar.Default.SessionBounds(now); !now.Before(close) {
+		return calendar.StartOfDay(now).AddDate(0, 0, 1)
+	}
+	return calendar.StartOfDay(now)
+}
+
+// mergeTail junta o histórico armazenado com a cauda recém-buscada.
+// Candles da cauda substituem os armazenados na mesma data (o do dia
+// corrente ainda está em formação).
+func mergeTail(stored, tail []domain.HistoricalDataPrice, interval string) []domain.HistoricalDataPrice {
+	byDate := make(map[int64]domain.HistoricalDataPrice, len(stored)+len(tail))
+	for _, c := range stored {
+		byDate[c.Date] = c
+	}
+	for _, c := range tail {
+		c.Date = normalizeDate(c.Date, interval)
+		byDate[c.Date] = c
+	}
+
+	merged := make([]domain.HistoricalDataPrice, 0, len(byDate))
+	for _, c := range byDate {
+		merged = append(merged, c)
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Date < merged[j].Date
+	})
+	return merged
+}
+
+// withAdjustedClose ordena os candles por data e recalcula o
+// AdjustedClose no modo total, mantendo o OHLC bruto
+func withAdjustedClose(candles []domain.HistoricalDataPrice, dividends domain.DividendsData) []domain.HistoricalDataPrice {
+	out := make([]domain.HistoricalDataPrice, len(candles))
+	copy(out, candles)
+	sort.SliceStable(out, func(i, j int) bool { return out[i].Date < out[j].Date })
+
+	adjusted := adjust.Apply(out, dividends, adjust.Total)
+	for i := range out {
+		out[i].AdjustedClose = adjusted[i].AdjustedClose
+	}
+	return out
+}
+
+// rangeCovering escolhe o menor range da brapi que cobre a lacuna
+func rangeCovering(gap time.Duration) string {
+	for _, r := range upstreamRanges {
+		if gap <= r.span {
+			return r.name
+		}
+	}
+	return "max"
+}
+
+func intervalDuration(interval string) time.Duration {
+	switch interval {
+	case "1m":
+		return time.Minute
+	case "2m":
+		return 2 * time.Minute
+	case "5m":
+		return 5 * time.Minute
+	case "15m":
+		return 15 * time.Minute
+	case "30m":
+		return 30 * time.Minute
+	case "60m", "1h":
+		return time.Hour
+	case "90m":
+		return 90 * time.Minute
+	}
+	return 24 * time.Hour
+}
@@ -0,0 +1,72 @@
+package history
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+func day(d int) int64 {
+	return time.Date(2024, 3, d, 0, 0, 0, 0, calendar.Location).Unix()
+}
+
+func TestAdjustedCloseMatchesAcrossSources(t *testing.T) {
+	// Provento de R$ 1 com data "com" no dia 5 (fechamento 41)
+	dividends := domain.DividendsData{
+		CashDividends: []domain.CashDividend{
+			{Label: "DIVIDENDO", Rate: 1, LastDatePrior: time.Unix(day(5), 0)},
+		},
+	}
+	stored := []domain.HistoricalDataPrice{
+		{Date: day(4), Close: 40},
+		{Date: day(5), Close: 41},
+	}
+	tail := []domain.HistoricalDataPrice{
+		{Date: day(6) + 17*3600, Close: 40.5, AdjustedClose: 40.5},
+	}
+	// A mesma série vinda inteira do provider, com o ajuste dele
+	cold := []domain.HistoricalDataPrice{
+		{Date: day(4), Close: 40, AdjustedClose: 39.1},
+		{Date: day(5), Close: 41, AdjustedClose: 40.05},
+		{Date: day(6), Close: 40.5, AdjustedClose: 40.5},
+	}
+
+	merged := withAdjustedClose(mergeTail(stored, tail, "1d"), dividends)
+	fresh := withAdjustedClose(cold, dividends)
+	if len(merged) != 3 || len(fresh) != 3 {
+		t.Fatalf("%d/%d candles, esperado 3", len(merged), len(fresh))
+	}
+	factor := 1 - 1.0/41
+	want := []float64{40 * factor, 41 * factor, 40.5}
+	for i := range want {
+		if merged[i].Close != fresh[i].Close {
+			t.Errorf("candle %d: close %.2f vs %.2f", i, merged[i].Close, fresh[i].Close)
+		}
+		if math.Abs(merged[i].AdjustedClose-want[i]) > 1e-9 || math.Abs(fresh[i].AdjustedClose-want[i]) > 1e-9 {
+			t.Errorf("candle %d: adjustedClose %.4f (store) / %.4f (provider), esperado %.4f",
+				i, merged[i].AdjustedClose, fresh[i].AdjustedClose, want[i])
+		}
+	}
+}
+
+func TestMergeTailPrefersTail(t *testing.T) {
+	stored := []domain.HistoricalDataPrice{
+		{Date: day(4), Close: 40},
+		{Date: day(5), Close: 41},
+	}
+	tail := []domain.HistoricalDataPrice{
+		{Date: day(5) + 17*3600, Close: 42},
+		{Date: day(6) + 17*3600, Close: 43},
+	}
+
+	merged := mergeTail(stored, tail, "1d")
+	if len(merged) != 3 {
+		t.Fatalf("%d candles, esperado 3", len(merged))
+	}
+	if merged[1].Date != day(5) || merged[1].Close != 42 {
+		t.Errorf("candle do dia 5 = %+v, esperado o da cauda", merged[1])
+	}
+}
@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"cotacoes/internal/analytics/adjust"
+	"cotacoes/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AnalyticsHandler struct {
+	GetIndicatorsUC *usecase.GetIndicatorsUseCase
+	GetStatsUC      *usecase.GetStatsUseCase
+	CorrelationUC   *usecase.CorrelationUseCase
+	CompareUC       *usecase.CompareUseCase
+}
+
+func NewAnalyticsHandler(
+	getIndicatorsUC *usecase.GetIndicatorsUseCase,
+	getStatsUC *usecase.GetStatsUseCase,
+	correlationUC *usecase.CorrelationUseCase,
+	compareUC *usecase.CompareUseCase,
+) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		GetIndicatorsUC: getIndicatorsUC,
+		GetStatsUC:      getStatsUC,
+		CorrelationUC:   correlationUC,
+		CompareUC:       compareUC,
+	}
+}
+
+// =======================
+// GET /stocks/:symbol/indicators
+// Ex: /stocks/PETR4/indicators?set=sma:20,ema:50,rsi:14,macd,bbands:20:2,atr:14,obv&range=1y&adjust=splits
+// =======================
+func (h *AnalyticsHandler) GetIndicators(c *gin.Context) {
+	symbol := c.Param("symbol")
+
+	rangeParam := c.DefaultQuery("range", "1y")
+	intervalParam := c.DefaultQuery("interval", "1d")
+	set := c.DefaultQuery("set", "sma:20,rsi:14")
+
+	result, err := h.GetIndicatorsUC.Execute(symbol, rangeParam, intervalParam, set, stockOptions(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// GET /stocks/:symbol/stats
+// Ex: /stocks/PETR4/stats?range=1y&rf=0.1075&benchmark=^BVSP&adjust=total
+// =======================
+func (h *AnalyticsHandler) GetStats(c *gin.Context) {
+	symbol := c.Param("symbol")
+
+	rangeParam := c.DefaultQuery("range", "1y")
+	intervalParam := c.DefaultQuery("interval", "1d")
+
+	riskFreeRate, err := optionalFloat(c, "rf")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.GetStatsUC.Execute(symbol, rangeParam, intervalParam, riskFreeRate, c.Query("benchmark"), stockOptions(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// GET /analytics/correlation
+// Ex: /analytics/correlation?symbols=ITUB4,BBDC4,PETR4&range=1y&interval=1d&adjust=total
+// =======================
+func (h *AnalyticsHandler) GetCorrelation(c *gin.Context) {
+	symbols := strings.Split(c.Query("symbols"), ",")
+
+	rangeParam := c.DefaultQuery("range", "1y")
+	intervalParam := c.DefaultQuery("interval", "1d")
+
+	result, err := h.CorrelationUC.Execute(symbols, rangeParam, intervalParam, stockOptions(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// GET /compare
+// Ex: /compare?symbols=ITUB4,BBDC4,BBAS3&range=1y&adjust=total&benchmark=custom:bancoes
+// =======================
+func (h *AnalyticsHandler) Compare(c *gin.Context) {
+	symbols := strings.Split(c.Query("symbols"), ",")
+	rangeParam := c.DefaultQuery("range", "1y")
+
+	result, err := h.CompareUC.Execute(symbols, rangeParam, c.Query("benchmark"), stockOptions(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// stockOptions lê as opções de histórico comuns às rotas de análise.
+// Sem "adjust" os preços são ajustados por desdobramentos: na série bruta
+// um desdobramento aparece como uma queda e distorce retornos e
+// indicadores. Use adjust=none para a série bruta.
+func stockOptions(c *gin.Context) usecase.StockOptions {
+	return usecase.StockOptions{
+		Adjust: c.DefaultQuery("adjust", string(adjust.Splits)),
+	}
+}
+
+// optionalFloat lê um parâmetro numérico opcional da query
+func optionalFloat(c *gin.Context, key string) (*float64, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("parâmetro %s inválido: %q", key, raw)
+	}
+	return &v, nil
+}
+
+// optionalInt lê um parâmetro inteiro opcional da query (0 quando ausente)
+func optionalInt(c *gin.Context, key string) (int, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return 0, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, fmt.Errorf("parâmetro %s inválido: %q", key, raw)
+	}
+	return v, nil
+}
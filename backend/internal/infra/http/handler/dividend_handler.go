@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"net/http"
+
+	"cotacoes/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DividendHandler struct {
+	GetDividendSummaryUC *usecase.GetDividendSummaryUseCase
+	DividendCalendarUC   *usecase.DividendCalendarUseCase
+}
+
+func NewDividendHandler(
+	getDividendSummaryUC *usecase.GetDividendSummaryUseCase,
+	dividendCalendarUC *usecase.DividendCalendarUseCase,
+) *DividendHandler {
+	return &DividendHandler{
+		GetDividendSummaryUC: getDividendSummaryUC,
+		DividendCalendarUC:   dividendCalendarUC,
+	}
+}
+
+// =======================
+// GET /stocks/:symbol/dividends/summary
+// Ex: /stocks/ITSA4/dividends/summary
+// =======================
+func (h *DividendHandler) GetSummary(c *gin.Context) {
+	result, err := h.GetDividendSummaryUC.Execute(c.Param("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// GET /dividends/calendar
+// Ex: /dividends/calendar?from=2024-03-01&to=2024-03-31
+// =======================
+func (h *DividendHandler) GetCalendar(c *gin.Context) {
+	result, err := h.DividendCalendarUC.Execute(c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
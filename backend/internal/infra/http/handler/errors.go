@@ -0,0 +1,31 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"cotacoes/internal/domain"
+	"cotacoes/internal/usecase"
+)
+
+// errorStatus converte erros de domínio/use case no status HTTP adequado
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrStockNotFound),
+		errors.Is(err, domain.ErrScreenNotFound),
+		errors.Is(err, domain.ErrSectorNotFound),
+		errors.Is(err, domain.ErrIndexNotFound),
+		errors.Is(err, domain.ErrWatchlistNotFound),
+		errors.Is(err, domain.ErrPortfolioNotFound),
+		errors.Is(err, domain.ErrTransactionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, usecase.ErrInvalidSymbol),
+		errors.Is(err, usecase.ErrInvalidParameter),
+		errors.Is(err, domain.ErrInvalidRange):
+		return http.StatusBadRequest
+	case errors.Is(err, usecase.ErrIdempotencyConflict),
+		errors.Is(err, usecase.ErrPortfolioChanged):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
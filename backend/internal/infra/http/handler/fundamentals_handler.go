@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"net/http"
+
+	"cotacoes/internal/analytics/valuation"
+
+	"cotacoes/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type FundamentalsHandler struct {
+	GetRatiosUC    *usecase.GetRatiosUseCase
+	GetValuationUC *usecase.GetValuationUseCase
+}
+
+func NewFundamentalsHandler(
+	getRatiosUC *usecase.GetRatiosUseCase,
+	getValuationUC *usecase.GetValuationUseCase,
+) *FundamentalsHandler {
+	return &FundamentalsHandler{
+		GetRatiosUC:    getRatiosUC,
+		GetValuationUC: getValuationUC,
+	}
+}
+
+// =======================
+// GET /stocks/:symbol/ratios
+// Ex: /stocks/WEGE3/ratios
+// =======================
+func (h *FundamentalsHandler) GetRatios(c *gin.Context) {
+	result, err := h.GetRatiosUC.Execute(c.Param("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// GET /stocks/:symbol/valuation
+// Ex: /stocks/TAEE11/valuation?bazinYield=0.08&dcfDiscountRate=0.14&dcfYears=10
+// =======================
+func (h *FundamentalsHandler) GetValuation(c *gin.Context) {
+	overrides := map[string]float64{}
+	for _, name := range valuation.Params {
+		v, err := optionalFloat(c, name)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		if v != nil {
+			overrides[name] = *v
+		}
+	}
+
+	result, err := h.GetValuationUC.Execute(c.Param("symbol"), overrides)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"net/http"
+
+	"cotacoes/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthHandler struct {
+	GetHealthUC *usecase.GetHealthUseCase
+}
+
+func NewHealthHandler(getHealthUC *usecase.GetHealthUseCase) *HealthHandler {
+	return &HealthHandler{
+		GetHealthUC: getHealthUC,
+	}
+}
+
+// GET /health
+// Sempre responde 200: modo degradado continua servindo dados do snapshot
+func (h *HealthHandler) GetHealth(c *gin.Context) {
+	c.JSON(http.StatusOK, h.GetHealthUC.Execute())
+}
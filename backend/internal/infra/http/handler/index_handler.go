@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"net/http"
+
+	"cotacoes/internal/domain"
+	"cotacoes/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type IndexHandler struct {
+	CustomIndexUC *usecase.CustomIndexUseCase
+}
+
+func NewIndexHandler(customIndexUC *usecase.CustomIndexUseCase) *IndexHandler {
+	return &IndexHandler{
+		CustomIndexUC: customIndexUC,
+	}
+}
+
+// GET /indexes/custom (índices do usuário do header X-User-ID)
+func (h *IndexHandler) List(c *gin.Context) {
+	list, err := h.CustomIndexUC.List(userID(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// =======================
+// POST /indexes/custom
+// Body: {"name": "Bancões", "weighting": "custom", "rebalance": "quarterly",
+//
+//	"constituents": [{"symbol": "ITUB4", "weight": 30}, {"symbol": "BBDC4", "weight": 30},
+//	                 {"symbol": "BBAS3", "weight": 20}, {"symbol": "SANB11", "weight": 20}]}
+//
+// =======================
+func (h *IndexHandler) Create(c *gin.Context) {
+	var body domain.CustomIndex
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	idx, err := h.CustomIndexUC.Create(userID(c), body)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, idx)
+}
+
+// =======================
+// GET /indexes/custom/:id
+// Ex: /indexes/custom/bancoes?range=5y&interval=1d&adjust=total&resample=1mo
+// O índice também pode ser usado como símbolo: benchmark=custom:bancoes
+// =======================
+func (h *IndexHandler) Get(c *gin.Context) {
+	result, err := h.CustomIndexUC.Execute(
+		c.Param("id"),
+		c.DefaultQuery("range", "1y"),
+		c.DefaultQuery("interval", "1d"),
+		c.Query("adjust"),
+		c.Query("resample"),
+	)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// PUT /indexes/custom/:id (apenas o dono)
+func (h *IndexHandler) Update(c *gin.Context) {
+	var body domain.CustomIndex
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	idx, err := h.CustomIndexUC.Update(userID(c), c.Param("id"), body)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, idx)
+}
+
+// DELETE /indexes/custom/:id (apenas o dono)
+func (h *IndexHandler) Delete(c *gin.Context) {
+	if err := h.CustomIndexUC.Delete(userID(c), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+
+	"cotacoes/internal/analytics/market"
+
+	"cotacoes/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type MarketHandler struct {
+	MarketMoversUC  *usecase.MarketMoversUseCase
+	MarketHeatmapUC *usecase.MarketHeatmapUseCase
+}
+
+func NewMarketHandler(
+	marketMoversUC *usecase.MarketMoversUseCase,
+	marketHeatmapUC *usecase.MarketHeatmapUseCase,
+) *MarketHandler {
+	return &MarketHandler{
+		MarketMoversUC:  marketMoversUC,
+		MarketHeatmapUC: marketHeatmapUC,
+	}
+}
+
+// =======================
+// GET /market/movers
+// Ex: /market/movers?type=gainers&limit=10&sector=Finance&assetType=stock&minVolume=500000
+// =======================
+func (h *MarketHandler) GetMovers(c *gin.Context) {
+	limit, err := optionalInt(c, "limit")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var minVolume *int64
+	if c.Query("minVolume") != "" {
+		v, err := optionalInt(c, "minVolume")
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": err.Error(),
+			})
+			return
+		}
+		mv := int64(v)
+		minVolume = &mv
+	}
+
+	result, err := h.MarketMoversUC.Execute(
+		c.Query("type"),
+		limit,
+		c.Query("sector"),
+		c.Query("assetType"),
+		minVolume,
+	)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// GET /market/heatmap
+// Ex: /market/heatmap?groupBy=sector&sizeBy=market_cap&colorBy=change&maxLeaves=20&colorRange=3&type=stock
+// =======================
+func (h *MarketHandler) GetHeatmap(c *gin.Context) {
+	maxLeaves, err := optionalInt(c, "maxLeaves")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	colorRange, err := optionalFloat(c, "colorRange")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	opts := market.HeatmapOptions{
+		GroupBy:   c.Query("groupBy"),
+		SizeBy:    c.Query("sizeBy"),
+		ColorBy:   c.Query("colorBy"),
+		MaxLeaves: maxLeaves,
+	}
+	if colorRange != nil {
+		opts.ColorRange = *colorRange
+	}
+
+	result, err := h.MarketHeatmapUC.Execute(opts, c.Query("type"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
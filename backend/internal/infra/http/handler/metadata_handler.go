@@ -9,17 +9,20 @@ import (
 )
 
 type MetadataHandler struct {
-	ListSectorsUC *usecase.ListSectorsUseCase
-	ListTypesUC   *usecase.ListTypesUseCase
+	ListSectorsUC   *usecase.ListSectorsUseCase
+	ListTypesUC     *usecase.ListTypesUseCase
+	SectorSummaryUC *usecase.SectorSummaryUseCase
 }
 
 func NewMetadataHandler(
 	listSectorsUC *usecase.ListSectorsUseCase,
 	listTypesUC *usecase.ListTypesUseCase,
+	sectorSummaryUC *usecase.SectorSummaryUseCase,
 ) *MetadataHandler {
 	return &MetadataHandler{
-		ListSectorsUC: listSectorsUC,
-		ListTypesUC:   listTypesUC,
+		ListSectorsUC:   listSectorsUC,
+		ListTypesUC:     listTypesUC,
+		SectorSummaryUC: sectorSummaryUC,
 	}
 }
 
@@ -38,6 +41,54 @@ func (h *MetadataHandler) ListSectors(c *gin.Context) {
 	})
 }
 
+// =======================
+// GET /sectors/summary
+// Ex: /sectors/summary?type=stock&top=5
+// =======================
+func (h *MetadataHandler) GetSectorsSummary(c *gin.Context) {
+	top, err := optionalInt(c, "top")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.SectorSummaryUC.Execute(c.Query("type"), top)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// GET /sectors/:name/summary
+// Ex: /sectors/Finance/summary?top=10
+// =======================
+func (h *MetadataHandler) GetSectorSummary(c *gin.Context) {
+	top, err := optionalInt(c, "top")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.SectorSummaryUC.ExecuteSector(c.Param("name"), c.Query("type"), top)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
 // GET /types
 func (h *MetadataHandler) ListTypes(c *gin.Context) {
 	sector := c.Query("sector")
@@ -0,0 +1,317 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"cotacoes/internal/domain"
+	"cotacoes/internal/importer"
+	"cotacoes/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxImportSize limita o tamanho do arquivo importado
+const maxImportSize = 10 << 20
+
+type PortfolioHandler struct {
+	PortfolioUC   *usecase.PortfolioUseCase
+	TaxReportUC   *usecase.TaxReportUseCase
+	ImportUC      *usecase.PortfolioImportUseCase
+	PerformanceUC *usecase.PortfolioPerformanceUseCase
+}
+
+func NewPortfolioHandler(
+	portfolioUC *usecase.PortfolioUseCase,
+	taxReportUC *usecase.TaxReportUseCase,
+	importUC *usecase.PortfolioImportUseCase,
+	performanceUC *usecase.PortfolioPerformanceUseCase,
+) *PortfolioHandler {
+	return &PortfolioHandler{
+		PortfolioUC:   portfolioUC,
+		TaxReportUC:   taxReportUC,
+		ImportUC:      importUC,
+		PerformanceUC: performanceUC,
+	}
+}
+
+// portfolioBody é o corpo de criação/edição de uma carteira
+type portfolioBody struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// GET /portfolios
+func (h *PortfolioHandler) List(c *gin.Context) {
+	list, err := h.PortfolioUC.List(userID(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// =======================
+// POST /portfolios
+// Body: {"name": "Dividendos", "description": "carteira de longo prazo"}
+// =======================
+func (h *PortfolioHandler) Create(c *gin.Context) {
+	var body portfolioBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	p, err := h.PortfolioUC.Create(userID(c), body.Name, body.Description)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, p)
+}
+
+// =======================
+// GET /portfolios/:id
+// Posições com preço médio, resultado realizado/não realizado e alocação
+// Ex: /portfolios/3f9a1c2b7d4e (header X-User-ID: joao)
+// =======================
+func (h *PortfolioHandler) Get(c *gin.Context) {
+	result, err := h.PortfolioUC.Summary(userID(c), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// PUT /portfolios/:id
+func (h *PortfolioHandler) Update(c *gin.Context) {
+	var body portfolioBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	p, err := h.PortfolioUC.Update(userID(c), c.Param("id"), body.Name, body.Description)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, p)
+}
+
+// DELETE /portfolios/:id
+func (h *PortfolioHandler) Delete(c *gin.Context) {
+	if err := h.PortfolioUC.Delete(userID(c), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// =======================
+// GET /portfolios/:id/transactions
+// Ex: /portfolios/3f9a1c2b7d4e/transactions?symbol=ITUB4
+// =======================
+func (h *PortfolioHandler) ListTransactions(c *gin.Context) {
+	list, err := h.PortfolioUC.Transactions(userID(c), c.Param("id"), c.Query("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// =======================
+// POST /portfolios/:id/transactions
+// Body: {"type": "buy", "symbol": "ITUB4", "date": "2024-03-15", "quantity": 100, "price": 32.5, "fees": 4.9}
+// Header opcional Idempotency-Key (ou campo idempotencyKey): repetir a
+// requisição devolve o lançamento já criado (200) em vez de duplicá-lo
+// =======================
+func (h *PortfolioHandler) AddTransaction(c *gin.Context) {
+	var body domain.Transaction
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+	if key := strings.TrimSpace(c.GetHeader("Idempotency-Key")); key != "" {
+		body.IdempotencyKey = key
+	}
+
+	tx, created, err := h.PortfolioUC.AddTransaction(userID(c), c.Param("id"), body)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	status := http.StatusCreated
+	if !created {
+		status = http.StatusOK
+	}
+	c.JSON(status, tx)
+}
+
+// DELETE /portfolios/:id/transactions/:txId
+func (h *PortfolioHandler) DeleteTransaction(c *gin.Context) {
+	if err := h.PortfolioUC.DeleteTransaction(userID(c), c.Param("id"), c.Param("txId")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// =======================
+// GET /portfolios/:id/realized
+// Vendas com resultado apurado (swing trade e day trade) e proventos
+// =======================
+func (h *PortfolioHandler) GetRealized(c *gin.Context) {
+	result, err := h.PortfolioUC.Realized(userID(c), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// GET /portfolios/:id/tax
+// Apuração mensal do IR (swing trade e day trade) com o DARF a pagar
+// Ex: /portfolios/3f9a1c2b7d4e/tax?year=2024
+// =======================
+func (h *PortfolioHandler) GetTax(c *gin.Context) {
+	year, err := optionalInt(c, "year")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	result, err := h.TaxReportUC.Execute(userID(c), c.Param("id"), year)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// GET /portfolios/:id/performance
+// Rentabilidade (TWR e XIRR) comparada aos benchmarks, com série
+// acumulada e tabela mensal. Benchmarks: cdi, ibov, ipca, ipca+X,
+// símbolos e índices customizados
+// Ex: /portfolios/3f9a1c2b7d4e/performance?range=1y&benchmarks=cdi,ibov,ipca+6,custom:bancoes
+// =======================
+func (h *PortfolioHandler) GetPerformance(c *gin.Context) {
+	var benchmarks []string
+	if v := c.Query("benchmarks"); v != "" {
+		benchmarks = strings.Split(v, ",")
+	}
+
+	result, err := h.PerformanceUC.Execute(userID(c), c.Param("id"), c.Query("range"), benchmarks)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// =======================
+// POST /portfolios/:id/import
+// Multipart com "file" (CSV ou XLSX) e, no formato csv, "mapping" (JSON)
+// Ex: /portfolios/3f9a1c2b7d4e/import?format=b3-negociacao&dryRun=true
+// Formatos: b3-negociacao, b3-movimentacao, csv
+// =======================
+func (h *PortfolioHandler) Import(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportSize)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "envie o arquivo no campo file: " + err.Error(),
+		})
+		return
+	}
+	f, err := file.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	var mapping *importer.Mapping
+	if raw := c.PostForm("mapping"); raw != "" {
+		mapping = &importer.Mapping{}
+		if err := json.Unmarshal([]byte(raw), mapping); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error": "mapping inválido: " + err.Error(),
+			})
+			return
+		}
+	}
+
+	format := c.Query("format")
+	if format == "" {
+		format = c.PostForm("format")
+	}
+	dryRun := c.Query("dryRun") == "true"
+
+	result, err := h.ImportUC.Execute(userID(c), c.Param("id"), format, data, mapping, dryRun)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
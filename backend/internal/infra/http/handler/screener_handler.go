@@ -0,0 +1,124 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"cotacoes/internal/domain"
+	"cotacoes/internal/screener"
+	"cotacoes/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ScreenerHandler struct {
+	ScreenerUC *usecase.ScreenerUseCase
+}
+
+func NewScreenerHandler(screenerUC *usecase.ScreenerUseCase) *ScreenerHandler {
+	return &ScreenerHandler{
+		ScreenerUC: screenerUC,
+	}
+}
+
+// =======================
+// POST /screener
+// Body: {"expression": "sector in (\"Finance\",\"Utilities\") and dy_ttm > 6 and pe < 10",
+//
+//	"sort": "dy_ttm", "order": "desc", "page": 1, "perPage": 50}
+//
+// ou {"screen": "dividendos-baratos"} para rodar um screen salvo
+// =======================
+func (h *ScreenerHandler) Run(c *gin.Context) {
+	var req usecase.ScreenerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	result, err := h.ScreenerUC.Execute(req)
+	if err != nil {
+		c.JSON(errorStatus(err), screenerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// GET /screener/fields
+func (h *ScreenerHandler) ListFields(c *gin.Context) {
+	c.JSON(http.StatusOK, h.ScreenerUC.Fields())
+}
+
+// GET /screener/screens
+func (h *ScreenerHandler) ListScreens(c *gin.Context) {
+	screens, err := h.ScreenerUC.ListScreens()
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, screens)
+}
+
+// GET /screener/screens/:name
+func (h *ScreenerHandler) GetScreen(c *gin.Context) {
+	screen, err := h.ScreenerUC.GetScreen(c.Param("name"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, screen)
+}
+
+// =======================
+// PUT /screener/screens/:name
+// Body: {"expression": "dy_ttm > 6 and payout < 80", "description": "...", "sort": "dy_ttm"}
+// =======================
+func (h *ScreenerHandler) SaveScreen(c *gin.Context) {
+	var body domain.SavedScreen
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	screen, err := h.ScreenerUC.SaveScreen(c.Param("name"), body)
+	if err != nil {
+		c.JSON(errorStatus(err), screenerError(err))
+		return
+	}
+
+	c.JSON(http.StatusOK, screen)
+}
+
+// DELETE /screener/screens/:name
+func (h *ScreenerHandler) DeleteScreen(c *gin.Context) {
+	if err := h.ScreenerUC.DeleteScreen(c.Param("name")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// screenerError inclui a posição do erro na expressão, quando houver
+func screenerError(err error) gin.H {
+	body := gin.H{"error": err.Error()}
+	var exprErr *screener.Error
+	if errors.As(err, &exprErr) {
+		body["position"] = exprErr.Pos
+		body["message"] = exprErr.Msg
+	}
+	return body
+}
@@ -4,7 +4,6 @@ import (
 	"net/http"
 	"strconv"
 
-	"cotacoes/internal/domain"
 	"cotacoes/internal/usecase"
 
 	"github.com/gin-gonic/gin"
@@ -27,7 +26,7 @@ func NewStockHandler(
 
 // =======================
 // GET /stocks/:symbol
-// Ex: /stocks/PETR4?range=1y&interval=1d
+// Ex: /stocks/PETR4?range=1y&interval=1d&resample=1wk&adjust=total
 // =======================
 func (h *StockHandler) GetStockBySymbol(c *gin.Context) {
 	symbol := c.Param("symbol")
@@ -35,14 +34,12 @@ func (h *StockHandler) GetStockBySymbol(c *gin.Context) {
 	rangeParam := c.DefaultQuery("range", "1d")
 	intervalParam := c.DefaultQuery("interval", "1d")
 
-	stock, err := h.GetStockUC.Execute(symbol, rangeParam, intervalParam)
+	stock, err := h.GetStockUC.ExecuteWithOptions(symbol, rangeParam, intervalParam, usecase.StockOptions{
+		Adjust:   c.Query("adjust"),
+		Resample: c.Query("resample"),
+	})
 	if err != nil {
-		status := http.StatusInternalServerError
-		if err == domain.ErrStockNotFound {
-			status = http.StatusNotFound
-		}
-
-		c.JSON(status, gin.H{
+		c.JSON(errorStatus(err), gin.H{
 			"error": err.Error(),
 		})
 		return
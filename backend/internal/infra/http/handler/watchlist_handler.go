@@ -0,0 +1,239 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"cotacoes/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultUser é o dono usado quando a requisição não informa X-User-ID
+const defaultUser = "default"
+
+type WatchlistHandler struct {
+	WatchlistUC *usecase.WatchlistUseCase
+}
+
+func NewWatchlistHandler(watchlistUC *usecase.WatchlistUseCase) *WatchlistHandler {
+	return &WatchlistHandler{
+		WatchlistUC: watchlistUC,
+	}
+}
+
+// GET /watchlists
+func (h *WatchlistHandler) List(c *gin.Context) {
+	list, err := h.WatchlistUC.List(userID(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, list)
+}
+
+// =======================
+// POST /watchlists
+// Body: {"name": "Bancos", "items": [{"symbol": "ITUB4", "note": "esperar 30"}, {"symbol": "BBAS3"}]}
+// =======================
+func (h *WatchlistHandler) Create(c *gin.Context) {
+	var body struct {
+		Name  string                       `json:"name"`
+		Items []usecase.WatchlistItemInput `json:"items"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	w, err := h.WatchlistUC.Create(userID(c), body.Name, body.Items)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, w)
+}
+
+// GET /watchlists/:id
+func (h *WatchlistHandler) Get(c *gin.Context) {
+	w, err := h.WatchlistUC.Get(userID(c), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, w)
+}
+
+// =======================
+// PUT /watchlists/:id
+// Body: {"name": "Bancos e seguradoras"}
+// =======================
+func (h *WatchlistHandler) Rename(c *gin.Context) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	w, err := h.WatchlistUC.Rename(userID(c), c.Param("id"), body.Name)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, w)
+}
+
+// DELETE /watchlists/:id
+func (h *WatchlistHandler) Delete(c *gin.Context) {
+	if err := h.WatchlistUC.Delete(userID(c), c.Param("id")); err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// GET /watchlists/:id/items
+func (h *WatchlistHandler) ListItems(c *gin.Context) {
+	w, err := h.WatchlistUC.Get(userID(c), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, w.Items)
+}
+
+// =======================
+// POST /watchlists/:id/items
+// Body: {"symbol": "SANB11", "note": "dividendos", "position": 1}
+// =======================
+func (h *WatchlistHandler) AddItem(c *gin.Context) {
+	var body usecase.WatchlistItemInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	w, err := h.WatchlistUC.AddItem(userID(c), c.Param("id"), body)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, w)
+}
+
+// =======================
+// PUT /watchlists/:id/items
+// Reordena os itens: a lista deve conter todos os ativos da watchlist
+// Body: {"symbols": ["BBAS3", "ITUB4", "SANB11"]}
+// =======================
+func (h *WatchlistHandler) ReorderItems(c *gin.Context) {
+	var body struct {
+		Symbols []string `json:"symbols"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	w, err := h.WatchlistUC.Reorder(userID(c), c.Param("id"), body.Symbols)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, w)
+}
+
+// =======================
+// PUT /watchlists/:id/items/:symbol
+// Body: {"note": "comprar abaixo de 25", "position": 2}
+// =======================
+func (h *WatchlistHandler) UpdateItem(c *gin.Context) {
+	var body usecase.WatchlistItemInput
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error": "corpo inválido: " + err.Error(),
+		})
+		return
+	}
+
+	w, err := h.WatchlistUC.UpdateItem(userID(c), c.Param("id"), c.Param("symbol"), body)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, w)
+}
+
+// DELETE /watchlists/:id/items/:symbol
+func (h *WatchlistHandler) RemoveItem(c *gin.Context) {
+	w, err := h.WatchlistUC.RemoveItem(userID(c), c.Param("id"), c.Param("symbol"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, w)
+}
+
+// =======================
+// GET /watchlists/:id/quotes
+// Ex: /watchlists/3f9a1c2b7d4e/quotes (header X-User-ID: joao)
+// =======================
+func (h *WatchlistHandler) GetQuotes(c *gin.Context) {
+	result, err := h.WatchlistUC.Quotes(userID(c), c.Param("id"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{
+			"error": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// userID identifica o dono dos dados pelo header X-User-ID
+func userID(c *gin.Context) string {
+	if id := strings.TrimSpace(c.GetHeader("X-User-ID")); id != "" {
+		return id
+	}
+	return defaultUser
+}
@@ -8,8 +8,17 @@ import (
 )
 
 type Handlers struct {
-	StockHandler    *handler.StockHandler
-	MetadataHandler *handler.MetadataHandler
+	StockHandler        *handler.StockHandler
+	MetadataHandler     *handler.MetadataHandler
+	AnalyticsHandler    *handler.AnalyticsHandler
+	DividendHandler     *handler.DividendHandler
+	FundamentalsHandler *handler.FundamentalsHandler
+	ScreenerHandler     *handler.ScreenerHandler
+	MarketHandler       *handler.MarketHandler
+	IndexHandler        *handler.IndexHandler
+	WatchlistHandler    *handler.WatchlistHandler
+	PortfolioHandler    *handler.PortfolioHandler
+	HealthHandler       *handler.HealthHandler
 }
 
 func SetupRouter(h Handlers) *gin.Engine {
@@ -18,14 +27,61 @@ func SetupRouter(h Handlers) *gin.Engine {
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     []string{"http://localhost:5173", "https://frontend-cotacoes.onrender.com"},
 		AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "X-User-ID", "Idempotency-Key"},
 		AllowCredentials: true,
 	}))
 
 	r.GET("/stocks/:symbol", h.StockHandler.GetStockBySymbol)
+	r.GET("/stocks/:symbol/indicators", h.AnalyticsHandler.GetIndicators)
+	r.GET("/stocks/:symbol/stats", h.AnalyticsHandler.GetStats)
+	r.GET("/stocks/:symbol/dividends/summary", h.DividendHandler.GetSummary)
+	r.GET("/stocks/:symbol/ratios", h.FundamentalsHandler.GetRatios)
+	r.GET("/stocks/:symbol/valuation", h.FundamentalsHandler.GetValuation)
 	r.GET("/cotacoes", h.StockHandler.ListStocks)
+	r.GET("/analytics/correlation", h.AnalyticsHandler.GetCorrelation)
+	r.GET("/compare", h.AnalyticsHandler.Compare)
+	r.GET("/dividends/calendar", h.DividendHandler.GetCalendar)
+	r.GET("/market/movers", h.MarketHandler.GetMovers)
+	r.GET("/market/heatmap", h.MarketHandler.GetHeatmap)
+	r.GET("/indexes/custom", h.IndexHandler.List)
+	r.POST("/indexes/custom", h.IndexHandler.Create)
+	r.GET("/indexes/custom/:id", h.IndexHandler.Get)
+	r.PUT("/indexes/custom/:id", h.IndexHandler.Update)
+	r.DELETE("/indexes/custom/:id", h.IndexHandler.Delete)
+	r.GET("/watchlists", h.WatchlistHandler.List)
+	r.POST("/watchlists", h.WatchlistHandler.Create)
+	r.GET("/watchlists/:id", h.WatchlistHandler.Get)
+	r.PUT("/watchlists/:id", h.WatchlistHandler.Rename)
+	r.DELETE("/watchlists/:id", h.WatchlistHandler.Delete)
+	r.GET("/watchlists/:id/items", h.WatchlistHandler.ListItems)
+	r.POST("/watchlists/:id/items", h.WatchlistHandler.AddItem)
+	r.PUT("/watchlists/:id/items", h.WatchlistHandler.ReorderItems)
+	r.PUT("/watchlists/:id/items/:symbol", h.WatchlistHandler.UpdateItem)
+	r.DELETE("/watchlists/:id/items/:symbol", h.WatchlistHandler.RemoveItem)
+	r.GET("/watchlists/:id/quotes", h.WatchlistHandler.GetQuotes)
+	r.GET("/portfolios", h.PortfolioHandler.List)
+	r.POST("/portfolios", h.PortfolioHandler.Create)
+	r.GET("/portfolios/:id", h.PortfolioHandler.Get)
+	r.PUT("/portfolios/:id", h.PortfolioHandler.Update)
+	r.DELETE("/portfolios/:id", h.PortfolioHandler.Delete)
+	r.GET("/portfolios/:id/transactions", h.PortfolioHandler.ListTransactions)
+	r.POST("/portfolios/:id/transactions", h.PortfolioHandler.AddTransaction)
+	r.DELETE("/portfolios/:id/transactions/:txId", h.PortfolioHandler.DeleteTransaction)
+	r.GET("/portfolios/:id/realized", h.PortfolioHandler.GetRealized)
+	r.GET("/portfolios/:id/tax", h.PortfolioHandler.GetTax)
+	r.GET("/portfolios/:id/performance", h.PortfolioHandler.GetPerformance)
+	r.POST("/portfolios/:id/import", h.PortfolioHandler.Import)
+	r.POST("/screener", h.ScreenerHandler.Run)
+	r.GET("/screener/fields", h.ScreenerHandler.ListFields)
+	r.GET("/screener/screens", h.ScreenerHandler.ListScreens)
+	r.GET("/screener/screens/:name", h.ScreenerHandler.GetScreen)
+	r.PUT("/screener/screens/:name", h.ScreenerHandler.SaveScreen)
+	r.DELETE("/screener/screens/:name", h.ScreenerHandler.DeleteScreen)
 	r.GET("/sectors", h.MetadataHandler.ListSectors)
+	r.GET("/sectors/summary", h.MetadataHandler.GetSectorsSummary)
+	r.GET("/sectors/:name/summary", h.MetadataHandler.GetSectorSummary)
 	r.GET("/types", h.MetadataHandler.ListTypes)
+	r.GET("/health", h.HealthHandler.GetHealth)
 
 	return r
 }
@@ -0,0 +1,64 @@
+package resilience
+
+import "cotacoes/internal/domain"
+
+// UpstreamProvider é o contrato de um provider completo (listagem + detalhe)
+type UpstreamProvider interface {
+	domain.StockProvider
+	domain.StockRepository
+}
+
+// BreakerProvider envolve um provider com um circuit breaker.
+// Enquanto o circuito está aberto, as chamadas falham imediatamente com
+// ErrCircuitOpen, permitindo que os repositórios usem snapshot/cache sem
+// esperar o timeout do http.Get.
+type BreakerProvider struct {
+	Provider UpstreamProvider
+	Breaker  *CircuitBreaker
+}
+
+func NewBreakerProvider(provider UpstreamProvider, breaker *CircuitBreaker) *BreakerProvider {
+	return &BreakerProvider{
+		Provider: provider,
+		Breaker:  breaker,
+	}
+}
+
+// ListAllStocks chama o provider protegido pelo breaker
+func (p *BreakerProvider) ListAllStocks(
+	sector, stockType, sortBy, sortOrder string,
+	page, perPage int,
+) (*domain.AllStocksResponse, error) {
+
+	var resp *domain.AllStocksResponse
+	err := p.Breaker.Execute(func() error {
+		var err error
+		resp, err = p.Provider.ListAllStocks(sector, stockType, sortBy, sortOrder, page, perPage)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return resp, nil
+}
+
+// GetBySymbol chama o detalhe da ação protegido pelo breaker
+func (p *BreakerProvider) GetBySymbol(symbol, rangeParam, intervalParam string) (*domain.Stock, error) {
+	var stock *domain.Stock
+	err := p.Breaker.Execute(func() error {
+		var err error
+		stock, err = p.Provider.GetBySymbol(symbol, rangeParam, intervalParam)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return stock, nil
+}
+
+// Health expõe o estado do breaker
+func (p *BreakerProvider) Health() domain.ProviderHealth {
+	return p.Breaker.Health()
+}
@@ -0,0 +1,244 @@
+package resilience
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// ErrCircuitOpen é retornado quando o breaker está aberto e a chamada
+// nem chega a ser enviada ao provider.
+var ErrCircuitOpen = errors.New("circuit breaker aberto: provider indisponível")
+
+// State representa o estado do circuit breaker
+type State int
+
+const (
+	StateClosed State = iota
+	StateOpen
+	StateHalfOpen
+)
+
+func (s State) String() string {
+	switch s {
+	case StateOpen:
+		return "open"
+	case StateHalfOpen:
+		return "half-open"
+	default:
+		return "closed"
+	}
+}
+
+// BreakerConfig define os limites do circuit breaker
+type BreakerConfig struct {
+	// Falhas consecutivas necessárias para abrir o circuito
+	FailureThreshold int
+	// Tempo que o circuito fica aberto antes de permitir chamadas de teste
+	OpenTimeout time.Duration
+	// Quantidade de chamadas de teste simultâneas permitidas em half-open
+	HalfOpenMaxCalls int
+	// Sucessos em half-open necessários para fechar o circuito
+	SuccessThreshold int
+}
+
+// CircuitBreaker implementa o padrão closed → open → half-open
+type CircuitBreaker struct {
+	name string
+	cfg  BreakerConfig
+
+	mu               sync.Mutex
+	state            State
+	failures         int
+	successes        int
+	halfOpenInFlight int
+	generation       uint64 // muda a cada transição de estado
+	openedAt         time.Time
+	lastError        string
+	lastFailureAt    time.Time
+
+	now func() time.Time
+}
+
+// NewCircuitBreaker cria um breaker fechado com defaults defensivos
+func NewCircuitBreaker(name string, cfg BreakerConfig) *CircuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.OpenTimeout <= 0 {
+		cfg.OpenTimeout = 30 * time.Second
+	}
+	if cfg.HalfOpenMaxCalls <= 0 {
+		cfg.HalfOpenMaxCalls = 1
+	}
+	if cfg.SuccessThreshold <= 0 {
+		cfg.SuccessThreshold = 1
+	}
+
+	return &CircuitBreaker{
+		name: name,
+		cfg:  cfg,
+		now:  time.Now,
+	}
+}
+
+// Name retorna o nome do breaker (normalmente o nome do provider)
+func (cb *CircuitBreaker) Name() string {
+	return cb.name
+}
+
+// State retorna o estado atual, já considerando o timeout de abertura
+func (cb *CircuitBreaker) State() State {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refreshState()
+	return cb.state
+}
+
+// Execute roda fn se o circuito permitir.
+// Erros de negócio (ex: ação não encontrada) não contam como falha do provider.
+func (cb *CircuitBreaker) Execute(fn func() error) error {
+	generation, err := cb.before()
+	if err != nil {
+		return err
+	}
+
+	err = fn()
+	cb.after(generation, err)
+	return err
+}
+
+// before autoriza a chamada e devolve a geração do estado em que ela
+// começou
+func (cb *CircuitBreaker) before() (uint64, error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refreshState()
+
+	switch cb.state {
+	case StateOpen:
+		return 0, ErrCircuitOpen
+	case StateHalfOpen:
+		if cb.halfOpenInFlight >= cb.cfg.HalfOpenMaxCalls {
+			return 0, ErrCircuitOpen
+		}
+		cb.halfOpenInFlight++
+	}
+
+	return cb.generation, nil
+}
+
+// after registra o resultado. Chamadas que começaram em outro estado (ex:
+// iniciadas com o circuito fechado e concluídas depois que ele abriu) são
+// ignoradas: não liberam vagas de teste nem zeram as falhas do estado atual.
+func (cb *CircuitBreaker) after(generation uint64, err error) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if generation != cb.generation {
+		return
+	}
+
+	if cb.state == StateHalfOpen && cb.halfOpenInFlight > 0 {
+		cb.halfOpenInFlight--
+	}
+
+	if err == nil || isBusinessError(err) {
+		cb.onSuccess()
+		return
+	}
+
+	cb.onFailure(err)
+}
+
+func (cb *CircuitBreaker) onSuccess() {
+	switch cb.state {
+	case StateHalfOpen:
+		cb.successes++
+		if cb.successes >= cb.cfg.SuccessThreshold {
+			cb.toClosed()
+		}
+	default:
+		cb.failures = 0
+	}
+}
+
+func (cb *CircuitBreaker) onFailure(err error) {
+	cb.lastError = err.Error()
+	cb.lastFailureAt = cb.now()
+
+	switch cb.state {
+	case StateHalfOpen:
+		// Qualquer falha durante o teste reabre o circuito
+		cb.toOpen()
+	default:
+		cb.failures++
+		if cb.failures >= cb.cfg.FailureThreshold {
+			cb.toOpen()
+		}
+	}
+}
+
+// refreshState move de open para half-open quando o timeout expira.
+// Deve ser chamado com o mutex travado.
+func (cb *CircuitBreaker) refreshState() {
+	if cb.state == StateOpen && cb.now().Sub(cb.openedAt) >= cb.cfg.OpenTimeout {
+		cb.state = StateHalfOpen
+		cb.generation++
+		cb.successes = 0
+		cb.halfOpenInFlight = 0
+	}
+}
+
+func (cb *CircuitBreaker) toOpen() {
+	cb.state = StateOpen
+	cb.generation++
+	cb.openedAt = cb.now()
+	cb.successes = 0
+}
+
+func (cb *CircuitBreaker) toClosed() {
+	cb.state = StateClosed
+	cb.generation++
+	cb.failures = 0
+	cb.successes = 0
+	cb.openedAt = time.Time{}
+}
+
+// Health expõe o estado do breaker para o endpoint /health
+func (cb *CircuitBreaker) Health() domain.ProviderHealth {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	cb.refreshState()
+
+	h := domain.ProviderHealth{
+		Name:                cb.name,
+		State:               cb.state.String(),
+		ConsecutiveFailures: cb.failures,
+		LastError:           cb.lastError,
+	}
+	if !cb.openedAt.IsZero() {
+		openedAt := cb.openedAt
+		h.OpenedAt = &openedAt
+		if cb.state == StateOpen {
+			retryAt := cb.openedAt.Add(cb.cfg.OpenTimeout)
+			h.RetryAt = &retryAt
+		}
+	}
+	if !cb.lastFailureAt.IsZero() {
+		lastFailureAt := cb.lastFailureAt
+		h.LastFailureAt = &lastFailureAt
+	}
+
+	return h
+}
+
+// isBusinessError indica erros que não significam indisponibilidade do provider
+func isBusinessError(err error) bool {
+	return errors.Is(err, domain.ErrStockNotFound)
+}
@@ -0,0 +1,112 @@
+package resilience
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+var errTimeout = errors.New("timeout")
+
+func newTestBreaker() (*CircuitBreaker, *time.Time) {
+	cb := NewCircuitBreaker("brapi", BreakerConfig{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Minute,
+		HalfOpenMaxCalls: 1,
+		SuccessThreshold: 1,
+	})
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cb.now = func() time.Time { return now }
+	return cb, &now
+}
+
+func fail() error    { return errTimeout }
+func succeed() error { return nil }
+
+func TestBreakerStates(t *testing.T) {
+	cb, now := newTestBreaker()
+
+	// Erro de negócio não conta como falha
+	cb.Execute(func() error { return domain.ErrStockNotFound })
+	cb.Execute(fail)
+	if cb.State() != StateClosed {
+		t.Fatalf("estado = %s, esperado closed após 1 falha", cb.State())
+	}
+	cb.Execute(fail)
+	if cb.State() != StateOpen {
+		t.Fatalf("estado = %s, esperado open após 2 falhas", cb.State())
+	}
+	if err := cb.Execute(succeed); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("erro = %v, esperado ErrCircuitOpen", err)
+	}
+
+	*now = now.Add(time.Minute)
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("estado = %s, esperado half-open após o timeout", cb.State())
+	}
+	// Falha no teste reabre
+	cb.Execute(fail)
+	if cb.State() != StateOpen {
+		t.Fatalf("estado = %s, esperado open após falha em half-open", cb.State())
+	}
+
+	*now = now.Add(time.Minute)
+	if err := cb.Execute(succeed); err != nil {
+		t.Fatal(err)
+	}
+	if cb.State() != StateClosed {
+		t.Errorf("estado = %s, esperado closed após sucesso em half-open", cb.State())
+	}
+}
+
+func TestBreakerIgnoresStaleSuccess(t *testing.T) {
+	cb, _ := newTestBreaker()
+
+	// Chamada lenta iniciada com o circuito fechado
+	slow, err := cb.before()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cb.Execute(fail)
+	cb.Execute(fail)
+
+	// O sucesso atrasado não zera as falhas nem fecha o circuito
+	cb.after(slow, nil)
+	if cb.State() != StateOpen {
+		t.Errorf("estado = %s, esperado open", cb.State())
+	}
+	if h := cb.Health(); h.ConsecutiveFailures != 2 {
+		t.Errorf("falhas = %d, esperado 2", h.ConsecutiveFailures)
+	}
+}
+
+func TestBreakerStaleCallKeepsHalfOpenLimit(t *testing.T) {
+	cb, now := newTestBreaker()
+
+	slow, _ := cb.before()
+	cb.Execute(fail)
+	cb.Execute(fail)
+	*now = now.Add(time.Minute)
+
+	// Chamada de teste em andamento ocupa a única vaga
+	probe, err := cb.before()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A chamada antiga termina: não libera a vaga nem conta como sucesso
+	cb.after(slow, nil)
+	if cb.State() != StateHalfOpen {
+		t.Fatalf("estado = %s, esperado half-open", cb.State())
+	}
+	if _, err := cb.before(); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("erro = %v, esperado ErrCircuitOpen (vaga de teste ocupada)", err)
+	}
+
+	cb.after(probe, nil)
+	if cb.State() != StateClosed {
+		t.Errorf("estado = %s, esperado closed após o teste", cb.State())
+	}
+}
@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"cotacoes/internal/domain"
+)
+
+// legacyIndexOwner é o dono dos índices gravados antes de existir dono:
+// o usuário padrão das requisições sem X-User-ID
+const legacyIndexOwner = "default"
+
+// CustomIndexFileRepo guarda os índices customizados em um arquivo JSON
+type CustomIndexFileRepo struct {
+	path string
+	mu   sync.Mutex
+}
+
+// storedCustomIndex inclui o dono, que não é exposto na API
+type storedCustomIndex struct {
+	Owner string `json:"owner"`
+	domain.CustomIndex
+}
+
+func NewCustomIndexFileRepo(path string) *CustomIndexFileRepo {
+	return &CustomIndexFileRepo{path: path}
+}
+
+func (r *CustomIndexFileRepo) List(owner string) ([]domain.CustomIndex, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]domain.CustomIndex, 0)
+	for _, idx := range stored {
+		if idx.Owner == owner {
+			list = append(list, idx.index())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].ID < list[j].ID })
+	return list, nil
+}
+
+func (r *CustomIndexFileRepo) Get(id string) (*domain.CustomIndex, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	idx, ok := stored[id]
+	if !ok {
+		return nil, domain.ErrIndexNotFound
+	}
+	index := idx.index()
+	return &index, nil
+}
+
+// Create escolhe o id e grava sob o mesmo lock, para que criações
+// concorrentes com o mesmo nome não recebam o mesmo id
+func (r *CustomIndexFileRepo) Create(index domain.CustomIndex) (string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return "", err
+	}
+
+	base := index.ID
+	for n := 2; ; n++ {
+		if _, ok := stored[index.ID]; !ok {
+			break
+		}
+		index.ID = fmt.Sprintf("%s-%d", base, n)
+	}
+	stored[index.ID] = storedCustomIndex{Owner: index.Owner, CustomIndex: index}
+	if err := writeJSON(r.path, stored); err != nil {
+		return "", err
+	}
+	return index.ID, nil
+}
+
+func (r *CustomIndexFileRepo) Save(index domain.CustomIndex) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return err
+	}
+	if existing, ok := stored[index.ID]; ok && existing.Owner != index.Owner {
+		return domain.ErrIndexNotFound
+	}
+	stored[index.ID] = storedCustomIndex{Owner: index.Owner, CustomIndex: index}
+	return writeJSON(r.path, stored)
+}
+
+func (r *CustomIndexFileRepo) Delete(owner, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return err
+	}
+	if idx, ok := stored[id]; !ok || idx.Owner != owner {
+		return domain.ErrIndexNotFound
+	}
+	delete(stored, id)
+	return writeJSON(r.path, stored)
+}
+
+func (r *CustomIndexFileRepo) load() (map[string]storedCustomIndex, error) {
+	stored := make(map[string]storedCustomIndex)
+	if err := readJSON(r.path, &stored); err != nil {
+		return nil, err
+	}
+	for id, idx := range stored {
+		if idx.Owner == "" {
+			idx.Owner = legacyIndexOwner
+			stored[id] = idx
+		}
+	}
+	return stored, nil
+}
+
+func (s storedCustomIndex) index() domain.CustomIndex {
+	index := s.CustomIndex
+	index.Owner = s.Owner
+	return index
+}
@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"cotacoes/internal/domain"
+)
+
+func TestCustomIndexCreateUniqueIDs(t *testing.T) {
+	repo := NewCustomIndexFileRepo(filepath.Join(t.TempDir(), "indexes.json"))
+
+	const n = 10
+	var wg sync.WaitGroup
+	ids := make([]string, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			id, err := repo.Create(domain.CustomIndex{ID: "bancoes", Owner: "u1", Name: "Bancões"})
+			if err != nil {
+				t.Error(err)
+			}
+			ids[i] = id
+		}(i)
+	}
+	wg.Wait()
+
+	seen := make(map[string]bool, n)
+	for _, id := range ids {
+		if seen[id] {
+			t.Errorf("id %q repetido: %v", id, ids)
+		}
+		seen[id] = true
+	}
+	if !seen["bancoes"] || !seen["bancoes-10"] {
+		t.Errorf("ids = %v, esperado bancoes a bancoes-10", ids)
+	}
+
+	list, err := repo.List("u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != n {
+		t.Errorf("%d índices gravados, esperado %d", len(list), n)
+	}
+}
@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"cotacoes/internal/domain"
+)
+
+// PortfolioFileRepo guarda as carteiras de todos os usuários, com seus
+// lançamentos, em um arquivo JSON indexado por id
+type PortfolioFileRepo struct {
+	path string
+	mu   sync.Mutex
+}
+
+// storedPortfolio inclui o dono e os lançamentos, que não são expostos
+// junto da carteira na API
+type storedPortfolio struct {
+	Owner string `json:"owner"`
+	domain.Portfolio
+	Transactions []domain.Transaction `json:"transactions"`
+}
+
+func NewPortfolioFileRepo(path string) *PortfolioFileRepo {
+	return &PortfolioFileRepo{path: path}
+}
+
+func (r *PortfolioFileRepo) List(owner string) ([]domain.Portfolio, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]domain.Portfolio, 0)
+	for _, p := range stored {
+		if p.Owner == owner {
+			list = append(list, p.portfolio())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+func (r *PortfolioFileRepo) Get(owner, id string) (*domain.Portfolio, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	p, ok := stored[id]
+	if !ok || p.Owner != owner {
+		return nil, domain.ErrPortfolioNotFound
+	}
+	portfolio := p.portfolio()
+	return &portfolio, nil
+}
+
+func (r *PortfolioFileRepo) Save(portfolio domain.Portfolio) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return err
+	}
+	if existing, ok := stored[portfolio.ID]; ok && existing.Owner != portfolio.Owner {
+		return domain.ErrPortfolioNotFound
+	}
+	stored[portfolio.ID] = storedPortfolio{
+		Owner:        portfolio.Owner,
+		Portfolio:    portfolio,
+		Transactions: portfolio.Transactions,
+	}
+	return writeJSON(r.path, stored)
+}
+
+// Update lê a carteira, aplica fn e grava o resultado sem liberar o lock,
+// para que alterações concorrentes não se sobreponham. Se fn retornar
+// erro, nada é gravado.
+func (r *PortfolioFileRepo) Update(owner, id string, fn func(*domain.Portfolio) error) (*domain.Portfolio, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	p, ok := stored[id]
+	if !ok || p.Owner != owner {
+		return nil, domain.ErrPortfolioNotFound
+	}
+	portfolio := p.portfolio()
+	if err := fn(&portfolio); err != nil {
+		return nil, err
+	}
+
+	portfolio.ID, portfolio.Owner = id, owner
+	stored[id] = storedPortfolio{
+		Owner:        owner,
+		Portfolio:    portfolio,
+		Transactions: portfolio.Transactions,
+	}
+	if err := writeJSON(r.path, stored); err != nil {
+		return nil, err
+	}
+	return &portfolio, nil
+}
+
+func (r *PortfolioFileRepo) Delete(owner, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return err
+	}
+	if p, ok := stored[id]; !ok || p.Owner != owner {
+		return domain.ErrPortfolioNotFound
+	}
+	delete(stored, id)
+	return writeJSON(r.path, stored)
+}
+
+func (r *PortfolioFileRepo) load() (map[string]storedPortfolio, error) {
+	stored := make(map[string]storedPortfolio)
+	if err := readJSON(r.path, &stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (p storedPortfolio) portfolio() domain.Portfolio {
+	portfolio := p.Portfolio
+	portfolio.Owner = p.Owner
+	portfolio.Transactions = p.Transactions
+	if portfolio.Transactions == nil {
+		portfolio.Transactions = []domain.Transaction{}
+	}
+	return portfolio
+}
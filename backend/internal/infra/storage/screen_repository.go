@@ -0,0 +1,85 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"cotacoes/internal/domain"
+)
+
+// ScreenFileRepo guarda os screens salvos em um único arquivo JSON
+type ScreenFileRepo struct {
+	path string
+	mu   sync.Mutex
+}
+
+func NewScreenFileRepo(path string) *ScreenFileRepo {
+	return &ScreenFileRepo{path: path}
+}
+
+func (r *ScreenFileRepo) List() ([]domain.SavedScreen, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	screens, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]domain.SavedScreen, 0, len(screens))
+	for _, s := range screens {
+		list = append(list, s)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list, nil
+}
+
+func (r *ScreenFileRepo) Get(name string) (*domain.SavedScreen, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	screens, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	screen, ok := screens[name]
+	if !ok {
+		return nil, domain.ErrScreenNotFound
+	}
+	return &screen, nil
+}
+
+func (r *ScreenFileRepo) Save(screen domain.SavedScreen) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	screens, err := r.load()
+	if err != nil {
+		return err
+	}
+	screens[screen.Name] = screen
+	return writeJSON(r.path, screens)
+}
+
+func (r *ScreenFileRepo) Delete(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	screens, err := r.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := screens[name]; !ok {
+		return domain.ErrScreenNotFound
+	}
+	delete(screens, name)
+	return writeJSON(r.path, screens)
+}
+
+func (r *ScreenFileRepo) load() (map[string]domain.SavedScreen, error) {
+	screens := make(map[string]domain.SavedScreen)
+	if err := readJSON(r.path, &screens); err != nil {
+		return nil, err
+	}
+	return screens, nil
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"sort"
+	"sync"
+
+	"cotacoes/internal/domain"
+)
+
+// WatchlistFileRepo guarda as watchlists de todos os usuários em um
+// arquivo JSON, indexadas por usuário e id
+type WatchlistFileRepo struct {
+	path string
+	mu   sync.Mutex
+}
+
+// storedWatchlist inclui o dono, que não é exposto na API
+type storedWatchlist struct {
+	Owner string `json:"owner"`
+	domain.Watchlist
+}
+
+func NewWatchlistFileRepo(path string) *WatchlistFileRepo {
+	return &WatchlistFileRepo{path: path}
+}
+
+func (r *WatchlistFileRepo) List(owner string) ([]domain.Watchlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]domain.Watchlist, 0)
+	for _, w := range stored {
+		if w.Owner == owner {
+			list = append(list, w.watchlist())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if !list[i].CreatedAt.Equal(list[j].CreatedAt) {
+			return list[i].CreatedAt.Before(list[j].CreatedAt)
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list, nil
+}
+
+func (r *WatchlistFileRepo) Get(owner, id string) (*domain.Watchlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	w, ok := stored[id]
+	if !ok || w.Owner != owner {
+		return nil, domain.ErrWatchlistNotFound
+	}
+	watchlist := w.watchlist()
+	return &watchlist, nil
+}
+
+func (r *WatchlistFileRepo) Save(watchlist domain.Watchlist) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return err
+	}
+	if existing, ok := stored[watchlist.ID]; ok && existing.Owner != watchlist.Owner {
+		return domain.ErrWatchlistNotFound
+	}
+	stored[watchlist.ID] = storedWatchlist{Owner: watchlist.Owner, Watchlist: watchlist}
+	return writeJSON(r.path, stored)
+}
+
+// Update lê a watchlist, aplica fn e grava o resultado sem liberar o
+// lock, para que alterações concorrentes não se sobreponham. Se fn
+// retornar erro, nada é gravado.
+func (r *WatchlistFileRepo) Update(owner, id string, fn func(*domain.Watchlist) error) (*domain.Watchlist, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return nil, err
+	}
+	w, ok := stored[id]
+	if !ok || w.Owner != owner {
+		return nil, domain.ErrWatchlistNotFound
+	}
+	watchlist := w.watchlist()
+	if err := fn(&watchlist); err != nil {
+		return nil, err
+	}
+
+	watchlist.ID, watchlist.Owner = id, owner
+	stored[id] = storedWatchlist{Owner: owner, Watchlist: watchlist}
+	if err := writeJSON(r.path, stored); err != nil {
+		return nil, err
+	}
+	return &watchlist, nil
+}
+
+func (r *WatchlistFileRepo) Delete(owner, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.load()
+	if err != nil {
+		return err
+	}
+	if w, ok := stored[id]; !ok || w.Owner != owner {
+		return domain.ErrWatchlistNotFound
+	}
+	delete(stored, id)
+	return writeJSON(r.path, stored)
+}
+
+func (r *WatchlistFileRepo) load() (map[string]storedWatchlist, error) {
+	stored := make(map[string]storedWatchlist)
+	if err := readJSON(r.path, &stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+func (w storedWatchlist) watchlist() domain.Watchlist {
+	watchlist := w.Watchlist
+	watchlist.Owner = w.Owner
+	return watchlist
+}
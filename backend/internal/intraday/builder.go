@@ -0,0 +1,189 @@
+package intraday
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// DefaultIntervals são os intervalos gerados pelo worker
+var DefaultIntervals = []Interval{
+	{Name: "1m", Duration: time.Minute},
+	{Name: "5m", Duration: 5 * time.Minute},
+	{Name: "15m", Duration: 15 * time.Minute},
+	{Name: "60m", Duration: time.Hour},
+}
+
+// Interval é um tamanho de candle
+type Interval struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Bar é um candle fechado pronto para ser persistido
+type Bar struct {
+	Symbol   string
+	Interval string
+	Candle   domain.HistoricalDataPrice
+}
+
+type openBar struct {
+	start  time.Time
+	candle domain.HistoricalDataPrice
+}
+
+type symbolState struct {
+	session    time.Time // dia do pregão (meia-noite)
+	lastVolume int64     // volume acumulado do dia na última leitura
+	seen       bool      // já houve leitura neste pregão
+	bars       map[string]*openBar
+}
+
+// Builder agrega snapshots sucessivos da listagem (close e volume
+// acumulado do dia) em candles OHLCV por símbolo e intervalo.
+// Os candles são ancorados na abertura do pregão.
+type Builder struct {
+	calendar  *calendar.B3
+	intervals []Interval
+
+	mu      sync.Mutex
+	symbols map[string]*symbolState
+}
+
+func NewBuilder(cal *calendar.B3, intervals []Interval) *Builder {
+	return &Builder{
+		calendar:  cal,
+		intervals: intervals,
+		symbols:   make(map[string]*symbolState),
+	}
+}
+
+// Ingest processa um snapshot da listagem tirado em "at" e devolve os
+// candles que fecharam. Snapshots fora do pregão apenas fecham os candles
+// em aberto.
+func (b *Builder) Ingest(at time.Time, items []domain.StockListItem) []Bar {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	at = at.In(calendar.Location)
+	closed := b.closeDue(at)
+
+	if !b.calendar.InSession(at) {
+		return closed
+	}
+
+	session := calendar.StartOfDay(at)
+	open, _ := b.calendar.SessionBounds(at)
+
+	for _, item := range items {
+		if item.Stock == "" || item.Close <= 0 {
+			continue
+		}
+
+		st, ok := b.symbols[item.Stock]
+		if !ok || !st.session.Equal(session) {
+			// Novo pregão: o volume acumulado recomeça do zero
+			st = &symbolState{session: session, bars: make(map[string]*openBar)}
+			b.symbols[item.Stock] = st
+		}
+
+		delta := item.Volume - st.lastVolume
+		if delta < 0 {
+			// O provider zerou/corrigiu o acumulado; considera o valor atual
+			delta = item.Volume
+		}
+		if !st.seen {
+			// Primeira leitura do dia: o acumulado inclui negócios de antes
+			// do worker começar, que não pertencem a este candle
+			delta = 0
+		}
+		st.lastVolume = item.Volume
+		st.seen = true
+
+		for _, iv := range b.intervals {
+			start := open.Add(at.Sub(open).Truncate(iv.Duration))
+			bar, ok := st.bars[iv.Name]
+			if !ok || !bar.start.Equal(start) {
+				st.bars[iv.Name] = &openBar{
+					start: start,
+					candle: domain.HistoricalDataPrice{
+						Date:          start.Unix(),
+						Open:          item.Close,
+						High:          item.Close,
+						Low:           item.Close,
+						Close:         item.Close,
+						Volume:        delta,
+						AdjustedClose: item.Close,
+					},
+				}
+				continue
+			}
+
+			c := &bar.candle
+			c.High = max(c.High, item.Close)
+			c.Low = min(c.Low, item.Close)
+			c.Close = item.Close
+			c.AdjustedClose = item.Close
+			c.Volume += delta
+		}
+	}
+
+	return closed
+}
+
+// Flush devolve os candles cujo período terminou até "at" e descarta os
+// que ainda estão em formação (ex: ao encerrar o worker). Um candle
+// incompleto gravado no store de escrita única nunca seria corrigido.
+func (b *Builder) Flush(at time.Time) []Bar {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bars := b.closeDue(at.In(calendar.Location))
+	for _, st := range b.symbols {
+		clear(st.bars)
+	}
+	return bars
+}
+
+// closeDue fecha os candles cujo período terminou antes de "at" ou cujo
+// pregão já acabou
+func (b *Builder) closeDue(at time.Time) []Bar {
+	bars := make([]Bar, 0)
+	durations := make(map[string]time.Duration, len(b.intervals))
+	for _, iv := range b.intervals {
+		durations[iv.Name] = iv.Duration
+	}
+
+	for symbol, st := range b.symbols {
+		_, sessionClose := b.calendar.SessionBounds(st.session)
+		for name, bar := range st.bars {
+			end := bar.start.Add(durations[name])
+			if end.After(sessionClose) {
+				end = sessionClose
+			}
+			if at.Before(end) {
+				continue
+			}
+			bars = append(bars, Bar{Symbol: symbol, Interval: name, Candle: bar.candle})
+			delete(st.bars, name)
+		}
+	}
+
+	sortBars(bars)
+	return bars
+}
+
+func sortBars(bars []Bar) {
+	sort.Slice(bars, func(i, j int) bool {
+		if bars[i].Symbol != bars[j].Symbol {
+			return bars[i].Symbol < bars[j].Symbol
+		}
+		if bars[i].Interval != bars[j].Interval {
+			return bars[i].Interval < bars[j].Interval
+		}
+		return bars[i].Candle.Date < bars[j].Candle.Date
+	})
+}
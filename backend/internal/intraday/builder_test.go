@@ -0,0 +1,36 @@
+package intraday
+
+import (
+	"testing"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+func TestFlushDropsOpenBars(t *testing.T) {
+	at := func(h, m, s int) time.Time {
+		return time.Date(2024, 3, 4, h, m, s, 0, calendar.Location)
+	}
+	items := func(close float64, volume int64) []domain.StockListItem {
+		return []domain.StockListItem{{Stock: "PETR4", Close: close, Volume: volume}}
+	}
+
+	b := NewBuilder(calendar.Default, DefaultIntervals)
+	b.Ingest(at(10, 0, 30), items(38, 1000))
+	b.Ingest(at(10, 1, 10), items(39, 1500))
+
+	// O candle de 1m das 10:01 terminou; os de 5m, 15m e 60m não
+	bars := b.Flush(at(10, 2, 5))
+	if len(bars) != 1 {
+		t.Fatalf("%d candles, esperado 1: %+v", len(bars), bars)
+	}
+	got := bars[0]
+	if got.Interval != "1m" || got.Candle.Date != at(10, 1, 0).Unix() || got.Candle.Close != 39 || got.Candle.Volume != 500 {
+		t.Errorf("candle = %+v, esperado o de 1m das 10:01", got)
+	}
+
+	if bars := b.Flush(at(11, 0, 0)); len(bars) != 0 {
+		t.Errorf("candles em formação devem ser descartados, recebeu %+v", bars)
+	}
+}
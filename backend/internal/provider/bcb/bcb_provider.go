@@ -0,0 +1,161 @@
+// Package bcb busca séries de taxas no Sistema Gerenciador de Séries
+// Temporais (SGS) do Banco Central: CDI diário e IPCA mensal.
+package bcb
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+const baseURL = "https://api.bcb.gov.br/dados/serie/bcdata.sgs.%d/dados"
+
+// Códigos das séries no SGS
+var seriesCodes = map[string]int{
+	domain.RateCDI:  12,
+	domain.RateIPCA: 433,
+}
+
+// maxWindow é a janela máxima aceita pelo SGS em séries diárias
+const maxWindow = 10
+
+// BCBProvider consulta o SGS e guarda as séries em memória por "ttl".
+// O cache tem uma entrada por série, com o período já buscado; pedidos
+// dentro desse período são recortados dele.
+type BCBProvider struct {
+	client *http.Client
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[int]cachedSeries
+}
+
+type cachedSeries struct {
+	from, to  time.Time
+	points    []domain.RatePoint
+	fetchedAt time.Time
+}
+
+func NewBCBProvider(ttl time.Duration) *BCBProvider {
+	return &BCBProvider{
+		client: &http.Client{Timeout: 15 * time.Second},
+		ttl:    ttl,
+		cache:  make(map[int]cachedSeries),
+	}
+}
+
+// Series retorna a série entre as datas (inclusive)
+func (p *BCBProvider) Series(name string, from, to time.Time) ([]domain.RatePoint, error) {
+	code, ok := seriesCodes[strings.ToLower(name)]
+	if !ok {
+		return nil, fmt.Errorf("série %q desconhecida", name)
+	}
+
+	from, to = calendar.StartOfDay(from), calendar.StartOfDay(to)
+	if to.Before(from) {
+		return nil, nil
+	}
+
+	// Com o cache válido, busca a união dos períodos para que a entrada
+	// continue cobrindo os pedidos anteriores
+	fetchFrom, fetchTo := from, to
+	p.mu.Lock()
+	if c, ok := p.cache[code]; ok && time.Since(c.fetchedAt) < p.ttl {
+		if !from.Before(c.from) && !to.After(c.to) {
+			p.mu.Unlock()
+			return slicePoints(c.points, from, to), nil
+		}
+		if c.from.Before(fetchFrom) {
+			fetchFrom = c.from
+		}
+		if c.to.After(fetchTo) {
+			fetchTo = c.to
+		}
+	}
+	p.mu.Unlock()
+
+	var points []domain.RatePoint
+	for start := fetchFrom; !start.After(fetchTo); {
+		end := start.AddDate(maxWindow, 0, -1)
+		if end.After(fetchTo) {
+			end = fetchTo
+		}
+		chunk, err := p.fetch(code, start, end)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, chunk...)
+		start = end.AddDate(0, 0, 1)
+	}
+
+	p.mu.Lock()
+	p.cache[code] = cachedSeries{from: fetchFrom, to: fetchTo, points: points, fetchedAt: time.Now()}
+	p.mu.Unlock()
+
+	log.Printf("🏦 BCB série %d: %d pontos", code, len(points))
+	return slicePoints(points, from, to), nil
+}
+
+// slicePoints devolve os pontos entre as datas (inclusive)
+func slicePoints(points []domain.RatePoint, from, to time.Time) []domain.RatePoint {
+	out := make([]domain.RatePoint, 0, len(points))
+	for _, pt := range points {
+		if !pt.Date.Before(from) && !pt.Date.After(to) {
+			out = append(out, pt)
+		}
+	}
+	return out
+}
+
+func (p *BCBProvider) fetch(code int, from, to time.Time) ([]domain.RatePoint, error) {
+	params := url.Values{}
+	params.Add("formato", "json")
+	params.Add("dataInicial", from.Format("02/01/2006"))
+	params.Add("dataFinal", to.Format("02/01/2006"))
+	requestURL := fmt.Sprintf(baseURL, code) + "?" + params.Encode()
+
+	resp, err := p.client.Get(requestURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// O SGS responde 404 quando não há dados no período
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("bcb error: %s", resp.Status)
+	}
+
+	var raw []struct {
+		Date  string `json:"data"`
+		Value string `json:"valor"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return nil, fmt.Errorf("bcb: resposta inválida: %w", err)
+	}
+
+	points := make([]domain.RatePoint, 0, len(raw))
+	for _, r := range raw {
+		date, err := time.ParseInLocation("02/01/2006", r.Date, calendar.Location)
+		if err != nil {
+			continue
+		}
+		v, err := strconv.ParseFloat(strings.TrimSpace(r.Value), 64)
+		if err != nil {
+			continue
+		}
+		points = append(points, domain.RatePoint{Date: date, Value: v})
+	}
+	return points, nil
+}
@@ -268,11 +268,14 @@ func (p *BrapiProvider) ListAllStocks(
 // ROTA /stocks/:ticker
 // =====================
 
+// Módulos de fundamentos pedidos no detalhe da ação
+const detailModules = "summaryProfile,balanceSheetHistory,financialData,defaultKeyStatistics"
+
 // GetBySymbol busca dados completos de uma ação pelo símbolo
 func (p *BrapiProvider) GetBySymbol(symbol, rangeParam, intervalParam string) (*domain.Stock, error) {
 	url := fmt.Sprintf(
-		"https://brapi.dev/api/quote/%s?token=%s&fundamental=true&dividends=true&range=%s&interval=%s",
-		symbol, p.APIKey, rangeParam, intervalParam,
+		"https://brapi.dev/api/quote/%s?token=%s&fundamental=true&dividends=true&range=%s&interval=%s&modules=%s",
+		symbol, p.APIKey, rangeParam, intervalParam, detailModules,
 	)
 
 	resp, err := http.Get(url)
@@ -281,6 +284,14 @@ func (p *BrapiProvider) GetBySymbol(symbol, rangeParam, intervalParam string) (*
 	}
 	defer resp.Body.Close()
 
+	// 404 é ação inexistente; demais status de erro indicam falha do provider
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, domain.ErrStockNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("brapi error: %s", resp.Status)
+	}
+
 	var result struct {
 		Results []struct {
 			Symbol                     string                       `json:"symbol"`
@@ -307,6 +318,12 @@ func (p *BrapiProvider) GetBySymbol(symbol, rangeParam, intervalParam string) (*
 			EarningsPerShare           float64                      `json:"earningsPerShare"`
 			DividendsData              domain.DividendsData         `json:"dividendsData"`
 			HistoricalDataPrice        []domain.HistoricalDataPrice `json:"historicalDataPrice"` // Date como string
+			// Módulos decodificados à parte: um campo com formato inesperado
+			// não deve derrubar o detalhe inteiro
+			BalanceSheetHistory  json.RawMessage `json:"balanceSheetHistory"`
+			SummaryProfile       json.RawMessage `json:"summaryProfile"`
+			FinancialData        json.RawMessage `json:"financialData"`
+			DefaultKeyStatistics json.RawMessage `json:"defaultKeyStatistics"`
 		} `json:"results"`
 	}
 
@@ -320,6 +337,18 @@ func (p *BrapiProvider) GetBySymbol(symbol, rangeParam, intervalParam string) (*
 
 	r := result.Results[0]
 
+	// Módulos podem não vir (plano da API ou ativo sem fundamentos)
+	balanceSheets := []domain.BalanceSheet{}
+	decodeModule(symbol, "balanceSheetHistory", r.BalanceSheetHistory, &balanceSheets)
+	summaryProfile := domain.SummaryProfile{}
+	decodeModule(symbol, "summaryProfile", r.SummaryProfile, &summaryProfile)
+	financialData := domain.FinancialData{}
+	decodeModule(symbol, "financialData", r.FinancialData, &financialData)
+	var keyStatistics struct {
+		SharesOutstanding float64 `json:"sharesOutstanding"`
+	}
+	decodeModule(symbol, "defaultKeyStatistics", r.DefaultKeyStatistics, &keyStatistics)
+
 	// --- Preencher o Stock ---
 	return &domain.Stock{
 		Symbol:                     r.Symbol,
@@ -348,11 +377,25 @@ func (p *BrapiProvider) GetBySymbol(symbol, rangeParam, intervalParam string) (*
 		DividendsData:              r.DividendsData,
 		HistoricalDataPrice:        r.HistoricalDataPrice,
 
+		// Fundamentos
+		BalanceSheetHistory: balanceSheets,
+		SummaryProfile:      summaryProfile,
+		FinancialData:       financialData,
+		SharesOutstanding:   int64(keyStatistics.SharesOutstanding),
+
 		// Campos adicionais
-		BalanceSheetHistory:   []domain.BalanceSheet{},
-		SummaryProfile:        domain.SummaryProfile{},
-		FinancialData:         domain.FinancialData{},
 		RegularMarketTime:     time.Now(),
 		RegularMarketDayRange: fmt.Sprintf("%.2f - %.2f", r.RegularMarketDayLow, r.RegularMarketDayHigh),
 	}, nil
 }
+
+// decodeModule decodifica um módulo opcional da brapi; em caso de erro
+// registra no log e mantém o valor vazio
+func decodeModule(symbol, module string, raw json.RawMessage, target any) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		log.Printf("⚠️ Módulo %s de %s com formato inesperado: %v", module, symbol, err)
+	}
+}
@@ -0,0 +1,208 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// ChainConfig define quando um provider é rebaixado na cadeia
+type ChainConfig struct {
+	// Falhas consecutivas para rebaixar o provider para o fim da cadeia
+	DemoteAfter int
+	// Tempo rebaixado até o provider voltar à posição original para uma
+	// nova tentativa. Sem isso, um fallback que sempre responde (ex:
+	// snapshot) impediria o provider de ser chamado de novo.
+	RetryAfter time.Duration
+}
+
+type chainMember struct {
+	name     string
+	position int // posição configurada
+	impl     any
+
+	failures  int
+	demoted   bool
+	demotedAt time.Time
+	probing   bool // de volta à posição após RetryAfter, em teste
+}
+
+// chain mantém a ordem efetiva: membros saudáveis na ordem configurada,
+// seguidos pelos rebaixados. Um provider cujo breaker está aberto também
+// é tratado como rebaixado.
+type chain struct {
+	name string
+	cfg  ChainConfig
+
+	mu      sync.Mutex
+	members []*chainMember
+	now     func() time.Time
+}
+
+func newChain(name string, members []*chainMember, cfg ChainConfig) *chain {
+	if cfg.DemoteAfter <= 0 {
+		cfg.DemoteAfter = 3
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = time.Minute
+	}
+	return &chain{name: name, cfg: cfg, members: members, now: time.Now}
+}
+
+// ordered devolve a ordem para uma chamada. Passado RetryAfter, o
+// rebaixado volta à posição original em teste: uma única falha o rebaixa
+// de novo.
+func (c *chain) ordered() []*chainMember {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	for _, m := range c.members {
+		if m.demoted && now.Sub(m.demotedAt) >= c.cfg.RetryAfter {
+			m.demoted = false
+			m.probing = true
+			log.Printf("🔁 Provider %s volta a ser tentado na cadeia %s", m.name, c.name)
+		}
+	}
+	ordered, _ := c.sorted()
+	return ordered
+}
+
+// sorted devolve os membros saudáveis na ordem configurada, seguidos pelos
+// rebaixados, sem alterar o estado da cadeia. Deve ser chamado com c.mu.
+func (c *chain) sorted() ([]*chainMember, map[*chainMember]bool) {
+	ordered := make([]*chainMember, len(c.members))
+	copy(ordered, c.members)
+
+	demoted := make(map[*chainMember]bool, len(ordered))
+	for _, m := range ordered {
+		demoted[m] = m.demoted || breakerOpen(m.impl)
+	}
+
+	sort.SliceStable(ordered, func(i, j int) bool {
+		if demoted[ordered[i]] != demoted[ordered[j]] {
+			return !demoted[ordered[i]]
+		}
+		return ordered[i].position < ordered[j].position
+	})
+	return ordered, demoted
+}
+
+func (c *chain) report(m *chainMember, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err == nil || errors.Is(err, domain.ErrStockNotFound) {
+		if m.demoted || m.probing {
+			log.Printf("🔼 Provider %s voltou à posição original na cadeia %s", m.name, c.name)
+		}
+		m.failures = 0
+		m.demoted = false
+		m.probing = false
+		return
+	}
+
+	m.failures++
+	if !m.demoted && (m.probing || m.failures >= c.cfg.DemoteAfter) {
+		m.demoted = true
+		m.probing = false
+		m.demotedAt = c.now()
+		log.Printf("🔽 Provider %s rebaixado na cadeia %s após %d falhas", m.name, c.name, m.failures)
+	}
+}
+
+// status descreve a cadeia para o /health sem alterar o estado: um
+// rebaixado continua listado como tal até a próxima chamada testá-lo
+func (c *chain) status() domain.ChainStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	ordered, demoted := c.sorted()
+	st := domain.ChainStatus{
+		Name:    c.name,
+		Order:   make([]string, 0, len(ordered)),
+		Demoted: []string{},
+	}
+	for _, m := range ordered {
+		st.Order = append(st.Order, m.name)
+		if demoted[m] {
+			st.Demoted = append(st.Demoted, m.name)
+		}
+	}
+	return st
+}
+
+func breakerOpen(impl any) bool {
+	reporter, ok := impl.(domain.HealthReporter)
+	if !ok {
+		return false
+	}
+	return reporter.Health().State == "open"
+}
+
+// ProviderChain implementa domain.StockProvider tentando cada provider em ordem
+type ProviderChain struct {
+	*chain
+}
+
+func (pc *ProviderChain) ListAllStocks(
+	sector, stockType, sortBy, sortOrder string,
+	page, perPage int,
+) (*domain.AllStocksResponse, error) {
+
+	var errs []error
+	for _, m := range pc.ordered() {
+		resp, err := m.impl.(domain.StockProvider).ListAllStocks(sector, stockType, sortBy, sortOrder, page, perPage)
+		pc.report(m, err)
+		if err == nil {
+			return resp, nil
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", m.name, err))
+	}
+
+	return nil, errors.Join(errs...)
+}
+
+// ChainStatus expõe a ordem efetiva para o /health
+func (pc *ProviderChain) ChainStatus() domain.ChainStatus {
+	return pc.status()
+}
+
+// RepositoryChain implementa domain.StockRepository tentando cada repositório em ordem
+type RepositoryChain struct {
+	*chain
+}
+
+func (rc *RepositoryChain) GetBySymbol(symbol, rangeParam, intervalParam string) (*domain.Stock, error) {
+	var errs []error
+	notFound := 0
+
+	members := rc.ordered()
+	for _, m := range members {
+		stock, err := m.impl.(domain.StockRepository).GetBySymbol(symbol, rangeParam, intervalParam)
+		rc.report(m, err)
+		if err == nil {
+			return stock, nil
+		}
+		if errors.Is(err, domain.ErrStockNotFound) {
+			notFound++
+		}
+		errs = append(errs, fmt.Errorf("%s: %w", m.name, err))
+	}
+
+	// Todos responderam que a ação não existe
+	if notFound == len(members) {
+		return nil, domain.ErrStockNotFound
+	}
+	return nil, errors.Join(errs...)
+}
+
+// ChainStatus expõe a ordem efetiva para o /health
+func (rc *RepositoryChain) ChainStatus() domain.ChainStatus {
+	return rc.status()
+}
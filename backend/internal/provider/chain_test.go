@@ -0,0 +1,108 @@
+package provider
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// fakeProvider responde com err (nil = sucesso) e conta as chamadas
+type fakeProvider struct {
+	err   error
+	calls int
+}
+
+func (f *fakeProvider) ListAllStocks(sector, stockType, sortBy, sortOrder string, page, perPage int) (*domain.AllStocksResponse, error) {
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &domain.AllStocksResponse{}, nil
+}
+
+func newTestChain(t *testing.T, cfg ChainConfig, primary, fallback *fakeProvider) (*ProviderChain, *time.Time) {
+	t.Helper()
+
+	r := NewRegistry()
+	r.RegisterStockProvider("brapi", primary)
+	r.RegisterStockProvider("snapshot", fallback)
+	pc, err := r.ProviderChain([]string{"brapi", "snapshot"}, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	pc.now = func() time.Time { return now }
+	return pc, &now
+}
+
+func list(pc *ProviderChain) error {
+	_, err := pc.ListAllStocks("", "", "", "", 1, 10)
+	return err
+}
+
+func TestChainDemotesAfterFailures(t *testing.T) {
+	primary := &fakeProvider{err: errors.New("timeout")}
+	fallback := &fakeProvider{}
+	pc, _ := newTestChain(t, ChainConfig{DemoteAfter: 3, RetryAfter: time.Minute}, primary, fallback)
+
+	for i := 0; i < 5; i++ {
+		if err := list(pc); err != nil {
+			t.Fatalf("chamada %d: %v", i, err)
+		}
+	}
+	if primary.calls != 3 {
+		t.Errorf("brapi chamado %d vezes, esperado 3 (rebaixado após 3 falhas)", primary.calls)
+	}
+	if got := pc.ChainStatus().Order[0]; got != "snapshot" {
+		t.Errorf("primeiro da cadeia = %s, esperado snapshot", got)
+	}
+}
+
+func TestChainRetriesDemotedProvider(t *testing.T) {
+	primary := &fakeProvider{err: errors.New("timeout")}
+	fallback := &fakeProvider{}
+	pc, now := newTestChain(t, ChainConfig{DemoteAfter: 3, RetryAfter: time.Minute}, primary, fallback)
+
+	for i := 0; i < 3; i++ {
+		list(pc)
+	}
+
+	// Consultar o status não tira o provider da condição de rebaixado
+	*now = now.Add(2 * time.Minute)
+	for i := 0; i < 2; i++ {
+		if st := pc.ChainStatus(); st.Order[0] != "snapshot" || len(st.Demoted) != 1 {
+			t.Fatalf("status = %v, rebaixados %v; esperado brapi rebaixado", st.Order, st.Demoted)
+		}
+	}
+	*now = now.Add(-2 * time.Minute)
+
+	// Antes de RetryAfter continua rebaixado
+	*now = now.Add(30 * time.Second)
+	list(pc)
+	if primary.calls != 3 {
+		t.Fatalf("brapi chamado antes de RetryAfter (%d chamadas)", primary.calls)
+	}
+
+	// Ainda falhando: uma tentativa e volta a ser rebaixado
+	*now = now.Add(time.Minute)
+	list(pc)
+	list(pc)
+	if primary.calls != 4 {
+		t.Fatalf("brapi chamado %d vezes, esperado 4 (uma tentativa)", primary.calls)
+	}
+
+	// Recuperado: volta à posição original
+	primary.err = nil
+	*now = now.Add(time.Minute)
+	list(pc)
+	list(pc)
+	if primary.calls != 6 {
+		t.Errorf("brapi chamado %d vezes, esperado 6", primary.calls)
+	}
+	st := pc.ChainStatus()
+	if st.Order[0] != "brapi" || len(st.Demoted) != 0 {
+		t.Errorf("cadeia = %v, rebaixados %v; esperado brapi primeiro e nenhum rebaixado", st.Order, st.Demoted)
+	}
+}
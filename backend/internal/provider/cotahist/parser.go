@@ -0,0 +1,180 @@
+package cotahist
+
+import (
+	"archive/zip"
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"cotacoes/internal/calendar"
+)
+
+// Variant identifica a periodicidade do arquivo publicado pela B3
+type Variant string
+
+const (
+	VariantDaily   Variant = "daily"   // COTAHIST_DddMMyyyy
+	VariantMonthly Variant = "monthly" // COTAHIST_MMMyyyy
+	VariantAnnual  Variant = "annual"  // COTAHIST_Ayyyy
+	VariantUnknown Variant = "unknown"
+)
+
+var (
+	dailyName   = regexp.MustCompile(`(?i)COTAHIST_D(\d{2})(\d{2})(\d{4})`)
+	monthlyName = regexp.MustCompile(`(?i)COTAHIST_M(\d{2})(\d{4})`)
+	annualName  = regexp.MustCompile(`(?i)COTAHIST_A(\d{4})`)
+)
+
+// DetectVariant identifica a variante e o período coberto pelo nome do arquivo
+func DetectVariant(filename string) (Variant, time.Time, time.Time) {
+	base := filepath.Base(filename)
+
+	if m := dailyName.FindStringSubmatch(base); m != nil {
+		day, err := time.ParseInLocation("02012006", m[1]+m[2]+m[3], calendar.Location)
+		if err == nil {
+			return VariantDaily, day, day
+		}
+	}
+	if m := monthlyName.FindStringSubmatch(base); m != nil {
+		month, err := time.ParseInLocation("012006", m[1]+m[2], calendar.Location)
+		if err == nil {
+			return VariantMonthly, month, month.AddDate(0, 1, -1)
+		}
+	}
+	if m := annualName.FindStringSubmatch(base); m != nil {
+		year, err := time.ParseInLocation("2006", m[1], calendar.Location)
+		if err == nil {
+			return VariantAnnual, year, year.AddDate(1, 0, -1)
+		}
+	}
+
+	return VariantUnknown, time.Time{}, time.Time{}
+}
+
+// Filter seleciona quais registros serão importados
+type Filter struct {
+	Markets  map[string]bool // TPMERC aceitos (vazio = todos)
+	BDICodes map[string]bool // CODBDI aceitos (vazio = todos)
+	Symbols  map[string]bool // tickers aceitos (vazio = todos)
+}
+
+// DefaultFilter importa o mercado à vista em lote padrão, FIIs e ETFs
+func DefaultFilter() Filter {
+	return Filter{
+		Markets:  map[string]bool{MarketSpot: true},
+		BDICodes: map[string]bool{BDIStandardLot: true, BDIRealEstate: true, BDIETF: true},
+	}
+}
+
+func (f Filter) accept(r Record) bool {
+	if len(f.Markets) > 0 && !f.Markets[r.MarketCode] {
+		return false
+	}
+	if len(f.BDICodes) > 0 && !f.BDICodes[r.BDICode] {
+		return false
+	}
+	if len(f.Symbols) > 0 && !f.Symbols[r.Symbol] {
+		return false
+	}
+	return true
+}
+
+// Summary resume o resultado do parse de um arquivo
+type Summary struct {
+	File     string
+	Variant  Variant
+	Header   string
+	Lines    int
+	Accepted int
+	Skipped  int
+	Declared int // total de registros informado no trailer
+	Errors   []error
+}
+
+// ParseFile lê um arquivo COTAHIST (.TXT ou .ZIP) e chama fn para cada
+// registro aceito pelo filtro.
+func ParseFile(path string, filter Filter, fn func(Record) error) (Summary, error) {
+	variant, _, _ := DetectVariant(path)
+	summary := Summary{File: path, Variant: variant}
+
+	if strings.EqualFold(filepath.Ext(path), ".zip") {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return summary, err
+		}
+		defer zr.Close()
+
+		for _, f := range zr.File {
+			if f.FileInfo().IsDir() {
+				continue
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return summary, err
+			}
+			err = Parse(rc, filter, fn, &summary)
+			rc.Close()
+			if err != nil {
+				return summary, err
+			}
+		}
+		return summary, nil
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return summary, err
+	}
+	defer file.Close()
+
+	return summary, Parse(file, filter, fn, &summary)
+}
+
+// Parse lê registros de r acumulando estatísticas em summary.
+// Linhas malformadas são contabilizadas em summary.Errors e não
+// interrompem a importação; erros de fn interrompem.
+func Parse(r io.Reader, filter Filter, fn func(Record) error, summary *Summary) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 512), 1024)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		summary.Lines++
+
+		if len(line) < 2 {
+			continue
+		}
+
+		switch line[:2] {
+		case recordHeader:
+			summary.Header = strings.TrimSpace(line[2:min(len(line), 31)])
+		case recordTrailer:
+			if len(line) >= 42 {
+				if n, err := parseInt(field(line, 32, 42)); err == nil {
+					summary.Declared = int(n)
+				}
+			}
+		case recordQuote:
+			rec, err := parseRecord(line)
+			if err != nil {
+				summary.Errors = append(summary.Errors, fmt.Errorf("linha %d: %w", summary.Lines, err))
+				continue
+			}
+			if !filter.accept(rec) {
+				summary.Skipped++
+				continue
+			}
+			if err := fn(rec); err != nil {
+				return err
+			}
+			summary.Accepted++
+		}
+	}
+
+	return scanner.Err()
+}
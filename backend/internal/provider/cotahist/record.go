@@ -0,0 +1,146 @@
+package cotahist
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// Layout oficial do arquivo COTAHIST (registros de 245 posições)
+const recordLength = 245
+
+// Tipos de registro
+const (
+	recordHeader  = "00"
+	recordQuote   = "01"
+	recordTrailer = "99"
+)
+
+// Códigos de mercado (TPMERC) mais usados
+const (
+	MarketSpot       = "010" // Mercado à vista
+	MarketFractional = "020" // Mercado fracionário
+	MarketForward    = "030" // Termo
+	MarketCallOption = "070" // Opções de compra
+	MarketPutOption  = "080" // Opções de venda
+)
+
+// Códigos BDI mais usados
+const (
+	BDIStandardLot = "02" // Lote padrão
+	BDIRealEstate  = "12" // Fundos imobiliários
+	BDIETF         = "14" // Certificados / ETFs
+	BDIFractional  = "96" // Fracionário
+)
+
+// Record representa uma linha de cotação (TIPREG 01)
+type Record struct {
+	Date       time.Time
+	BDICode    string
+	Symbol     string
+	MarketCode string
+	ShortName  string
+	Spec       string
+	Open       float64
+	High       float64
+	Low        float64
+	Average    float64
+	Close      float64
+	Trades     int64
+	Quantity   int64
+	Volume     float64 // volume financeiro em R$
+	Factor     int64   // fator de cotação (1 ou 1000)
+	ISIN       string
+}
+
+// Candle converte o registro para o formato usado pela API.
+// Os preços do COTAHIST não são ajustados por proventos.
+func (r Record) Candle() domain.HistoricalDataPrice {
+	return domain.HistoricalDataPrice{
+		Date:          r.Date.Unix(),
+		Open:          r.Open,
+		High:          r.High,
+		Low:           r.Low,
+		Close:         r.Close,
+		Volume:        r.Quantity,
+		AdjustedClose: r.Close,
+	}
+}
+
+// parseRecord interpreta uma linha do tipo 01
+func parseRecord(line string) (Record, error) {
+	if len(line) < recordLength {
+		return Record{}, fmt.Errorf("registro com %d posições (esperado %d)", len(line), recordLength)
+	}
+
+	date, err := time.ParseInLocation("20060102", field(line, 3, 10), calendar.Location)
+	if err != nil {
+		return Record{}, fmt.Errorf("DATA inválida: %w", err)
+	}
+
+	factor, err := parseInt(field(line, 211, 217))
+	if err != nil {
+		return Record{}, fmt.Errorf("FATCOT inválido: %w", err)
+	}
+	if factor <= 0 {
+		factor = 1
+	}
+
+	prices := make([]float64, 5)
+	for i, pos := range [][2]int{{57, 69}, {70, 82}, {83, 95}, {96, 108}, {109, 121}} {
+		v, err := parseInt(field(line, pos[0], pos[1]))
+		if err != nil {
+			return Record{}, fmt.Errorf("preço inválido na posição %d: %w", pos[0], err)
+		}
+		// Preços têm 2 casas decimais implícitas e são cotados por lote de FATCOT
+		prices[i] = float64(v) / 100 / float64(factor)
+	}
+
+	trades, err := parseInt(field(line, 148, 152))
+	if err != nil {
+		return Record{}, fmt.Errorf("TOTNEG inválido: %w", err)
+	}
+	quantity, err := parseInt(field(line, 153, 170))
+	if err != nil {
+		return Record{}, fmt.Errorf("QUATOT inválido: %w", err)
+	}
+	volume, err := parseInt(field(line, 171, 188))
+	if err != nil {
+		return Record{}, fmt.Errorf("VOLTOT inválido: %w", err)
+	}
+
+	return Record{
+		Date:       date,
+		BDICode:    field(line, 11, 12),
+		Symbol:     field(line, 13, 24),
+		MarketCode: field(line, 25, 27),
+		ShortName:  field(line, 28, 39),
+		Spec:       field(line, 40, 49),
+		Open:       prices[0],
+		High:       prices[1],
+		Low:        prices[2],
+		Average:    prices[3],
+		Close:      prices[4],
+		Trades:     trades,
+		Quantity:   quantity,
+		Volume:     float64(volume) / 100,
+		Factor:     factor,
+		ISIN:       field(line, 231, 242),
+	}, nil
+}
+
+// field extrai as posições [start, end] (1-based, inclusivas) do layout da B3
+func field(line string, start, end int) string {
+	return strings.TrimSpace(line[start-1 : end])
+}
+
+func parseInt(s string) (int64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return strconv.ParseInt(s, 10, 64)
+}
@@ -0,0 +1,179 @@
+// Package indexes expõe os índices customizados como se fossem símbolos:
+// "custom:<id>" é calculado a partir dos ativos do índice e qualquer outro
+// símbolo é repassado ao repositório de ações.
+package indexes
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"cotacoes/internal/analytics/adjust"
+	"cotacoes/internal/analytics/customindex"
+	"cotacoes/internal/analytics/stats"
+	"cotacoes/internal/domain"
+)
+
+const maxConcurrentFetches = 5
+
+// StockRepo decora um domain.StockRepository com os índices customizados,
+// para que possam ser usados em qualquer lugar que aceite um símbolo (ex:
+// benchmark das estatísticas)
+type StockRepo struct {
+	inner   domain.StockRepository
+	indexes domain.CustomIndexRepository
+}
+
+func NewStockRepo(inner domain.StockRepository, indexes domain.CustomIndexRepository) *StockRepo {
+	return &StockRepo{
+		inner:   inner,
+		indexes: indexes,
+	}
+}
+
+// GetBySymbol calcula o índice customizado ou repassa ao repositório
+func (r *StockRepo) GetBySymbol(symbol, rangeParam, intervalParam string) (*domain.Stock, error) {
+	return r.GetBySymbolAdjusted(symbol, rangeParam, intervalParam, "")
+}
+
+// GetBySymbolAdjusted é o GetBySymbol com os ativos do índice ajustados
+// pelo modo antes do cálculo. Ajustar os níveis depois não teria efeito:
+// o índice não tem proventos próprios. Outros símbolos são repassados sem
+// ajuste (quem chama aplica o modo).
+func (r *StockRepo) GetBySymbolAdjusted(symbol, rangeParam, intervalParam string, mode adjust.Mode) (*domain.Stock, error) {
+	if !customindex.IsSymbol(symbol) {
+		return r.inner.GetBySymbol(symbol, rangeParam, intervalParam)
+	}
+
+	stock, _, err := r.Build(customindex.IDFromSymbol(symbol), rangeParam, intervalParam, mode)
+	if errors.Is(err, domain.ErrIndexNotFound) {
+		return nil, fmt.Errorf("%w: %s", domain.ErrStockNotFound, symbol)
+	}
+	return stock, err
+}
+
+// Build busca os ativos do índice e calcula o histórico no range pedido.
+// O histórico de cada ativo é ajustado pelo modo antes do cálculo; sem
+// modo, por desdobramentos, para que um desdobramento não apareça como
+// queda do índice.
+func (r *StockRepo) Build(id, rangeParam, intervalParam string, mode adjust.Mode) (*domain.Stock, *customindex.Result, error) {
+	if mode == "" {
+		mode = adjust.Splits
+	}
+
+	idx, err := r.indexes.Get(id)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	input, degraded, warnings := r.fetch(idx.Constituents, rangeParam, intervalParam, mode)
+	if len(input.Prices) == 0 {
+		return nil, nil, fmt.Errorf("índice %s sem dados: nenhum ativo disponível", id)
+	}
+
+	result := customindex.Compute(*idx, input, intervalParam)
+	result.Warnings = append(warnings, result.Warnings...)
+	if len(result.Levels) == 0 {
+		return nil, nil, fmt.Errorf("índice %s sem dados no período", id)
+	}
+
+	stock := &domain.Stock{
+		Currency:            "BRL",
+		ShortName:           idx.Name,
+		LongName:            idx.Name,
+		Symbol:              customindex.Symbol(idx.ID),
+		UsedRange:           rangeParam,
+		UsedInterval:        intervalParam,
+		HistoricalDataPrice: make([]domain.HistoricalDataPrice, 0, len(result.Levels)),
+		Degraded:            degraded,
+		Adjustment:          string(mode),
+	}
+
+	for _, p := range result.Levels {
+		level := round(p.Value)
+		stock.HistoricalDataPrice = append(stock.HistoricalDataPrice, domain.HistoricalDataPrice{
+			Date:          p.Date,
+			Open:          level,
+			High:          level,
+			Low:           level,
+			Close:         level,
+			AdjustedClose: level,
+		})
+	}
+
+	last := result.Levels[len(result.Levels)-1]
+	stock.RegularMarketPrice = round(last.Value)
+	stock.RegularMarketTime = time.Unix(last.Date, 0)
+	previous := result.Levels[0].Value
+	if len(result.Levels) > 1 {
+		previous = result.Levels[len(result.Levels)-2].Value
+	}
+	stock.RegularMarketPreviousClose = round(previous)
+	stock.RegularMarketChange = round(last.Value - previous)
+	if previous > 0 {
+		stock.RegularMarketChangePercent = round((last.Value/previous - 1) * 100)
+	}
+
+	return stock, &result, nil
+}
+
+// fetch busca os ativos em paralelo. Ativos indisponíveis viram avisos e
+// ficam fora do cálculo.
+func (r *StockRepo) fetch(
+	constituents []domain.IndexConstituent,
+	rangeParam, intervalParam string,
+	mode adjust.Mode,
+) (customindex.Input, bool, []string) {
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		degraded bool
+		warnings []string
+		input    = customindex.Input{
+			Prices: make(map[string][]stats.Point, len(constituents)),
+			Shares: make(map[string]float64, len(constituents)),
+		}
+		sem = make(chan struct{}, maxConcurrentFetches)
+	)
+
+	for _, c := range constituents {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			stock, err := r.inner.GetBySymbol(symbol, rangeParam, intervalParam)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("⚠️ Ativo %s do índice indisponível: %v", symbol, err)
+				warnings = append(warnings, fmt.Sprintf("%s indisponível: %v", symbol, err))
+				return
+			}
+
+			candles := adjust.Apply(stock.HistoricalDataPrice, stock.DividendsData, mode)
+			input.Prices[symbol] = stats.Prices(candles)
+			shares := float64(stock.SharesOutstanding)
+			if shares <= 0 && stock.MarketCap > 0 && stock.RegularMarketPrice > 0 {
+				shares = float64(stock.MarketCap) / stock.RegularMarketPrice
+			}
+			input.Shares[symbol] = shares
+			degraded = degraded || stock.Degraded
+		}(c.Symbol)
+	}
+
+	wg.Wait()
+	sort.Strings(warnings)
+	return input, degraded, warnings
+}
+
+func round(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
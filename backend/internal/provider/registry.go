@@ -0,0 +1,142 @@
+package provider
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"cotacoes/internal/domain"
+)
+
+// Registry guarda as implementações de StockProvider e StockRepository
+// registradas por nome, para que a ordem de fallback venha da configuração.
+type Registry struct {
+	mu           sync.RWMutex
+	providers    map[string]domain.StockProvider
+	repositories map[string]domain.StockRepository
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		providers:    make(map[string]domain.StockProvider),
+		repositories: make(map[string]domain.StockRepository),
+	}
+}
+
+// Register registra impl em todos os contratos que ela implementa
+// (StockProvider e/ou StockRepository).
+func (r *Registry) Register(name string, impl any) error {
+	registered := false
+
+	if p, ok := impl.(domain.StockProvider); ok {
+		r.RegisterStockProvider(name, p)
+		registered = true
+	}
+	if repo, ok := impl.(domain.StockRepository); ok {
+		r.RegisterStockRepository(name, repo)
+		registered = true
+	}
+
+	if !registered {
+		return fmt.Errorf("provider %q não implementa StockProvider nem StockRepository", name)
+	}
+	return nil
+}
+
+func (r *Registry) RegisterStockProvider(name string, p domain.StockProvider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[normalizeName(name)] = p
+}
+
+func (r *Registry) RegisterStockRepository(name string, repo domain.StockRepository) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.repositories[normalizeName(name)] = repo
+}
+
+// Names retorna os nomes registrados (para logs e mensagens de erro)
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	set := make(map[string]struct{})
+	for name := range r.providers {
+		set[name] = struct{}{}
+	}
+	for name := range r.repositories {
+		set[name] = struct{}{}
+	}
+
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ProviderChain monta a cadeia de StockProvider na ordem informada
+func (r *Registry) ProviderChain(names []string, cfg ChainConfig) (*ProviderChain, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]*chainMember, 0, len(names))
+	for i, name := range names {
+		name = normalizeName(name)
+		p, ok := r.providers[name]
+		if !ok {
+			return nil, fmt.Errorf("StockProvider %q não registrado (disponíveis: %s)", name, strings.Join(r.providerNames(), ", "))
+		}
+		members = append(members, &chainMember{name: name, position: i, impl: p})
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("cadeia de StockProvider vazia")
+	}
+
+	return &ProviderChain{chain: newChain("providers", members, cfg)}, nil
+}
+
+// RepositoryChain monta a cadeia de StockRepository na ordem informada
+func (r *Registry) RepositoryChain(names []string, cfg ChainConfig) (*RepositoryChain, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	members := make([]*chainMember, 0, len(names))
+	for i, name := range names {
+		name = normalizeName(name)
+		repo, ok := r.repositories[name]
+		if !ok {
+			return nil, fmt.Errorf("StockRepository %q não registrado (disponíveis: %s)", name, strings.Join(r.repositoryNames(), ", "))
+		}
+		members = append(members, &chainMember{name: name, position: i, impl: repo})
+	}
+	if len(members) == 0 {
+		return nil, fmt.Errorf("cadeia de StockRepository vazia")
+	}
+
+	return &RepositoryChain{chain: newChain("repositories", members, cfg)}, nil
+}
+
+func (r *Registry) providerNames() []string {
+	names := make([]string, 0, len(r.providers))
+	for name := range r.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (r *Registry) repositoryNames() []string {
+	names := make([]string, 0, len(r.repositories))
+	for name := range r.repositories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func normalizeName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,276 @@
+package screener
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Expr é uma expressão já verificada, pronta para ser avaliada
+type Expr struct {
+	Source string
+	root   node
+	fields []string
+}
+
+// Compile lê a expressão e verifica os tipos contra os campos conhecidos.
+// A expressão precisa resultar em bool.
+func Compile(src string, fields map[string]Type) (*Expr, error) {
+	root, err := parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	used := map[string]bool{}
+	typ, err := check(root, fields, used)
+	if err != nil {
+		return nil, err
+	}
+	if typ != TypeBool {
+		return nil, errorAt(root.position(), "a expressão deve ser uma condição (bool), não %s", typ)
+	}
+
+	names := make([]string, 0, len(used))
+	for name := range used {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &Expr{Source: src, root: root, fields: names}, nil
+}
+
+// Fields retorna os campos usados pela expressão
+func (e *Expr) Fields() []string {
+	return e.fields
+}
+
+// Match avalia a expressão para uma ação. Comparações com dado ausente
+// resultam em "desconhecido" (lógica de três valores) e a ação só passa
+// no filtro quando o resultado é verdadeiro.
+func (e *Expr) Match(lookup func(field string) Value) bool {
+	v := eval(e.root, lookup)
+	return !v.Null && v.Bool
+}
+
+func check(n node, fields map[string]Type, used map[string]bool) (Type, error) {
+	switch n := n.(type) {
+	case *literalNode:
+		return n.typ, nil
+
+	case *fieldNode:
+		typ, ok := fields[n.name]
+		if !ok {
+			return "", errorAt(n.pos, "campo desconhecido %q%s", n.name, suggestion(n.name, fields))
+		}
+		used[n.name] = true
+		return typ, nil
+
+	case *unaryNode:
+		typ, err := check(n.x, fields, used)
+		if err != nil {
+			return "", err
+		}
+		want := TypeBool
+		if n.op == "-" {
+			want = TypeNumber
+		}
+		if typ != want {
+			return "", errorAt(n.pos, "operador %q exige %s, recebeu %s", n.op, want, typ)
+		}
+		return want, nil
+
+	case *binaryNode:
+		left, err := check(n.l, fields, used)
+		if err != nil {
+			return "", err
+		}
+		right, err := check(n.r, fields, used)
+		if err != nil {
+			return "", err
+		}
+
+		switch n.op {
+		case "and", "or":
+			if left != TypeBool || right != TypeBool {
+				return "", errorAt(n.pos, "operador %q exige condições dos dois lados (recebeu %s e %s)", n.op, left, right)
+			}
+			return TypeBool, nil
+		case "+", "-", "*", "/":
+			if left != TypeNumber || right != TypeNumber {
+				return "", errorAt(n.pos, "operador %q exige números (recebeu %s e %s)", n.op, left, right)
+			}
+			return TypeNumber, nil
+		case "=", "!=":
+			if left != right {
+				return "", errorAt(n.pos, "não é possível comparar %s com %s", left, right)
+			}
+			return TypeBool, nil
+		default: // < <= > >=
+			if left != TypeNumber || right != TypeNumber {
+				return "", errorAt(n.pos, "operador %q exige números (recebeu %s e %s)", n.op, left, right)
+			}
+			return TypeBool, nil
+		}
+
+	case *inNode:
+		typ, err := check(n.x, fields, used)
+		if err != nil {
+			return "", err
+		}
+		if typ == TypeBool {
+			return "", errorAt(n.pos, "\"in\" não se aplica a %s", typ)
+		}
+		for _, lit := range n.list {
+			if lit.typ != typ {
+				return "", errorAt(lit.pos, "lista de %s contém %s", typ, lit.typ)
+			}
+		}
+		return TypeBool, nil
+	}
+
+	return "", errorAt(n.position(), "expressão inválida")
+}
+
+// suggestion sugere o campo conhecido mais parecido
+func suggestion(name string, fields map[string]Type) string {
+	best, bestDist := "", min(3, len(name)/2+1)
+	for candidate := range fields {
+		if d := distance(name, candidate); d < bestDist || (d == bestDist && best != "" && candidate < best) {
+			best, bestDist = candidate, d
+		}
+	}
+	if best == "" {
+		return ""
+	}
+	return " (você quis dizer " + best + "?)"
+}
+
+// distance é a distância de edição (Levenshtein) entre dois textos
+func distance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
+
+func eval(n node, lookup func(string) Value) Value {
+	switch n := n.(type) {
+	case *literalNode:
+		return n.value
+
+	case *fieldNode:
+		return lookup(n.name)
+
+	case *unaryNode:
+		x := eval(n.x, lookup)
+		if x.Null {
+			return Null
+		}
+		if n.op == "-" {
+			return Number(-x.Num)
+		}
+		return Bool(!x.Bool)
+
+	case *binaryNode:
+		switch n.op {
+		case "and":
+			l := eval(n.l, lookup)
+			if !l.Null && !l.Bool {
+				return Bool(false)
+			}
+			r := eval(n.r, lookup)
+			if !r.Null && !r.Bool {
+				return Bool(false)
+			}
+			if l.Null || r.Null {
+				return Null
+			}
+			return Bool(true)
+		case "or":
+			l := eval(n.l, lookup)
+			if !l.Null && l.Bool {
+				return Bool(true)
+			}
+			r := eval(n.r, lookup)
+			if !r.Null && r.Bool {
+				return Bool(true)
+			}
+			if l.Null || r.Null {
+				return Null
+			}
+			return Bool(false)
+		}
+
+		l, r := eval(n.l, lookup), eval(n.r, lookup)
+		if l.Null || r.Null {
+			return Null
+		}
+		switch n.op {
+		case "+":
+			return number(l.Num + r.Num)
+		case "-":
+			return number(l.Num - r.Num)
+		case "*":
+			return number(l.Num * r.Num)
+		case "/":
+			return number(l.Num / r.Num)
+		case "=":
+			return Bool(equal(l, r))
+		case "!=":
+			return Bool(!equal(l, r))
+		case "<":
+			return Bool(l.Num < r.Num)
+		case "<=":
+			return Bool(l.Num <= r.Num)
+		case ">":
+			return Bool(l.Num > r.Num)
+		case ">=":
+			return Bool(l.Num >= r.Num)
+		}
+
+	case *inNode:
+		x := eval(n.x, lookup)
+		if x.Null {
+			return Null
+		}
+		found := false
+		for _, lit := range n.list {
+			if equal(x, lit.value) {
+				found = true
+				break
+			}
+		}
+		return Bool(found != n.not)
+	}
+
+	return Null
+}
+
+// equal compara valores do mesmo tipo; textos sem diferenciar maiúsculas
+func equal(a, b Value) bool {
+	if a.Str != "" || b.Str != "" {
+		return strings.EqualFold(a.Str, b.Str)
+	}
+	return a.Num == b.Num && a.Bool == b.Bool
+}
+
+// number descarta resultados não finitos (ex: divisão por zero)
+func number(v float64) Value {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return Null
+	}
+	return Number(v)
+}
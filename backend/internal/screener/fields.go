@@ -0,0 +1,160 @@
+package screener
+
+import (
+	"time"
+
+	"cotacoes/internal/analytics/dividends"
+	"cotacoes/internal/analytics/ratios"
+	"cotacoes/internal/domain"
+)
+
+// Field descreve um campo disponível nas expressões. Campos Detail exigem
+// o detalhe da ação (fundamentos e proventos), que custa uma chamada ao
+// provider por ação.
+type Field struct {
+	Name        string `json:"name"`
+	Type        Type   `json:"type"`
+	Description string `json:"description"`
+	Detail      bool   `json:"detail"`
+}
+
+// Fields é o catálogo de campos. Percentuais são expressos em % (6 = 6%).
+var Fields = []Field{
+	{Name: "symbol", Type: TypeString, Description: "Código de negociação"},
+	{Name: "name", Type: TypeString, Description: "Nome da empresa"},
+	{Name: "sector", Type: TypeString, Description: "Setor"},
+	{Name: "type", Type: TypeString, Description: "Tipo do ativo (stock, fund, bdr)"},
+	{Name: "price", Type: TypeNumber, Description: "Último preço"},
+	{Name: "change", Type: TypeNumber, Description: "Variação do dia (%)"},
+	{Name: "volume", Type: TypeNumber, Description: "Volume do dia"},
+	{Name: "market_cap", Type: TypeNumber, Description: "Valor de mercado"},
+
+	{Name: "pe", Type: TypeNumber, Description: "Preço / lucro", Detail: true},
+	{Name: "pb", Type: TypeNumber, Description: "Preço / valor patrimonial", Detail: true},
+	{Name: "ev_ebitda", Type: TypeNumber, Description: "EV / EBITDA", Detail: true},
+	{Name: "roe", Type: TypeNumber, Description: "Retorno sobre o patrimônio (%)", Detail: true},
+	{Name: "roic", Type: TypeNumber, Description: "Retorno sobre o capital investido (%)", Detail: true},
+	{Name: "net_debt_ebitda", Type: TypeNumber, Description: "Dívida líquida / EBITDA", Detail: true},
+	{Name: "current_ratio", Type: TypeNumber, Description: "Liquidez corrente", Detail: true},
+	{Name: "gross_margin", Type: TypeNumber, Description: "Margem bruta (%)", Detail: true},
+	{Name: "net_margin", Type: TypeNumber, Description: "Margem líquida (%)", Detail: true},
+	{Name: "payout", Type: TypeNumber, Description: "Payout (%)", Detail: true},
+	{Name: "dy_ttm", Type: TypeNumber, Description: "Dividend yield líquido de IR dos últimos 12 meses (%)", Detail: true},
+	{Name: "dy_ttm_gross", Type: TypeNumber, Description: "Dividend yield bruto dos últimos 12 meses (%)", Detail: true},
+	{Name: "eps", Type: TypeNumber, Description: "Lucro por ação", Detail: true},
+	{Name: "high_52w", Type: TypeNumber, Description: "Máxima de 52 semanas", Detail: true},
+	{Name: "low_52w", Type: TypeNumber, Description: "Mínima de 52 semanas", Detail: true},
+}
+
+// ratioFields liga os campos aos indicadores do pacote ratios e indica se
+// o valor é percentual
+var ratioFields = map[string]struct {
+	ratio   string
+	percent bool
+}{
+	"pe":              {ratio: "pe"},
+	"pb":              {ratio: "pb"},
+	"ev_ebitda":       {ratio: "evEbitda"},
+	"roe":             {ratio: "roe", percent: true},
+	"roic":            {ratio: "roic", percent: true},
+	"net_debt_ebitda": {ratio: "netDebtEbitda"},
+	"current_ratio":   {ratio: "currentRatio"},
+	"gross_margin":    {ratio: "grossMargin", percent: true},
+	"net_margin":      {ratio: "netMargin", percent: true},
+	"payout":          {ratio: "payout", percent: true},
+}
+
+// FieldTypes retorna o tipo de cada campo, para Compile
+func FieldTypes() map[string]Type {
+	types := make(map[string]Type, len(Fields))
+	for _, f := range Fields {
+		types[f.Name] = f.Type
+	}
+	return types
+}
+
+// FieldByName procura um campo do catálogo
+func FieldByName(name string) (Field, bool) {
+	for _, f := range Fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return Field{}, false
+}
+
+// NeedsDetail indica se algum dos campos exige o detalhe da ação
+func NeedsDetail(names ...string) bool {
+	for _, name := range names {
+		if f, ok := FieldByName(name); ok && f.Detail {
+			return true
+		}
+	}
+	return false
+}
+
+// Row são os valores dos campos de uma ação
+type Row map[string]Value
+
+// Get devolve o valor do campo; campos sem valor são Null
+func (r Row) Get(field string) Value {
+	if v, ok := r[field]; ok {
+		return v
+	}
+	return Null
+}
+
+// RowFromListItem preenche os campos da listagem de ações
+func RowFromListItem(item domain.StockListItem) Row {
+	row := Row{
+		"symbol": String(item.Stock),
+		"name":   String(item.Name),
+		"sector": String(item.Sector),
+		"type":   String(item.Type),
+		"change": Number(item.Change),
+		"volume": Number(float64(item.Volume)),
+	}
+	if item.Close > 0 {
+		row["price"] = Number(item.Close)
+	}
+	if item.MarketCap > 0 {
+		row["market_cap"] = Number(float64(item.MarketCap))
+	}
+	return row
+}
+
+// AddDetail preenche os campos calculados a partir do detalhe da ação.
+// Indicadores sem dado ficam ausentes (Null), nunca zero.
+func (r Row) AddDetail(stock *domain.Stock, now time.Time) {
+	summary := dividends.Summarize(stock.DividendsData.CashDividends, stock.RegularMarketPrice, now)
+	// dy_ttm é líquido (JCP sofre retenção), como nas demais rotas
+	if summary.TTMNetYield != nil {
+		r["dy_ttm"] = Number(*summary.TTMNetYield * 100)
+	}
+	if summary.TTMYield != nil {
+		r["dy_ttm_gross"] = Number(*summary.TTMYield * 100)
+	}
+
+	for _, ratio := range ratios.Compute(ratios.FromStock(stock, summary.TTMPerShare)) {
+		for field, def := range ratioFields {
+			if def.ratio != ratio.Name || ratio.Value == nil {
+				continue
+			}
+			v := *ratio.Value
+			if def.percent {
+				v *= 100
+			}
+			r[field] = Number(v)
+		}
+	}
+
+	if stock.EarningsPerShare != 0 {
+		r["eps"] = Number(stock.EarningsPerShare)
+	}
+	if stock.FiftyTwoWeekHigh > 0 {
+		r["high_52w"] = Number(stock.FiftyTwoWeekHigh)
+	}
+	if stock.FiftyTwoWeekLow > 0 {
+		r["low_52w"] = Number(stock.FiftyTwoWeekLow)
+	}
+}
@@ -0,0 +1,138 @@
+// Package screener implementa a linguagem de filtros do screener de ações:
+// expressões como `sector in ("Finance","Utilities") and dy_ttm > 6` são
+// lidas, verificadas contra os campos disponíveis e avaliadas por ação.
+package screener
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokIdent
+	tokNumber
+	tokString
+	tokOp     // = == != < <= > >= + - * /
+	tokLParen // (
+	tokRParen // )
+	tokComma  // ,
+)
+
+type token struct {
+	kind tokenKind
+	text string // texto original (ou o valor já decodificado em strings)
+	num  float64
+	pos  int
+}
+
+// Error é um erro de sintaxe ou de tipo, com a posição (coluna a partir
+// de 1) na expressão
+type Error struct {
+	Pos int
+	Msg string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("posição %d: %s", e.Pos, e.Msg)
+}
+
+func errorAt(pos int, format string, args ...any) *Error {
+	return &Error{Pos: pos + 1, Msg: fmt.Sprintf(format, args...)}
+}
+
+// lex separa a expressão em tokens
+func lex(src string) ([]token, error) {
+	var tokens []token
+	runes := []rune(src)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == ',':
+			tokens = append(tokens, token{kind: tokComma, text: ",", pos: i})
+			i++
+
+		case r == '"' || r == '\'':
+			start := i
+			var sb strings.Builder
+			i++
+			for ; i < len(runes) && runes[i] != r; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				sb.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, errorAt(start, "texto sem aspas de fechamento")
+			}
+			i++
+			tokens = append(tokens, token{kind: tokString, text: sb.String(), pos: start})
+
+		case unicode.IsDigit(r) || (r == '.' && i+1 < len(runes) && unicode.IsDigit(runes[i+1])):
+			start := i
+			for i < len(runes) && (unicode.IsDigit(runes[i]) || runes[i] == '.' || runes[i] == '_') {
+				i++
+			}
+			// Notação científica: 1e6, 2.5E-3
+			if i < len(runes) && (runes[i] == 'e' || runes[i] == 'E') {
+				j := i + 1
+				if j < len(runes) && (runes[j] == '+' || runes[j] == '-') {
+					j++
+				}
+				if j < len(runes) && unicode.IsDigit(runes[j]) {
+					i = j
+					for i < len(runes) && unicode.IsDigit(runes[i]) {
+						i++
+					}
+				}
+			}
+			text := string(runes[start:i])
+			v, err := strconv.ParseFloat(strings.ReplaceAll(text, "_", ""), 64)
+			if err != nil {
+				return nil, errorAt(start, "número inválido %q", text)
+			}
+			tokens = append(tokens, token{kind: tokNumber, text: text, num: v, pos: start})
+
+		case unicode.IsLetter(r) || r == '_':
+			start := i
+			for i < len(runes) && (unicode.IsLetter(runes[i]) || unicode.IsDigit(runes[i]) || runes[i] == '_') {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokIdent, text: string(runes[start:i]), pos: start})
+
+		default:
+			start := i
+			op := string(r)
+			if i+1 < len(runes) {
+				switch two := string(runes[i : i+2]); two {
+				case "==", "!=", "<=", ">=", "<>":
+					op = two
+				}
+			}
+			switch op {
+			case "=", "==", "!=", "<>", "<", "<=", ">", ">=", "+", "-", "*", "/":
+			default:
+				return nil, errorAt(start, "caractere inesperado %q", op)
+			}
+			i += len([]rune(op))
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: start})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes)})
+	return tokens, nil
+}
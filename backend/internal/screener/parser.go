@@ -0,0 +1,361 @@
+package screener
+
+import (
+	"strings"
+)
+
+// Type é o tipo de um campo ou expressão
+type Type string
+
+const (
+	TypeNumber Type = "number"
+	TypeString Type = "string"
+	TypeBool   Type = "bool"
+)
+
+// Value é um valor avaliado; Null indica dado ausente
+type Value struct {
+	Null bool
+	Num  float64
+	Str  string
+	Bool bool
+}
+
+// Null é o valor ausente
+var Null = Value{Null: true}
+
+func Number(v float64) Value { return Value{Num: v} }
+func String(v string) Value  { return Value{Str: v} }
+func Bool(v bool) Value      { return Value{Bool: v} }
+
+// node é um nó da árvore sintática
+type node interface {
+	position() int
+}
+
+type literalNode struct {
+	typ   Type
+	value Value
+	pos   int
+}
+
+type fieldNode struct {
+	name string
+	pos  int
+}
+
+type unaryNode struct {
+	op  string // "not" ou "-"
+	x   node
+	pos int
+}
+
+type binaryNode struct {
+	op   string // and or = != < <= > >= + - * /
+	l, r node
+	pos  int
+}
+
+type inNode struct {
+	x    node
+	list []*literalNode
+	not  bool
+	pos  int
+}
+
+func (n *literalNode) position() int { return n.pos }
+func (n *fieldNode) position() int   { return n.pos }
+func (n *unaryNode) position() int   { return n.pos }
+func (n *binaryNode) position() int  { return n.pos }
+func (n *inNode) position() int      { return n.pos }
+
+// parser é um analisador descendente recursivo. Gramática:
+//
+//	expr    := and ("or" and)*
+//	and     := not ("and" not)*
+//	not     := "not" not | cmp
+//	cmp     := sum [ op sum | ["not"] "in" "(" literal ("," literal)* ")" ]
+//	sum     := prod (("+" | "-") prod)*
+//	prod    := unary (("*" | "/") unary)*
+//	unary   := "-" unary | primary
+//	primary := número | texto | true | false | campo | "(" expr ")"
+type parser struct {
+	tokens []token
+	i      int
+	depth  int
+}
+
+// maxDepth limita o aninhamento (parênteses, "not" e "-" encadeados): a
+// recursão sem limite estoura a pilha, o que derruba o processo inteiro
+const maxDepth = 64
+
+// enter conta um nível de aninhamento; leave desfaz
+func (p *parser) enter(pos int) error {
+	p.depth++
+	if p.depth > maxDepth {
+		return errorAt(pos, "expressão aninhada demais (máximo de %d níveis)", maxDepth)
+	}
+	return nil
+}
+
+func (p *parser) leave() { p.depth-- }
+
+func parse(src string) (node, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+
+	if p.peek().kind == tokEOF {
+		return nil, errorAt(0, "expressão vazia")
+	}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		return nil, errorAt(t.pos, "token inesperado %q", t.text)
+	}
+	return n, nil
+}
+
+func (p *parser) peek() token { return p.tokens[p.i] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokEOF {
+		p.i++
+	}
+	return t
+}
+
+// keyword confere se o próximo token é a palavra-chave (sem diferenciar
+// maiúsculas)
+func (p *parser) keyword(word string) bool {
+	t := p.peek()
+	return t.kind == tokIdent && strings.EqualFold(t.text, word)
+}
+
+func isKeyword(s string) bool {
+	switch strings.ToLower(s) {
+	case "and", "or", "not", "in", "true", "false":
+		return true
+	}
+	return false
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		t := p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "or", l: left, r: right, pos: t.pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		t := p.next()
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: "and", l: left, r: right, pos: t.pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseNot() (node, error) {
+	if p.keyword("not") {
+		t := p.next()
+		if err := p.enter(t.pos); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		x, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "not", x: x, pos: t.pos}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+
+	t := p.peek()
+	if t.kind == tokOp {
+		switch t.text {
+		case "=", "==", "!=", "<>", "<", "<=", ">", ">=":
+			p.next()
+			right, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			op := t.text
+			switch op {
+			case "==":
+				op = "="
+			case "<>":
+				op = "!="
+			}
+			return &binaryNode{op: op, l: left, r: right, pos: t.pos}, nil
+		}
+	}
+
+	negated := false
+	if p.keyword("not") {
+		// "x not in (...)": só é válido seguido de "in"
+		if next := p.tokens[p.i+1]; !(next.kind == tokIdent && strings.EqualFold(next.text, "in")) {
+			return nil, errorAt(next.pos, "esperado \"in\" depois de \"not\"")
+		}
+		p.next()
+		negated = true
+	}
+	if p.keyword("in") {
+		in := p.next()
+		list, err := p.parseList()
+		if err != nil {
+			return nil, err
+		}
+		return &inNode{x: left, list: list, not: negated, pos: in.pos}, nil
+	}
+
+	return left, nil
+}
+
+func (p *parser) parseList() ([]*literalNode, error) {
+	if t := p.next(); t.kind != tokLParen {
+		return nil, errorAt(t.pos, "esperado \"(\" depois de \"in\"")
+	}
+
+	var list []*literalNode
+	for {
+		t := p.next()
+		lit, ok := literalFrom(t)
+		if !ok {
+			return nil, errorAt(t.pos, "esperado número ou texto na lista")
+		}
+		list = append(list, lit)
+
+		sep := p.next()
+		if sep.kind == tokRParen {
+			return list, nil
+		}
+		if sep.kind != tokComma {
+			return nil, errorAt(sep.pos, "esperado \",\" ou \")\" na lista")
+		}
+	}
+}
+
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokOp && (t.text == "+" || t.text == "-"); t = p.peek() {
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: t.text, l: left, r: right, pos: t.pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for t := p.peek(); t.kind == tokOp && (t.text == "*" || t.text == "/"); t = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &binaryNode{op: t.text, l: left, r: right, pos: t.pos}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	if t := p.peek(); t.kind == tokOp && t.text == "-" {
+		p.next()
+		if err := p.enter(t.pos); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		x, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &unaryNode{op: "-", x: x, pos: t.pos}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (node, error) {
+	t := p.next()
+
+	if lit, ok := literalFrom(t); ok {
+		return lit, nil
+	}
+
+	switch t.kind {
+	case tokIdent:
+		if strings.EqualFold(t.text, "true") || strings.EqualFold(t.text, "false") {
+			return &literalNode{typ: TypeBool, value: Bool(strings.EqualFold(t.text, "true")), pos: t.pos}, nil
+		}
+		if isKeyword(t.text) {
+			return nil, errorAt(t.pos, "palavra-chave %q fora de lugar", t.text)
+		}
+		return &fieldNode{name: strings.ToLower(t.text), pos: t.pos}, nil
+
+	case tokLParen:
+		if err := p.enter(t.pos); err != nil {
+			return nil, err
+		}
+		defer p.leave()
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, errorAt(closing.pos, "esperado \")\"")
+		}
+		return n, nil
+
+	case tokEOF:
+		return nil, errorAt(t.pos, "expressão incompleta")
+	}
+
+	return nil, errorAt(t.pos, "token inesperado %q", t.text)
+}
+
+func literalFrom(t token) (*literalNode, bool) {
+	switch t.kind {
+	case tokNumber:
+		return &literalNode{typ: TypeNumber, value: Number(t.num), pos: t.pos}, true
+	case tokString:
+		return &literalNode{typ: TypeString, value: String(t.text), pos: t.pos}, true
+	}
+	return nil, false
+}
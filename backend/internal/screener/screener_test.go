@@ -0,0 +1,241 @@
+package screener
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+func TestLex(t *testing.T) {
+	tokens, err := lex(`pe>=1_000 and sector in ('A\'s', "B") or x <> 2.5e-1`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []struct {
+		kind tokenKind
+		text string
+		pos  int
+	}{
+		{tokIdent, "pe", 0},
+		{tokOp, ">=", 2},
+		{tokNumber, "1_000", 4},
+		{tokIdent, "and", 10},
+		{tokIdent, "sector", 14},
+		{tokIdent, "in", 21},
+		{tokLParen, "(", 24},
+		{tokString, "A's", 25},
+		{tokComma, ",", 31},
+		{tokString, "B", 33},
+		{tokRParen, ")", 36},
+		{tokIdent, "or", 38},
+		{tokIdent, "x", 41},
+		{tokOp, "<>", 43},
+		{tokNumber, "2.5e-1", 46},
+		{tokEOF, "", 52},
+	}
+	if len(tokens) != len(want) {
+		t.Fatalf("%d tokens, esperado %d: %+v", len(tokens), len(want), tokens)
+	}
+	for i, w := range want {
+		got := tokens[i]
+		if got.kind != w.kind || got.text != w.text || got.pos != w.pos {
+			t.Errorf("token %d = {%d %q %d}, esperado {%d %q %d}", i, got.kind, got.text, got.pos, w.kind, w.text, w.pos)
+		}
+	}
+	if tokens[2].num != 1000 || tokens[14].num != 0.25 {
+		t.Errorf("números = %v/%v, esperado 1000/0.25", tokens[2].num, tokens[14].num)
+	}
+}
+
+func TestLexErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+	}{
+		{`sector = "Finance`, 10},
+		{`pe > 5 & pb < 1`, 8},
+		{`pe > 1.2.3`, 6},
+	}
+	for _, tt := range tests {
+		_, err := lex(tt.src)
+		var lexErr *Error
+		if !errors.As(err, &lexErr) {
+			t.Errorf("lex(%q): esperado *Error, recebeu %v", tt.src, err)
+			continue
+		}
+		if lexErr.Pos != tt.pos {
+			t.Errorf("lex(%q): posição %d, esperado %d (%v)", tt.src, lexErr.Pos, tt.pos, err)
+		}
+	}
+}
+
+// testFields é um catálogo reduzido para os testes
+var testFields = map[string]Type{
+	"pe":     TypeNumber,
+	"dy_ttm": TypeNumber,
+	"price":  TypeNumber,
+	"sector": TypeString,
+}
+
+func match(t *testing.T, src string, row Row) bool {
+	t.Helper()
+	expr, err := Compile(src, testFields)
+	if err != nil {
+		t.Fatalf("Compile(%q): %v", src, err)
+	}
+	return expr.Match(row.Get)
+}
+
+func TestMatch(t *testing.T) {
+	row := Row{
+		"pe":     Number(8),
+		"dy_ttm": Number(7.5),
+		"price":  Number(20),
+		"sector": String("Finance"),
+	}
+
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{`pe < 10 and dy_ttm > 6`, true},
+		// "and" tem precedência sobre "or"
+		{`pe > 10 and dy_ttm > 6 or sector = "finance"`, true},
+		{`pe > 10 and (dy_ttm > 6 or sector = "finance")`, false},
+		// "*" tem precedência sobre "+"; "-" unário
+		{`price + pe * 2 = 36`, true},
+		{`-pe + 10 == 2`, true},
+		{`price / pe > 2.4 and price / pe < 2.6`, true},
+		{`sector in ("Utilities", "FINANCE")`, true},
+		{`sector not in ("Utilities", "Finance")`, false},
+		{`not pe >= 8`, false},
+		{`pe != 8 or pe <> 8`, false},
+		{`true and not false`, true},
+	}
+	for _, tt := range tests {
+		if got := match(t, tt.src, row); got != tt.want {
+			t.Errorf("%s = %v, esperado %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestMatchMissingData(t *testing.T) {
+	// Sem dy_ttm: comparações são "desconhecidas" e a ação não passa,
+	// a não ser que o outro lado do "or" seja verdadeiro
+	row := Row{"pe": Number(8)}
+
+	tests := []struct {
+		src  string
+		want bool
+	}{
+		{`dy_ttm > 6`, false},
+		{`not dy_ttm > 6`, false},
+		{`dy_ttm > 6 or pe < 10`, true},
+		{`dy_ttm > 6 and pe < 10`, false},
+		{`dy_ttm > 6 and pe > 10`, false},
+		// Divisão por zero vira dado ausente
+		{`pe / 0 > 1`, false},
+	}
+	for _, tt := range tests {
+		if got := match(t, tt.src, row); got != tt.want {
+			t.Errorf("%s = %v, esperado %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestCompileErrors(t *testing.T) {
+	tests := []struct {
+		src string
+		pos int
+		msg string
+	}{
+		{``, 1, "expressão vazia"},
+		{`pe >`, 5, "expressão incompleta"},
+		{`pe > 5)`, 7, "token inesperado"},
+		{`(pe > 5`, 8, "esperado \")\""},
+		{`sector not like "F"`, 12, "esperado \"in\""},
+		{`sector in "Finance"`, 11, "esperado \"(\""},
+		{`sector in ("A" "B")`, 16, "esperado \",\" ou \")\""},
+		{`pe > and`, 6, "palavra-chave"},
+		{`dy_tm > 6`, 1, "você quis dizer dy_ttm?"},
+		{`pe`, 1, "deve ser uma condição"},
+		{`sector > 5`, 8, "exige números"},
+		{`sector = 5`, 8, "não é possível comparar"},
+		{`pe > 1 and price`, 8, "condições dos dois lados"},
+		{`not pe`, 1, "exige bool"},
+		{`-sector = "x"`, 1, "exige number"},
+		{`sector in ("A", 1)`, 17, "lista de string contém number"},
+		{`(pe > 1) in (1)`, 10, "não se aplica"},
+	}
+	for _, tt := range tests {
+		_, err := Compile(tt.src, testFields)
+		var compileErr *Error
+		if !errors.As(err, &compileErr) {
+			t.Errorf("Compile(%q): esperado *Error, recebeu %v", tt.src, err)
+			continue
+		}
+		if compileErr.Pos != tt.pos || !strings.Contains(compileErr.Msg, tt.msg) {
+			t.Errorf("Compile(%q) = %v, esperado posição %d com %q", tt.src, err, tt.pos, tt.msg)
+		}
+	}
+}
+
+func TestCompileDepthLimit(t *testing.T) {
+	deep := strings.Repeat("(", 100_000) + "pe > 1" + strings.Repeat(")", 100_000)
+	for _, src := range []string{deep, strings.Repeat("not ", 100_000) + "pe > 1", strings.Repeat("-", 100_000) + "pe > 1"} {
+		_, err := Compile(src, testFields)
+		var compileErr *Error
+		if !errors.As(err, &compileErr) || !strings.Contains(compileErr.Msg, "aninhada demais") {
+			t.Errorf("erro = %v, esperado limite de aninhamento", err)
+		}
+	}
+
+	ok := strings.Repeat("(", maxDepth) + "pe > 1" + strings.Repeat(")", maxDepth)
+	if _, err := Compile(ok, testFields); err != nil {
+		t.Errorf("%d níveis devem ser aceitos: %v", maxDepth, err)
+	}
+}
+
+func TestExprFields(t *testing.T) {
+	expr, err := Compile(`PE < 10 and sector in ("Finance") and pe > 1`, testFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(expr.Fields(), ","); got != "pe,sector" {
+		t.Errorf("campos = %s, esperado pe,sector", got)
+	}
+	if !NeedsDetail("symbol", "dy_ttm") || NeedsDetail("symbol", "price") {
+		t.Error("NeedsDetail deve valer só para campos do detalhe")
+	}
+}
+
+func TestAddDetailDividendYield(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	stock := &domain.Stock{
+		RegularMarketPrice: 10,
+		DividendsData: domain.DividendsData{
+			CashDividends: []domain.CashDividend{
+				{Label: "DIVIDENDO", Rate: 0.4, NetRate: 0.4, LastDatePrior: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+				{Label: "JCP", Rate: 0.4, NetRate: 0.34, LastDatePrior: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)},
+			},
+		},
+	}
+
+	row := Row{}
+	row.AddDetail(stock, now)
+	if got := row.Get("dy_ttm"); got.Null || !near(got.Num, 7.4) {
+		t.Errorf("dy_ttm = %+v, esperado 7,4 (líquido)", got)
+	}
+	if got := row.Get("dy_ttm_gross"); got.Null || !near(got.Num, 8) {
+		t.Errorf("dy_ttm_gross = %+v, esperado 8 (bruto)", got)
+	}
+}
+
+func near(a, b float64) bool {
+	d := a - b
+	return d < 1e-9 && d > -1e-9
+}
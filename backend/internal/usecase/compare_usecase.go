@@ -0,0 +1,285 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"cotacoes/internal/analytics/dividends"
+	"cotacoes/internal/analytics/ratios"
+	"cotacoes/internal/analytics/stats"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// Limite da rota /compare
+const maxCompareSymbols = 10
+
+// CompareQuote é a cotação atual de um símbolo
+type CompareQuote struct {
+	Price            float64   `json:"price"`
+	Change           float64   `json:"change"`
+	ChangePercent    float64   `json:"changePercent"`
+	Volume           int64     `json:"volume"`
+	MarketCap        *float64  `json:"marketCap"`
+	FiftyTwoWeekHigh *float64  `json:"fiftyTwoWeekHigh"`
+	FiftyTwoWeekLow  *float64  `json:"fiftyTwoWeekLow"`
+	Time             time.Time `json:"time"`
+}
+
+// CompareFundamentals são os dados contábeis usados na comparação;
+// null indica dado ausente
+type CompareFundamentals struct {
+	EarningsPerShare  *float64 `json:"earningsPerShare"`
+	SharesOutstanding *float64 `json:"sharesOutstanding"`
+	TotalRevenue      *float64 `json:"totalRevenue"`
+	EBITDA            *float64 `json:"ebitda"`
+	TotalDebt         *float64 `json:"totalDebt"`
+	TotalCash         *float64 `json:"totalCash"`
+	FreeCashflow      *float64 `json:"freeCashflow"`
+}
+
+// CompareDividends resume os proventos dos últimos 12 meses
+type CompareDividends struct {
+	TTMPerShare float64  `json:"ttmPerShare"`
+	TTMYield    *float64 `json:"ttmYield"`
+	TTMNetYield *float64 `json:"ttmNetYield"`
+}
+
+// CompareRow é a linha de um símbolo na tabela de comparação
+type CompareRow struct {
+	Symbol       string                  `json:"symbol"`
+	Name         string                  `json:"name"`
+	Sector       string                  `json:"sector"`
+	Currency     string                  `json:"currency"`
+	Quote        CompareQuote            `json:"quote"`
+	Fundamentals CompareFundamentals     `json:"fundamentals"`
+	Ratios       map[string]ratios.Ratio `json:"ratios"`
+	Dividends    CompareDividends        `json:"dividends"`
+	Risk         stats.Report            `json:"risk"`
+	Degraded     bool                    `json:"degraded,omitempty"`
+}
+
+// ComparePerformance é o desempenho de cada símbolo normalizado para 100
+// em BaseDate, a primeira data do range em que todos negociaram
+type ComparePerformance struct {
+	Base     float64               `json:"base"`
+	BaseDate string                `json:"baseDate,omitempty"`
+	Dates    []string              `json:"dates"`
+	Series   map[string][]*float64 `json:"series"`
+}
+
+// CompareResult representa a resposta da rota /compare
+type CompareResult struct {
+	Range       string             `json:"range"`
+	Interval    string             `json:"interval"`
+	Adjust      string             `json:"adjust,omitempty"`
+	Symbols     []string           `json:"symbols"`
+	Table       []CompareRow       `json:"table"`
+	Performance ComparePerformance `json:"performance"`
+	Errors      map[string]string  `json:"errors,omitempty"`
+}
+
+type CompareUseCase struct {
+	getStockUC   *GetStockUseCase
+	benchmark    string
+	riskFreeRate float64
+}
+
+func NewCompareUseCase(
+	getStockUC *GetStockUseCase,
+	benchmark string,
+	riskFreeRate float64,
+) *CompareUseCase {
+	return &CompareUseCase{
+		getStockUC:   getStockUC,
+		benchmark:    benchmark,
+		riskFreeRate: riskFreeRate,
+	}
+}
+
+// Execute busca os símbolos em paralelo e monta a tabela de comparação e
+// a série de desempenho. Símbolos que falharem vão para Errors. benchmark
+// opcional (ex: custom:bancoes) sobrescreve o configurado.
+func (uc *CompareUseCase) Execute(symbols []string, rangeParam, benchmark string, opts StockOptions) (*CompareResult, error) {
+	if benchmark == "" {
+		benchmark = uc.benchmark
+	}
+
+	symbols = uniqueSymbols(symbols)
+	if len(symbols) < 2 {
+		return nil, fmt.Errorf("%w: informe ao menos 2 símbolos", ErrInvalidParameter)
+	}
+	if len(symbols) > maxCompareSymbols {
+		return nil, fmt.Errorf("%w: máximo de %d símbolos", ErrInvalidParameter, maxCompareSymbols)
+	}
+
+	const interval = "1d"
+	stocks, errs := uc.fetchStocks(symbols, rangeParam, interval, opts)
+	if len(stocks) == 0 {
+		// Nenhum símbolo disponível: devolve o erro do primeiro (ex: 404)
+		return nil, errs[symbols[0]]
+	}
+
+	// Beta em relação ao benchmark é opcional
+	var benchPrices []stats.Point
+	bench, err := uc.getStockUC.ExecuteWithOptions(benchmark, rangeParam, interval, opts)
+	if err != nil {
+		log.Printf("⚠️ Benchmark %s indisponível: %v", benchmark, err)
+	} else {
+		benchPrices = stats.Prices(bench.HistoricalDataPrice)
+	}
+
+	now := time.Now().In(calendar.Location)
+	result := &CompareResult{
+		Range:    rangeParam,
+		Interval: interval,
+		Adjust:   opts.Adjust,
+		Symbols:  make([]string, 0, len(stocks)),
+		Table:    make([]CompareRow, 0, len(stocks)),
+		Performance: ComparePerformance{
+			Base:   100,
+			Dates:  []string{},
+			Series: map[string][]*float64{},
+		},
+	}
+	if len(errs) > 0 {
+		result.Errors = make(map[string]string, len(errs))
+		for symbol, err := range errs {
+			result.Errors[symbol] = err.Error()
+		}
+	}
+
+	series := make([][]stats.Point, 0, len(stocks))
+	for _, symbol := range symbols {
+		stock, ok := stocks[symbol]
+		if !ok {
+			continue
+		}
+		prices := stats.Prices(stock.HistoricalDataPrice)
+
+		risk := stats.Compute(prices, interval, uc.riskFreeRate)
+		if benchPrices != nil {
+			risk.WithBenchmark(benchmark, prices, benchPrices, interval)
+		}
+
+		result.Symbols = append(result.Symbols, symbol)
+		result.Table = append(result.Table, compareRow(stock, risk, now))
+		series = append(series, prices)
+		if stock.Adjustment != "" {
+			result.Adjust = stock.Adjustment
+		}
+	}
+
+	dates, values := stats.Rebase(interval, result.Performance.Base, series...)
+	for _, d := range dates {
+		result.Performance.Dates = append(result.Performance.Dates, time.Unix(d, 0).In(calendar.Location).Format("2006-01-02"))
+	}
+	if len(dates) > 0 {
+		result.Performance.BaseDate = result.Performance.Dates[0]
+	}
+	for i, symbol := range result.Symbols {
+		if values == nil {
+			result.Performance.Series[symbol] = []*float64{}
+			continue
+		}
+		result.Performance.Series[symbol] = values[i]
+	}
+
+	return result, nil
+}
+
+func compareRow(stock *domain.Stock, risk stats.Report, now time.Time) CompareRow {
+	summary := dividends.Summarize(stock.DividendsData.CashDividends, stock.RegularMarketPrice, now)
+	fd := stock.FinancialData
+
+	name := stock.LongName
+	if name == "" {
+		name = stock.ShortName
+	}
+
+	row := CompareRow{
+		Symbol:   stock.Symbol,
+		Name:     name,
+		Sector:   stock.SummaryProfile.Sector,
+		Currency: stock.Currency,
+		Quote: CompareQuote{
+			Price:            stock.RegularMarketPrice,
+			Change:           stock.RegularMarketChange,
+			ChangePercent:    stock.RegularMarketChangePercent,
+			Volume:           stock.RegularMarketVolume,
+			MarketCap:        present(float64(stock.MarketCap)),
+			FiftyTwoWeekHigh: present(stock.FiftyTwoWeekHigh),
+			FiftyTwoWeekLow:  present(stock.FiftyTwoWeekLow),
+			Time:             stock.RegularMarketTime,
+		},
+		Fundamentals: CompareFundamentals{
+			EarningsPerShare:  present(stock.EarningsPerShare),
+			SharesOutstanding: present(float64(stock.SharesOutstanding)),
+			TotalRevenue:      present(float64(fd.TotalRevenue)),
+			EBITDA:            present(float64(fd.EBITDA)),
+			TotalDebt:         present(float64(fd.TotalDebt)),
+			TotalCash:         present(float64(fd.TotalCash)),
+			FreeCashflow:      present(float64(fd.FreeCashflow)),
+		},
+		Ratios: map[string]ratios.Ratio{},
+		Dividends: CompareDividends{
+			TTMPerShare: summary.TTMPerShare,
+			TTMYield:    summary.TTMYield,
+			TTMNetYield: summary.TTMNetYield,
+		},
+		Risk:     risk,
+		Degraded: stock.Degraded,
+	}
+
+	for _, r := range ratios.Compute(ratios.FromStock(stock, summary.TTMPerShare)) {
+		row.Ratios[r.Name] = r
+	}
+	return row
+}
+
+func (uc *CompareUseCase) fetchStocks(
+	symbols []string,
+	rangeParam, intervalParam string,
+	opts StockOptions,
+) (map[string]*domain.Stock, map[string]error) {
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		stocks = make(map[string]*domain.Stock, len(symbols))
+		errs   = make(map[string]error)
+		sem    = make(chan struct{}, maxConcurrentFetches)
+	)
+
+	for _, symbol := range symbols {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			stock, err := uc.getStockUC.ExecuteWithOptions(symbol, rangeParam, intervalParam, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[symbol] = err
+				return
+			}
+			stocks[symbol] = stock
+		}(symbol)
+	}
+
+	wg.Wait()
+	return stocks, errs
+}
+
+// present trata zero como dado ausente (null)
+func present(v float64) *float64 {
+	if v == 0 {
+		return nil
+	}
+	return nullable(v)
+}
@@ -0,0 +1,171 @@
+package usecase
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+
+	"cotacoes/internal/analytics/stats"
+)
+
+// Limites da rota /analytics/correlation
+const (
+	maxCorrelationSymbols = 30
+	maxConcurrentFetches  = 5
+)
+
+// CorrelationResult representa a matriz de correlação entre os símbolos.
+// As matrizes são simétricas e seguem a ordem de Symbols; null indica
+// amostra insuficiente.
+type CorrelationResult struct {
+	Range      string            `json:"range"`
+	Interval   string            `json:"interval"`
+	Adjust     string            `json:"adjust,omitempty"`
+	Symbols    []string          `json:"symbols"`
+	Pearson    [][]*float64      `json:"pearson"`
+	Spearman   [][]*float64      `json:"spearman"`
+	SampleSize [][]int           `json:"sampleSize"`
+	Errors     map[string]string `json:"errors,omitempty"`
+}
+
+type CorrelationUseCase struct {
+	getStockUC *GetStockUseCase
+}
+
+func NewCorrelationUseCase(getStockUC *GetStockUseCase) *CorrelationUseCase {
+	return &CorrelationUseCase{getStockUC: getStockUC}
+}
+
+// Execute busca os históricos em paralelo e calcula as correlações dos
+// retornos par a par, cada par sobre as datas que os dois têm em comum
+func (uc *CorrelationUseCase) Execute(
+	symbols []string,
+	rangeParam, intervalParam string,
+	opts StockOptions,
+) (*CorrelationResult, error) {
+
+	symbols = uniqueSymbols(symbols)
+	if len(symbols) < 2 {
+		return nil, fmt.Errorf("%w: informe ao menos 2 símbolos", ErrInvalidParameter)
+	}
+	if len(symbols) > maxCorrelationSymbols {
+		return nil, fmt.Errorf("%w: máximo de %d símbolos", ErrInvalidParameter, maxCorrelationSymbols)
+	}
+
+	prices, errs := uc.fetchPrices(symbols, rangeParam, intervalParam, opts)
+
+	available := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		if _, ok := prices[s]; ok {
+			available = append(available, s)
+		}
+	}
+	if len(available) < 2 {
+		// Sem par para correlacionar: devolve o erro do primeiro símbolo
+		// indisponível (ex: 404)
+		for _, s := range symbols {
+			if err, ok := errs[s]; ok {
+				return nil, fmt.Errorf("correlação exige ao menos 2 séries disponíveis: %s: %w", s, err)
+			}
+		}
+	}
+
+	n := len(available)
+	result := &CorrelationResult{
+		Range:      rangeParam,
+		Interval:   intervalParam,
+		Adjust:     opts.Adjust,
+		Symbols:    available,
+		Pearson:    make([][]*float64, n),
+		Spearman:   make([][]*float64, n),
+		SampleSize: make([][]int, n),
+	}
+	if len(errs) > 0 {
+		result.Errors = make(map[string]string, len(errs))
+		for symbol, err := range errs {
+			result.Errors[symbol] = err.Error()
+		}
+	}
+
+	for i := range available {
+		result.Pearson[i] = make([]*float64, n)
+		result.Spearman[i] = make([]*float64, n)
+		result.SampleSize[i] = make([]int, n)
+	}
+
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			a, b := stats.PairReturns(intervalParam, prices[available[i]], prices[available[j]])
+
+			pearson := nullable(stats.Pearson(a, b))
+			spearman := nullable(stats.Spearman(a, b))
+
+			result.Pearson[i][j], result.Pearson[j][i] = pearson, pearson
+			result.Spearman[i][j], result.Spearman[j][i] = spearman, spearman
+			result.SampleSize[i][j], result.SampleSize[j][i] = len(a), len(a)
+		}
+	}
+
+	return result, nil
+}
+
+func (uc *CorrelationUseCase) fetchPrices(
+	symbols []string,
+	rangeParam, intervalParam string,
+	opts StockOptions,
+) (map[string][]stats.Point, map[string]error) {
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		prices = make(map[string][]stats.Point, len(symbols))
+		errs   = make(map[string]error)
+		sem    = make(chan struct{}, maxConcurrentFetches)
+	)
+
+	for _, symbol := range symbols {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			stock, err := uc.getStockUC.ExecuteWithOptions(symbol, rangeParam, intervalParam, opts)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[symbol] = err
+				return
+			}
+			prices[symbol] = stats.Prices(stock.HistoricalDataPrice)
+		}(symbol)
+	}
+
+	wg.Wait()
+	return prices, errs
+}
+
+// uniqueSymbols normaliza e remove símbolos repetidos mantendo a ordem
+func uniqueSymbols(symbols []string) []string {
+	seen := make(map[string]bool, len(symbols))
+	out := make([]string, 0, len(symbols))
+	for _, s := range symbols {
+		s = strings.ToUpper(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		seen[s] = true
+		out = append(out, s)
+	}
+	return out
+}
+
+// nullable converte NaN/Inf em nil para serializar como null
+func nullable(v float64) *float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
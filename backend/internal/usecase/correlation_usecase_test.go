@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"cotacoes/internal/domain"
+)
+
+// historyRepo devolve um histórico fixo para os símbolos conhecidos e
+// ErrStockNotFound para os demais
+type historyRepo map[string][]float64
+
+func (r historyRepo) GetBySymbol(symbol, rangeParam, intervalParam string) (*domain.Stock, error) {
+	closes, ok := r[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", domain.ErrStockNotFound, symbol)
+	}
+	stock := &domain.Stock{Symbol: symbol}
+	for i, c := range closes {
+		stock.HistoricalDataPrice = append(stock.HistoricalDataPrice, domain.HistoricalDataPrice{
+			Date:  int64(1704196800 + i*86400),
+			Close: c,
+		})
+	}
+	return stock, nil
+}
+
+func TestCorrelationNeedsTwoSeries(t *testing.T) {
+	repo := historyRepo{
+		"PETR4": {10, 11, 10.5, 12, 11.8},
+		"PETR3": {20, 22, 21, 24, 23.6},
+	}
+	uc := NewCorrelationUseCase(NewGetStockUseCase(repo, nil))
+
+	result, err := uc.Execute([]string{"PETR4", "PETR3", "XPTO3"}, "1mo", "1d", StockOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Symbols) != 2 || result.Errors["XPTO3"] == "" {
+		t.Errorf("símbolos = %v, erros = %v", result.Symbols, result.Errors)
+	}
+	if p := result.Pearson[0][1]; p == nil || *p < 0.99 {
+		t.Errorf("pearson = %v, esperado ~1", p)
+	}
+
+	// Só uma série disponível: erro com o status do símbolo que falhou
+	_, err = uc.Execute([]string{"PETR4", "XPTO3"}, "1mo", "1d", StockOptions{})
+	if !errors.Is(err, domain.ErrStockNotFound) {
+		t.Errorf("erro = %v, esperado ErrStockNotFound", err)
+	}
+}
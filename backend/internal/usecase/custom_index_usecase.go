@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"cotacoes/internal/analytics/adjust"
+	"cotacoes/internal/analytics/customindex"
+	"cotacoes/internal/analytics/resample"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+	"cotacoes/internal/provider/indexes"
+)
+
+var nonSlug = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CustomIndexResult representa a resposta da rota /indexes/custom/:id:
+// o índice no mesmo formato de /stocks/:symbol, com a definição e os
+// rebalanceamentos do período
+type CustomIndexResult struct {
+	*domain.Stock
+	Index      domain.CustomIndex      `json:"index"`
+	Rebalances []customindex.Rebalance `json:"rebalances"`
+	Warnings   []string                `json:"warnings,omitempty"`
+}
+
+type CustomIndexUseCase struct {
+	indexes    domain.CustomIndexRepository
+	stockRepo  *indexes.StockRepo
+	getStockUC *GetStockUseCase
+}
+
+func NewCustomIndexUseCase(
+	indexRepo domain.CustomIndexRepository,
+	stockRepo *indexes.StockRepo,
+	getStockUC *GetStockUseCase,
+) *CustomIndexUseCase {
+	return &CustomIndexUseCase{
+		indexes:    indexRepo,
+		stockRepo:  stockRepo,
+		getStockUC: getStockUC,
+	}
+}
+
+// List retorna as definições dos índices do usuário
+func (uc *CustomIndexUseCase) List(owner string) ([]domain.CustomIndex, error) {
+	return uc.indexes.List(owner)
+}
+
+// Create valida e salva um novo índice do usuário; o id vem do nome e é
+// único entre todos os usuários, pois também é o símbolo do índice
+func (uc *CustomIndexUseCase) Create(owner string, idx domain.CustomIndex) (*domain.CustomIndex, error) {
+	if err := uc.validate(&idx); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	idx.ID = baseID(idx.Name)
+	idx.Owner = owner
+	idx.CreatedAt, idx.UpdatedAt = now, now
+	id, err := uc.indexes.Create(idx)
+	if err != nil {
+		return nil, err
+	}
+	idx.ID = id
+
+	log.Printf("📊 Índice customizado %q criado com %d ativos", id, len(idx.Constituents))
+	return &idx, nil
+}
+
+// Update substitui a definição de um índice do usuário
+func (uc *CustomIndexUseCase) Update(owner, id string, idx domain.CustomIndex) (*domain.CustomIndex, error) {
+	existing, err := uc.indexes.Get(strings.ToLower(id))
+	if err != nil {
+		return nil, err
+	}
+	if existing.Owner != owner {
+		return nil, domain.ErrIndexNotFound
+	}
+	if err := uc.validate(&idx); err != nil {
+		return nil, err
+	}
+
+	idx.ID = existing.ID
+	idx.Owner = owner
+	idx.CreatedAt = existing.CreatedAt
+	idx.UpdatedAt = time.Now()
+	if err := uc.indexes.Save(idx); err != nil {
+		return nil, err
+	}
+	return &idx, nil
+}
+
+// Delete remove um índice do usuário
+func (uc *CustomIndexUseCase) Delete(owner, id string) error {
+	return uc.indexes.Delete(owner, strings.ToLower(id))
+}
+
+// Execute calcula o histórico do índice no range pedido. Adjust opcional
+// (none, splits ou total) ajusta os ativos antes do cálculo; resample
+// opcional agrega os níveis em 1wk, 1mo, 3mo ou 1y.
+func (uc *CustomIndexUseCase) Execute(id, rangeParam, intervalParam, adjustParam, resamplePeriod string) (*CustomIndexResult, error) {
+	if rangeParam == "" {
+		rangeParam = "1y"
+	}
+	if intervalParam == "" {
+		intervalParam = "1d"
+	}
+	if _, err := domain.RangeStart(rangeParam, time.Now()); err != nil {
+		return nil, err
+	}
+	var mode adjust.Mode
+	if adjustParam != "" {
+		m, err := adjust.ParseMode(adjustParam)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+		mode = m
+	}
+	if resamplePeriod != "" {
+		if err := resample.Validate(resamplePeriod); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+	}
+
+	id = strings.ToLower(id)
+	stock, result, err := uc.stockRepo.Build(id, rangeParam, intervalParam, mode)
+	if err != nil {
+		return nil, err
+	}
+	idx, err := uc.indexes.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if resamplePeriod != "" {
+		stock.HistoricalDataPrice, err = resample.Resample(stock.HistoricalDataPrice, resamplePeriod, calendar.Default)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+		stock.UsedInterval = resamplePeriod
+	}
+
+	return &CustomIndexResult{
+		Stock:      stock,
+		Index:      *idx,
+		Rebalances: result.Rebalances,
+		Warnings:   result.Warnings,
+	}, nil
+}
+
+// validate normaliza a definição e confere se os ativos existem
+func (uc *CustomIndexUseCase) validate(idx *domain.CustomIndex) error {
+	if err := customindex.Normalize(idx); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	var unknown []string
+	for _, c := range idx.Constituents {
+		if _, err := uc.getStockUC.Execute(c.Symbol, "1d", "1d"); err != nil {
+			if errors.Is(err, domain.ErrStockNotFound) {
+				unknown = append(unknown, c.Symbol)
+				continue
+			}
+			// Provider indisponível não impede salvar o índice
+			log.Printf("⚠️ Não foi possível validar %s: %v", c.Symbol, err)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("%w: ativos não encontrados: %s", ErrInvalidParameter, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// baseID gera o id a partir do nome ("Bancões" → "bancoes"); o
+// repositório acrescenta um sufixo numérico quando já existir
+func baseID(name string) string {
+	if base := slug(name); base != "" {
+		return base
+	}
+	return "indice"
+}
+
+func slug(s string) string {
+	replacer := strings.NewReplacer(
+		"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+		"é", "e", "ê", "e", "è", "e", "ë", "e",
+		"í", "i", "î", "i", "ì", "i", "ï", "i",
+		"ó", "o", "ô", "o", "õ", "o", "ò", "o", "ö", "o",
+		"ú", "u", "û", "u", "ù", "u", "ü", "u",
+		"ç", "c", "ñ", "n",
+	)
+	s = replacer.Replace(strings.ToLower(strings.TrimSpace(s)))
+	s = nonSlug.ReplaceAllString(s, "-")
+	s = strings.Trim(s, "-")
+	if len(s) > 48 {
+		s = strings.TrimRight(s[:48], "-")
+	}
+	return s
+}
@@ -0,0 +1,219 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"cotacoes/internal/analytics/dividends"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// DividendSummaryResult representa a resposta da rota /stocks/:symbol/dividends/summary
+type DividendSummaryResult struct {
+	Symbol string `json:"symbol"`
+	dividends.Summary
+}
+
+type GetDividendSummaryUseCase struct {
+	getStockUC *GetStockUseCase
+}
+
+func NewGetDividendSummaryUseCase(getStockUC *GetStockUseCase) *GetDividendSummaryUseCase {
+	return &GetDividendSummaryUseCase{getStockUC: getStockUC}
+}
+
+// Execute resume os proventos em dinheiro da ação
+func (uc *GetDividendSummaryUseCase) Execute(symbol string) (*DividendSummaryResult, error) {
+	stock, err := uc.getStockUC.Execute(symbol, "1d", "1d")
+	if err != nil {
+		return nil, err
+	}
+
+	return &DividendSummaryResult{
+		Symbol:  stock.Symbol,
+		Summary: dividends.Summarize(stock.DividendsData.CashDividends, stock.RegularMarketPrice, time.Now()),
+	}, nil
+}
+
+// DividendCalendarResult representa a resposta da rota /dividends/calendar
+type DividendCalendarResult struct {
+	From      string                    `json:"from"`
+	To        string                    `json:"to"`
+	Universe  int                       `json:"universe"`
+	UpdatedAt time.Time                 `json:"updatedAt"`
+	Events    []dividends.CalendarEvent `json:"events"`
+}
+
+// DividendCalendarUseCase monta o calendário de proventos do universo de
+// ações (as universeSize mais negociadas por volume, ou todas com 0).
+// Buscar os proventos de cada ação custa uma chamada ao provider, então os
+// eventos ficam em memória por "ttl" e são renovados fora do lock:
+// enquanto isso, as requisições recebem o calendário anterior.
+type DividendCalendarUseCase struct {
+	universe     *MarketUniverse
+	getStockUC   *GetStockUseCase
+	universeSize int
+	ttl          time.Duration
+
+	mu         sync.Mutex
+	events     []dividends.CalendarEvent
+	size       int
+	updatedAt  time.Time
+	refreshing chan struct{} // fechado ao fim da renovação em andamento
+	err        error         // erro da última renovação
+}
+
+func NewDividendCalendarUseCase(
+	universe *MarketUniverse,
+	getStockUC *GetStockUseCase,
+	universeSize int,
+	ttl time.Duration,
+) *DividendCalendarUseCase {
+	return &DividendCalendarUseCase{
+		universe:     universe,
+		getStockUC:   getStockUC,
+		universeSize: universeSize,
+		ttl:          ttl,
+	}
+}
+
+// Execute retorna os eventos com data ex ou de pagamento entre from e to
+// (AAAA-MM-DD). Sem datas, usa os próximos 30 dias.
+func (uc *DividendCalendarUseCase) Execute(fromParam, toParam string) (*DividendCalendarResult, error) {
+	today := calendar.StartOfDay(time.Now())
+
+	from, err := parseDateParam(fromParam, today)
+	if err != nil {
+		return nil, err
+	}
+	to, err := parseDateParam(toParam, from.AddDate(0, 0, 30))
+	if err != nil {
+		return nil, err
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: to anterior a from", ErrInvalidParameter)
+	}
+
+	events, universe, updatedAt, err := uc.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &DividendCalendarResult{
+		From:      from.Format("2006-01-02"),
+		To:        to.Format("2006-01-02"),
+		Universe:  universe,
+		UpdatedAt: updatedAt,
+		Events:    dividends.Window(events, from, to.Add(24*time.Hour-time.Second)),
+	}, nil
+}
+
+// load devolve o calendário em cache ou o renova. Só uma renovação roda
+// por vez; sem calendário anterior, as requisições concorrentes aguardam
+// o resultado dela.
+func (uc *DividendCalendarUseCase) load() ([]dividends.CalendarEvent, int, time.Time, error) {
+	uc.mu.Lock()
+	if uc.events != nil && time.Since(uc.updatedAt) < uc.ttl {
+		defer uc.mu.Unlock()
+		return uc.events, uc.size, uc.updatedAt, nil
+	}
+
+	if wait := uc.refreshing; wait != nil {
+		if uc.events == nil {
+			uc.mu.Unlock()
+			<-wait
+			uc.mu.Lock()
+		}
+		defer uc.mu.Unlock()
+		if uc.events == nil {
+			return nil, 0, time.Time{}, uc.err
+		}
+		return uc.events, uc.size, uc.updatedAt, nil
+	}
+
+	done := make(chan struct{})
+	uc.refreshing = done
+	uc.mu.Unlock()
+
+	events, size, err := uc.fetch()
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.refreshing = nil
+	uc.err = err
+	close(done)
+
+	if err != nil {
+		if uc.events != nil {
+			log.Printf("⚠️ Erro ao atualizar calendário de proventos, usando cache: %v", err)
+			return uc.events, uc.size, uc.updatedAt, nil
+		}
+		return nil, 0, time.Time{}, err
+	}
+
+	uc.events = events
+	uc.size = size
+	uc.updatedAt = time.Now()
+	log.Printf("📅 Calendário de proventos atualizado: %d eventos de %d ações", len(events), size)
+
+	return uc.events, uc.size, uc.updatedAt, nil
+}
+
+// fetch busca os proventos das ações do universo, ordenadas por volume
+// localmente (a listagem do provider não garante a ordenação)
+func (uc *DividendCalendarUseCase) fetch() ([]dividends.CalendarEvent, int, error) {
+	snapshot, err := uc.universe.Load()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	stocks := make([]domain.StockListItem, len(snapshot.Stocks))
+	copy(stocks, snapshot.Stocks)
+	sort.SliceStable(stocks, func(i, j int) bool { return stocks[i].Volume > stocks[j].Volume })
+	if uc.universeSize > 0 && len(stocks) > uc.universeSize {
+		stocks = stocks[:uc.universeSize]
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		events = make([]dividends.CalendarEvent, 0)
+		sem    = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, item := range stocks {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			stock, err := uc.getStockUC.Execute(symbol, "1d", "1d")
+			if err != nil {
+				log.Printf("⚠️ Proventos de %s indisponíveis: %v", symbol, err)
+				return
+			}
+
+			mu.Lock()
+			events = append(events, dividends.Events(stock.Symbol, stock.DividendsData.CashDividends)...)
+			mu.Unlock()
+		}(item.Stock)
+	}
+	wg.Wait()
+
+	return events, len(stocks), nil
+}
+
+func parseDateParam(value string, fallback time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", value, calendar.Location)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: data inválida %q (use AAAA-MM-DD)", ErrInvalidParameter, value)
+	}
+	return t, nil
+}
@@ -0,0 +1,132 @@
+package usecase
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// fakeListing devolve a listagem fora da ordem de volume
+type fakeListing struct{}
+
+func (fakeListing) ListAllStocks(sector, stockType *string, page, perPage int) (*domain.AllStocksResponse, error) {
+	return &domain.AllStocksResponse{Stocks: []domain.StockListItem{
+		{Stock: "LOW3", Volume: 10},
+		{Stock: "TOP3", Volume: 1000},
+		{Stock: "MID3", Volume: 500},
+	}}, nil
+}
+
+// fakeStocks conta as buscas por símbolo; com block, cada busca espera
+// até o canal ser fechado
+type fakeStocks struct {
+	mu      sync.Mutex
+	calls   map[string]int
+	started chan struct{}
+	block   chan struct{}
+}
+
+func (f *fakeStocks) GetBySymbol(symbol, rangeParam, intervalParam string) (*domain.Stock, error) {
+	f.mu.Lock()
+	f.calls[symbol]++
+	block, started := f.block, f.started
+	f.mu.Unlock()
+
+	if block != nil {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-block
+	}
+	return &domain.Stock{Symbol: symbol}, nil
+}
+
+func newCalendarTest(size int, ttl time.Duration) (*DividendCalendarUseCase, *fakeStocks) {
+	stocks := &fakeStocks{calls: map[string]int{}}
+	uc := NewDividendCalendarUseCase(
+		NewMarketUniverse(fakeListing{}, time.Hour),
+		NewGetStockUseCase(stocks, nil),
+		size,
+		ttl,
+	)
+	return uc, stocks
+}
+
+func TestDividendCalendarUniverse(t *testing.T) {
+	uc, stocks := newCalendarTest(2, time.Hour)
+
+	result, err := uc.Execute("2024-03-01", "2024-03-31")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Universe != 2 {
+		t.Errorf("universe = %d, esperado 2", result.Universe)
+	}
+	if stocks.calls["TOP3"] != 1 || stocks.calls["MID3"] != 1 || stocks.calls["LOW3"] != 0 {
+		t.Errorf("buscas = %v, esperado só as 2 de maior volume", stocks.calls)
+	}
+
+	// Sem limite, o universo inteiro
+	uc, _ = newCalendarTest(0, time.Hour)
+	if result, _ = uc.Execute("", ""); result.Universe != 3 {
+		t.Errorf("universe = %d, esperado 3", result.Universe)
+	}
+}
+
+func TestDividendCalendarSingleRefresh(t *testing.T) {
+	uc, stocks := newCalendarTest(0, time.Hour)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := uc.Execute("", ""); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for symbol, n := range stocks.calls {
+		if n != 1 {
+			t.Errorf("%s buscado %d vezes, esperado 1", symbol, n)
+		}
+	}
+}
+
+func TestDividendCalendarServesStaleWhileRefreshing(t *testing.T) {
+	uc, stocks := newCalendarTest(0, 0)
+
+	first, err := uc.Execute("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A próxima renovação fica presa no provider
+	stocks.mu.Lock()
+	stocks.started = make(chan struct{}, 1)
+	stocks.block = make(chan struct{})
+	stocks.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		uc.Execute("", "")
+	}()
+	<-stocks.started
+
+	stale, err := uc.Execute("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !stale.UpdatedAt.Equal(first.UpdatedAt) {
+		t.Error("esperado o calendário anterior durante a renovação")
+	}
+
+	close(stocks.block)
+	<-done
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import "cotacoes/internal/domain"
+
+// HealthStatus representa a resposta da rota /health
+type HealthStatus struct {
+	Status    string                  `json:"status"`
+	Providers []domain.ProviderHealth `json:"providers"`
+	Chains    []domain.ChainStatus    `json:"chains"`
+}
+
+type GetHealthUseCase struct {
+	reporters []domain.HealthReporter
+	chains    []domain.ChainReporter
+}
+
+func NewGetHealthUseCase(
+	reporters []domain.HealthReporter,
+	chains []domain.ChainReporter,
+) *GetHealthUseCase {
+	return &GetHealthUseCase{
+		reporters: reporters,
+		chains:    chains,
+	}
+}
+
+// Execute retorna "ok" quando todos os breakers estão fechados e
+// "degraded" quando algum provider está aberto ou em teste
+func (uc *GetHealthUseCase) Execute() HealthStatus {
+	status := HealthStatus{
+		Status:    "ok",
+		Providers: make([]domain.ProviderHealth, 0, len(uc.reporters)),
+		Chains:    make([]domain.ChainStatus, 0, len(uc.chains)),
+	}
+
+	for _, r := range uc.reporters {
+		h := r.Health()
+		if h.State != "closed" {
+			status.Status = "degraded"
+		}
+		status.Providers = append(status.Providers, h)
+	}
+
+	for _, c := range uc.chains {
+		status.Chains = append(status.Chains, c.ChainStatus())
+	}
+
+	return status
+}
@@ -0,0 +1,49 @@
+package usecase
+
+import (
+	"fmt"
+
+	"cotacoes/internal/analytics/indicators"
+)
+
+// IndicatorsResult representa a resposta da rota /stocks/:symbol/indicators
+type IndicatorsResult struct {
+	Symbol   string `json:"symbol"`
+	Range    string `json:"range"`
+	Interval string `json:"interval"`
+	Adjust   string `json:"adjust,omitempty"`
+	indicators.Result
+}
+
+type GetIndicatorsUseCase struct {
+	getStockUC *GetStockUseCase
+}
+
+func NewGetIndicatorsUseCase(getStockUC *GetStockUseCase) *GetIndicatorsUseCase {
+	return &GetIndicatorsUseCase{getStockUC: getStockUC}
+}
+
+// Execute calcula os indicadores pedidos em "set" sobre o histórico da ação
+func (uc *GetIndicatorsUseCase) Execute(
+	symbol, rangeParam, intervalParam, set string,
+	opts StockOptions,
+) (*IndicatorsResult, error) {
+
+	specs, err := indicators.ParseSet(set)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	stock, err := uc.getStockUC.ExecuteWithOptions(symbol, rangeParam, intervalParam, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &IndicatorsResult{
+		Symbol:   stock.Symbol,
+		Range:    stock.UsedRange,
+		Interval: stock.UsedInterval,
+		Adjust:   stock.Adjustment,
+		Result:   indicators.Compute(stock.HistoricalDataPrice, specs),
+	}, nil
+}
@@ -0,0 +1,41 @@
+package usecase
+
+import (
+	"time"
+
+	"cotacoes/internal/analytics/dividends"
+	"cotacoes/internal/analytics/ratios"
+)
+
+// RatiosResult representa a resposta da rota /stocks/:symbol/ratios
+type RatiosResult struct {
+	Symbol   string         `json:"symbol"`
+	Price    float64        `json:"price"`
+	Currency string         `json:"currency"`
+	Ratios   []ratios.Ratio `json:"ratios"`
+}
+
+type GetRatiosUseCase struct {
+	getStockUC *GetStockUseCase
+}
+
+func NewGetRatiosUseCase(getStockUC *GetStockUseCase) *GetRatiosUseCase {
+	return &GetRatiosUseCase{getStockUC: getStockUC}
+}
+
+// Execute calcula os indicadores fundamentalistas da ação
+func (uc *GetRatiosUseCase) Execute(symbol string) (*RatiosResult, error) {
+	stock, err := uc.getStockUC.Execute(symbol, "1d", "1d")
+	if err != nil {
+		return nil, err
+	}
+
+	summary := dividends.Summarize(stock.DividendsData.CashDividends, stock.RegularMarketPrice, time.Now())
+
+	return &RatiosResult{
+		Symbol:   stock.Symbol,
+		Price:    stock.RegularMarketPrice,
+		Currency: stock.Currency,
+		Ratios:   ratios.Compute(ratios.FromStock(stock, summary.TTMPerShare)),
+	}, nil
+}
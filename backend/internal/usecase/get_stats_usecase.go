@@ -0,0 +1,79 @@
+package usecase
+
+import (
+	"log"
+	"strings"
+
+	"cotacoes/internal/analytics/stats"
+)
+
+// StatsResult representa a resposta da rota /stocks/:symbol/stats
+type StatsResult struct {
+	Symbol   string `json:"symbol"`
+	Range    string `json:"range"`
+	Interval string `json:"interval"`
+	Adjust   string `json:"adjust,omitempty"`
+	stats.Report
+}
+
+type GetStatsUseCase struct {
+	getStockUC   *GetStockUseCase
+	benchmark    string
+	riskFreeRate float64
+}
+
+func NewGetStatsUseCase(
+	getStockUC *GetStockUseCase,
+	benchmark string,
+	riskFreeRate float64,
+) *GetStatsUseCase {
+	return &GetStatsUseCase{
+		getStockUC:   getStockUC,
+		benchmark:    benchmark,
+		riskFreeRate: riskFreeRate,
+	}
+}
+
+// Execute calcula retorno e risco da ação no range pedido.
+// riskFreeRate e benchmark opcionais sobrescrevem os valores configurados.
+func (uc *GetStatsUseCase) Execute(
+	symbol, rangeParam, intervalParam string,
+	riskFreeRate *float64,
+	benchmark string,
+	opts StockOptions,
+) (*StatsResult, error) {
+
+	rf := uc.riskFreeRate
+	if riskFreeRate != nil {
+		rf = *riskFreeRate
+	}
+	if benchmark == "" {
+		benchmark = uc.benchmark
+	}
+
+	stock, err := uc.getStockUC.ExecuteWithOptions(symbol, rangeParam, intervalParam, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	prices := stats.Prices(stock.HistoricalDataPrice)
+	report := stats.Compute(prices, stock.UsedInterval, rf)
+
+	// Beta/correlação são opcionais: sem benchmark o resto continua válido
+	if !strings.EqualFold(benchmark, stock.Symbol) {
+		bench, err := uc.getStockUC.ExecuteWithOptions(benchmark, rangeParam, intervalParam, opts)
+		if err != nil {
+			log.Printf("⚠️ Benchmark %s indisponível: %v", benchmark, err)
+		} else {
+			report.WithBenchmark(benchmark, prices, stats.Prices(bench.HistoricalDataPrice), stock.UsedInterval)
+		}
+	}
+
+	return &StatsResult{
+		Symbol:   stock.Symbol,
+		Range:    stock.UsedRange,
+		Interval: stock.UsedInterval,
+		Adjust:   stock.Adjustment,
+		Report:   report,
+	}, nil
+}
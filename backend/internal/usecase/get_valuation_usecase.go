@@ -0,0 +1,53 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"cotacoes/internal/analytics/valuation"
+	"cotacoes/internal/calendar"
+)
+
+// ValuationResult representa a resposta da rota /stocks/:symbol/valuation
+type ValuationResult struct {
+	Symbol      string                `json:"symbol"`
+	Price       float64               `json:"price"`
+	Currency    string                `json:"currency"`
+	Assumptions valuation.Assumptions `json:"assumptions"`
+	Models      []valuation.Model     `json:"models"`
+}
+
+type GetValuationUseCase struct {
+	getStockUC *GetStockUseCase
+}
+
+func NewGetValuationUseCase(getStockUC *GetStockUseCase) *GetValuationUseCase {
+	return &GetValuationUseCase{getStockUC: getStockUC}
+}
+
+// Execute calcula o preço justo da ação pelos modelos de valuation.
+// overrides sobrescreve as premissas padrão pelo nome (valuation.Params).
+func (uc *GetValuationUseCase) Execute(symbol string, overrides map[string]float64) (*ValuationResult, error) {
+	assumptions := valuation.DefaultAssumptions()
+	for name, v := range overrides {
+		if err := assumptions.Set(name, v); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+	}
+	if err := assumptions.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	stock, err := uc.getStockUC.Execute(symbol, "1d", "1d")
+	if err != nil {
+		return nil, err
+	}
+
+	return &ValuationResult{
+		Symbol:      stock.Symbol,
+		Price:       stock.RegularMarketPrice,
+		Currency:    stock.Currency,
+		Assumptions: assumptions,
+		Models:      valuation.Compute(valuation.FromStock(stock), assumptions, time.Now().In(calendar.Location)),
+	}, nil
+}
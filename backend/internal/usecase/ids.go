@@ -0,0 +1,13 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// newID gera um identificador aleatório (12 caracteres hexadecimais)
+func newID() string {
+	b := make([]byte, 6)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"fmt"
+	"time"
+
+	"cotacoes/internal/analytics/market"
+)
+
+// MarketHeatmapResult representa a resposta da rota /market/heatmap
+type MarketHeatmapResult struct {
+	market.HeatmapOptions
+	Type      string              `json:"type,omitempty"`
+	Excluded  int                 `json:"excluded"`
+	UpdatedAt time.Time           `json:"updatedAt"`
+	Degraded  bool                `json:"degraded,omitempty"`
+	Tree      *market.HeatmapNode `json:"tree"`
+}
+
+// MarketHeatmapUseCase monta a árvore do heatmap a partir do universo em
+// cache
+type MarketHeatmapUseCase struct {
+	universe *MarketUniverse
+}
+
+func NewMarketHeatmapUseCase(universe *MarketUniverse) *MarketHeatmapUseCase {
+	return &MarketHeatmapUseCase{universe: universe}
+}
+
+// Execute monta o heatmap; stockType opcional filtra o tipo de ativo
+func (uc *MarketHeatmapUseCase) Execute(opts market.HeatmapOptions, stockType string) (*MarketHeatmapResult, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	snapshot, err := uc.universe.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	tree, excluded := market.Heatmap(filterByType(snapshot.Stocks, stockType), opts)
+
+	return &MarketHeatmapResult{
+		HeatmapOptions: opts,
+		Type:           stockType,
+		Excluded:       excluded,
+		UpdatedAt:      snapshot.UpdatedAt,
+		Degraded:       snapshot.Degraded,
+		Tree:           tree,
+	}, nil
+}
@@ -0,0 +1,193 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"cotacoes/internal/analytics/market"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+const (
+	defaultMoversLimit = 10
+	maxMoversLimit     = 100
+
+	// Pregões mínimos no histórico local para considerar a faixa de 52 semanas
+	minRangeSessions = 200
+)
+
+// MarketMoversResult representa a resposta da rota /market/movers
+type MarketMoversResult struct {
+	Type      string         `json:"type"`
+	Limit     int            `json:"limit"`
+	Sector    string         `json:"sector,omitempty"`
+	AssetType string         `json:"assetType,omitempty"`
+	MinVolume int64          `json:"minVolume"`
+	Ignored   int            `json:"ignored"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+	Degraded  bool           `json:"degraded,omitempty"`
+	Movers    []market.Mover `json:"movers"`
+	Breadth   market.Breadth `json:"breadth"`
+	// RangeNote avisa quando newHighs/newLows não cobrem todos os ativos
+	RangeNote string `json:"rangeNote,omitempty"`
+}
+
+// MarketMoversUseCase lista maiores altas, baixas e mais negociados e mede
+// a amplitude do mercado sobre o universo em cache. Ativos com volume
+// abaixo de minVolume são ignorados. As faixas de 52 semanas vêm do
+// histórico local e são calculadas uma vez por pregão.
+type MarketMoversUseCase struct {
+	universe  *MarketUniverse
+	history   domain.HistoryStore
+	minVolume int64
+
+	mu       sync.Mutex
+	rangeDay time.Time
+	ranges   map[string]*market.Range // nil: histórico insuficiente
+}
+
+func NewMarketMoversUseCase(
+	universe *MarketUniverse,
+	history domain.HistoryStore,
+	minVolume int64,
+) *MarketMoversUseCase {
+	return &MarketMoversUseCase{
+		universe:  universe,
+		history:   history,
+		minVolume: minVolume,
+	}
+}
+
+// Execute monta a lista pedida e a amplitude. sector e assetType filtram
+// o universo; minVolume opcional sobrescreve o volume mínimo configurado.
+func (uc *MarketMoversUseCase) Execute(
+	kind string,
+	limit int,
+	sector, assetType string,
+	minVolume *int64,
+) (*MarketMoversResult, error) {
+
+	kind, err := market.ParseMoversKind(kind)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	switch {
+	case limit == 0:
+		limit = defaultMoversLimit
+	case limit < 0 || limit > maxMoversLimit:
+		return nil, fmt.Errorf("%w: limit deve estar entre 1 e %d", ErrInvalidParameter, maxMoversLimit)
+	}
+
+	threshold := uc.minVolume
+	if minVolume != nil {
+		if *minVolume < 0 {
+			return nil, fmt.Errorf("%w: minVolume não pode ser negativo", ErrInvalidParameter)
+		}
+		threshold = *minVolume
+	}
+
+	snapshot, err := uc.universe.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]domain.StockListItem, 0, len(snapshot.Stocks))
+	ignored := 0
+	for _, item := range filterByType(snapshot.Stocks, assetType) {
+		if sector != "" && !strings.EqualFold(market.SectorOf(item), sector) {
+			continue
+		}
+		if item.Volume < threshold {
+			ignored++
+			continue
+		}
+		items = append(items, item)
+	}
+
+	breadth := market.ComputeBreadth(items, uc.ranges52w(items))
+	var rangeNote string
+	if breadth.RangeCoverage < breadth.Total {
+		rangeNote = fmt.Sprintf(
+			"newHighs e newLows consideram apenas %d de %d ativos: a faixa de 52 semanas exige ao menos %d pregões no histórico local",
+			breadth.RangeCoverage, breadth.Total, minRangeSessions)
+	}
+
+	return &MarketMoversResult{
+		Type:      kind,
+		Limit:     limit,
+		Sector:    sector,
+		AssetType: assetType,
+		MinVolume: threshold,
+		Ignored:   ignored,
+		UpdatedAt: snapshot.UpdatedAt,
+		Degraded:  snapshot.Degraded,
+		Movers:    market.Movers(items, kind, limit),
+		Breadth:   breadth,
+		RangeNote: rangeNote,
+	}, nil
+}
+
+// ranges52w retorna a faixa de 52 semanas (sem o pregão atual) dos ativos
+// com histórico local suficiente. Em uma instalação nova o histórico local
+// ainda não cobre 200 pregões e nenhum ativo tem faixa (rangeCoverage 0).
+func (uc *MarketMoversUseCase) ranges52w(items []domain.StockListItem) map[string]market.Range {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	today := calendar.StartOfDay(time.Now())
+	if !uc.rangeDay.Equal(today) {
+		uc.rangeDay = today
+		uc.ranges = make(map[string]*market.Range)
+	}
+
+	computed := 0
+	out := make(map[string]market.Range, len(items))
+	for _, item := range items {
+		r, ok := uc.ranges[item.Stock]
+		if !ok {
+			r = uc.loadRange(item.Stock, today)
+			uc.ranges[item.Stock] = r
+			computed++
+		}
+		if r != nil {
+			out[item.Stock] = *r
+		}
+	}
+
+	if computed > 0 {
+		log.Printf("📈 Faixas de 52 semanas calculadas para %d ativos (%d com histórico)", computed, len(out))
+	}
+	return out
+}
+
+func (uc *MarketMoversUseCase) loadRange(symbol string, today time.Time) *market.Range {
+	candles, err := uc.history.Range(symbol, "1d", today.AddDate(-1, 0, 0), today.Add(-time.Second))
+	if err != nil || len(candles) < minRangeSessions {
+		return nil
+	}
+
+	r := market.Range{}
+	for _, c := range candles {
+		high, low := c.High, c.Low
+		if high <= 0 || low <= 0 {
+			high, low = c.Close, c.Close
+		}
+		if low <= 0 {
+			continue
+		}
+		if r.High == 0 || high > r.High {
+			r.High = high
+		}
+		if r.Low == 0 || low < r.Low {
+			r.Low = low
+		}
+	}
+	if r.High == 0 {
+		return nil
+	}
+	return &r
+}
@@ -0,0 +1,72 @@
+package usecase
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// MarketUniverse guarda em memória a listagem completa de ações
+// (StockListItem), compartilhada pelas rotas que agregam o mercado inteiro
+// (screener, setores, maiores altas, heatmap). A listagem é renovada a
+// cada "ttl"; se a renovação falhar, a última listagem continua valendo.
+type MarketUniverse struct {
+	cotacoesRepo domain.CotacoesRepository
+	ttl          time.Duration
+
+	mu       sync.Mutex
+	stocks   []domain.StockListItem
+	degraded bool
+	loadedAt time.Time
+}
+
+// UniverseSnapshot é a listagem do universo em um instante
+type UniverseSnapshot struct {
+	Stocks    []domain.StockListItem
+	UpdatedAt time.Time
+	Degraded  bool
+}
+
+func NewMarketUniverse(cotacoesRepo domain.CotacoesRepository, ttl time.Duration) *MarketUniverse {
+	return &MarketUniverse{
+		cotacoesRepo: cotacoesRepo,
+		ttl:          ttl,
+	}
+}
+
+// Load retorna a listagem atual, renovando-a quando expirada. O slice
+// devolvido é compartilhado e não deve ser alterado.
+func (u *MarketUniverse) Load() (UniverseSnapshot, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if u.stocks != nil && time.Since(u.loadedAt) < u.ttl {
+		return u.snapshot(), nil
+	}
+
+	list, err := u.cotacoesRepo.ListAllStocks(nil, nil, 1, 5000)
+	if err != nil {
+		if u.stocks != nil {
+			log.Printf("⚠️ Erro ao atualizar universo de ações, usando cache: %v", err)
+			return u.snapshot(), nil
+		}
+		return UniverseSnapshot{}, err
+	}
+
+	u.stocks = list.Stocks
+	u.degraded = list.Degraded
+	u.loadedAt = time.Now()
+	log.Printf("🌎 Universo de ações atualizado: %d ativos", len(u.stocks))
+
+	return u.snapshot(), nil
+}
+
+func (u *MarketUniverse) snapshot() UniverseSnapshot {
+	return UniverseSnapshot{
+		Stocks:    u.stocks,
+		UpdatedAt: u.loadedAt,
+		Degraded:  u.degraded,
+	}
+}
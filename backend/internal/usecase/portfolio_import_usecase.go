@@ -0,0 +1,239 @@
+package usecase
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"cotacoes/internal/analytics/portfolio"
+	"cotacoes/internal/domain"
+	"cotacoes/internal/importer"
+)
+
+// Situação de cada linha importada
+const (
+	ImportNew       = "new"       // será importada (simulação)
+	ImportImported  = "imported"  // importada
+	ImportDuplicate = "duplicate" // já existe na carteira
+	ImportSkipped   = "skipped"   // não altera a carteira
+	ImportError     = "error"     // inválida
+)
+
+// ImportRow é o resultado de uma linha do arquivo
+type ImportRow struct {
+	Line        int                 `json:"line"`
+	Status      string              `json:"status"`
+	Transaction *domain.Transaction `json:"transaction,omitempty"`
+	DuplicateOf string              `json:"duplicateOf,omitempty"`
+	Reason      string              `json:"reason,omitempty"`
+}
+
+// ImportResult representa a resposta de /portfolios/:id/import
+type ImportResult struct {
+	ID      string `json:"id"`
+	Format  string `json:"format"`
+	DryRun  bool   `json:"dryRun"`
+	Summary struct {
+		Total      int `json:"total"`
+		New        int `json:"new"`
+		Duplicates int `json:"duplicates"`
+		Skipped    int `json:"skipped"`
+		Errors     int `json:"errors"`
+	} `json:"summary"`
+	Rows []ImportRow `json:"rows"`
+}
+
+// Limites da importação
+const (
+	// maxImportRowChecks limita a validação linha a linha, que reprocessa o
+	// histórico uma vez por lançamento importado
+	maxImportRowChecks = 500
+	// maxImportAttempts limita as tentativas quando a carteira muda
+	// enquanto a importação é preparada
+	maxImportAttempts = 3
+)
+
+// ErrPortfolioChanged indica que a carteira foi alterada por outra
+// requisição durante a importação
+var ErrPortfolioChanged = errors.New("portfolio changed during import")
+
+type PortfolioImportUseCase struct {
+	portfolios domain.PortfolioRepository
+}
+
+func NewPortfolioImportUseCase(portfolios domain.PortfolioRepository) *PortfolioImportUseCase {
+	return &PortfolioImportUseCase{
+		portfolios: portfolios,
+	}
+}
+
+// Execute importa o arquivo na carteira. Linhas iguais a lançamentos já
+// existentes são marcadas como duplicadas (N linhas idênticas no arquivo
+// contra M na carteira geram max(N-M, 0) novas). Linhas que tornariam o
+// histórico inconsistente (ex: venda sem posição) viram erro. Com dryRun
+// nada é gravado.
+func (uc *PortfolioImportUseCase) Execute(
+	owner, id, format string,
+	data []byte,
+	mapping *importer.Mapping,
+	dryRun bool,
+) (*ImportResult, error) {
+
+	parsed, err := importer.Parse(format, data, mapping)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	// A comparação com os lançamentos existentes é feita fora do lock do
+	// repositório; a gravação só acontece se a carteira não mudou nesse
+	// meio tempo, para que importações concorrentes não se sobreponham
+	for attempt := 0; attempt < maxImportAttempts; attempt++ {
+		p, err := uc.portfolios.Get(owner, id)
+		if err != nil {
+			return nil, err
+		}
+		result, transactions, err := planImport(p, format, parsed, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		if dryRun || result.Summary.New == 0 {
+			return result, nil
+		}
+
+		_, err = uc.portfolios.Update(owner, id, func(current *domain.Portfolio) error {
+			if !current.UpdatedAt.Equal(p.UpdatedAt) || len(current.Transactions) != len(p.Transactions) {
+				return ErrPortfolioChanged
+			}
+			current.Transactions = transactions
+			current.UpdatedAt = time.Now()
+			return nil
+		})
+		if errors.Is(err, ErrPortfolioChanged) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		log.Printf("📥 Carteira %s: %d lançamentos importados (%s)", id, result.Summary.New, format)
+		return result, nil
+	}
+	return nil, fmt.Errorf("%w: tente novamente", ErrPortfolioChanged)
+}
+
+// planImport classifica as linhas contra os lançamentos da carteira e
+// devolve o resultado e o histórico com os lançamentos aceitos
+func planImport(
+	p *domain.Portfolio,
+	format string,
+	parsed []importer.Row,
+	dryRun bool,
+) (*ImportResult, []domain.Transaction, error) {
+
+	existing := make(map[string][]string)
+	for _, tx := range p.Transactions {
+		fp := fingerprint(tx)
+		existing[fp] = append(existing[fp], tx.ID)
+	}
+
+	result := &ImportResult{
+		ID:     p.ID,
+		Format: format,
+		DryRun: dryRun,
+		Rows:   make([]ImportRow, len(parsed)),
+	}
+
+	// Classifica as linhas e separa as candidatas a importação
+	seen := make(map[string]int)
+	var candidates []int
+	for i, row := range parsed {
+		out := ImportRow{Line: row.Line, Transaction: row.Transaction}
+		switch {
+		case row.Error != "":
+			out.Status, out.Reason = ImportError, row.Error
+		case row.Skipped != "":
+			out.Status, out.Reason = ImportSkipped, row.Skipped
+		default:
+			fp := fingerprint(*row.Transaction)
+			n := seen[fp]
+			seen[fp]++
+			if n < len(existing[fp]) {
+				out.Status, out.DuplicateOf = ImportDuplicate, existing[fp][n]
+				break
+			}
+			sum := sha1.Sum([]byte(fmt.Sprintf("%s#%d", fp, n)))
+			row.Transaction.IdempotencyKey = "import:" + hex.EncodeToString(sum[:8])
+			out.Status = ImportNew
+			candidates = append(candidates, i)
+		}
+		result.Rows[i] = out
+	}
+
+	// Ordem cronológica (e do arquivo) para que a validação do histórico
+	// aponte a primeira linha problemática
+	sort.SliceStable(candidates, func(a, b int) bool {
+		return parsed[candidates[a]].Transaction.Date < parsed[candidates[b]].Transaction.Date
+	})
+
+	now := time.Now()
+	transactions := p.Transactions[:len(p.Transactions):len(p.Transactions)]
+	accepted := make([]domain.Transaction, 0, len(candidates))
+	for k, i := range candidates {
+		tx := *parsed[i].Transaction
+		tx.ID = newID()
+		tx.CreatedAt = now.Add(time.Duration(k) * time.Microsecond)
+		accepted = append(accepted, tx)
+		result.Rows[i].Transaction = &accepted[len(accepted)-1]
+	}
+
+	// Caminho comum: tudo consistente de uma vez; senão valida linha a
+	// linha, o que só é viável para arquivos pequenos
+	if len(transactions)+len(accepted) > maxPortfolioTransactions {
+		return nil, nil, fmt.Errorf("%w: a importação excede %d lançamentos por carteira", ErrInvalidParameter, maxPortfolioTransactions)
+	}
+	if _, err := portfolio.Replay(append(transactions, accepted...)); err == nil {
+		transactions = append(transactions, accepted...)
+	} else if len(accepted) > maxImportRowChecks {
+		return nil, nil, fmt.Errorf("%w: o arquivo deixa o histórico inconsistente (%v); importe em partes de até %d lançamentos para ver as linhas com erro",
+			ErrInvalidParameter, err, maxImportRowChecks)
+	} else {
+		for k, i := range candidates {
+			next := append(transactions[:len(transactions):len(transactions)], accepted[k])
+			if _, err := portfolio.Replay(next); err != nil {
+				result.Rows[i].Status = ImportError
+				result.Rows[i].Reason = err.Error()
+				continue
+			}
+			transactions = next
+		}
+	}
+
+	for i := range result.Rows {
+		row := &result.Rows[i]
+		switch row.Status {
+		case ImportNew:
+			result.Summary.New++
+			if !dryRun {
+				row.Status = ImportImported
+			}
+		case ImportDuplicate:
+			result.Summary.Duplicates++
+		case ImportSkipped:
+			result.Summary.Skipped++
+		case ImportError:
+			result.Summary.Errors++
+		}
+	}
+	result.Summary.Total = len(result.Rows)
+
+	return result, transactions, nil
+}
+
+// fingerprint identifica o conteúdo do lançamento para detectar duplicatas
+func fingerprint(tx domain.Transaction) string {
+	return fmt.Sprintf("%s|%s|%s|%g|%g|%g|%g|%g",
+		tx.Type, portfolio.NormalizeSymbol(tx.Symbol), tx.Date, tx.Quantity, tx.Price, tx.Fees, tx.Amount, tx.Ratio)
+}
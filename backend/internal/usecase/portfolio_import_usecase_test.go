@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"cotacoes/internal/importer"
+	"cotacoes/internal/infra/storage"
+)
+
+const tradesCSV = "Data do Negócio;Tipo de Movimentação;Código de Negociação;Quantidade;Preço\n" +
+	"05/03/2024;Compra;PETR4;100;38,50\n" +
+	"05/03/2024;Compra;PETR4;100;38,50\n" +
+	"06/03/2024;Compra;VALE3F;1.000;62,00\n" +
+	"07/03/2024;Venda;ITUB4;10;30,00\n"
+
+func newImportTest(t *testing.T) (*PortfolioImportUseCase, string) {
+	t.Helper()
+
+	repo := storage.NewPortfolioFileRepo(filepath.Join(t.TempDir(), "portfolios.json"))
+	p, err := NewPortfolioUseCase(repo, nil, nil).Create("u1", "Teste", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewPortfolioImportUseCase(repo), p.ID
+}
+
+func TestPortfolioImport(t *testing.T) {
+	uc, id := newImportTest(t)
+
+	// Simulação não grava
+	dry, err := uc.Execute("u1", id, importer.FormatB3Trades, []byte(tradesCSV), nil, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dry.Summary.New != 3 || dry.Summary.Errors != 1 {
+		t.Fatalf("simulação: %+v, esperado 3 novas e 1 erro (venda sem posição)", dry.Summary)
+	}
+	if dry.Rows[0].Status != ImportNew || dry.Rows[3].Status != ImportError {
+		t.Errorf("status = %s/%s", dry.Rows[0].Status, dry.Rows[3].Status)
+	}
+
+	first, err := uc.Execute("u1", id, importer.FormatB3Trades, []byte(tradesCSV), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Summary.New != 3 || first.Rows[0].Status != ImportImported {
+		t.Fatalf("importação: %+v", first.Summary)
+	}
+	if tx := first.Rows[2].Transaction; tx.Symbol != "VALE3" || tx.Quantity != 1000 {
+		t.Errorf("fracionário = %+v, esperado 1000 VALE3", tx)
+	}
+
+	// Reimportar o mesmo arquivo: as duas linhas iguais casam com os dois
+	// lançamentos existentes
+	again, err := uc.Execute("u1", id, importer.FormatB3Trades, []byte(tradesCSV), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.Summary.New != 0 || again.Summary.Duplicates != 3 {
+		t.Fatalf("reimportação: %+v, esperado 3 duplicadas", again.Summary)
+	}
+	if again.Rows[0].DuplicateOf == again.Rows[1].DuplicateOf {
+		t.Error("linhas iguais apontam para o mesmo lançamento")
+	}
+
+	// Uma terceira compra igual no arquivo é nova (3 no arquivo, 2 na carteira)
+	extra := tradesCSV + "05/03/2024;Compra;PETR4;100;38,50\n"
+	more, err := uc.Execute("u1", id, importer.FormatB3Trades, []byte(extra), nil, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if more.Summary.New != 1 || more.Summary.Duplicates != 3 || more.Rows[4].Status != ImportImported {
+		t.Errorf("linha extra: %+v", more.Summary)
+	}
+}
+
+func TestPortfolioImportRowCheckLimit(t *testing.T) {
+	uc, id := newImportTest(t)
+
+	// Arquivo grande com uma venda sem posição: rejeitado por inteiro em vez
+	// de reprocessar o histórico uma vez por linha
+	var b strings.Builder
+	b.WriteString("Data do Negócio;Tipo de Movimentação;Código de Negociação;Quantidade;Preço\n")
+	b.WriteString("04/03/2024;Venda;ITUB4;10;30,00\n")
+	for i := 0; i < maxImportRowChecks; i++ {
+		fmt.Fprintf(&b, "05/03/2024;Compra;PETR4;%d;38,50\n", i+1)
+	}
+
+	_, err := uc.Execute("u1", id, importer.FormatB3Trades, []byte(b.String()), nil, true)
+	if !errors.Is(err, ErrInvalidParameter) || !strings.Contains(err.Error(), "ITUB4") {
+		t.Errorf("erro = %v, esperado parâmetro inválido apontando a venda de ITUB4", err)
+	}
+}
+
+func TestPortfolioImportOwner(t *testing.T) {
+	uc, id := newImportTest(t)
+	if _, err := uc.Execute("u2", id, importer.FormatB3Trades, []byte(tradesCSV), nil, false); err == nil {
+		t.Error("esperado erro para carteira de outro usuário")
+	}
+}
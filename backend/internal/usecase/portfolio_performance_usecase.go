@@ -0,0 +1,586 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"cotacoes/internal/analytics/performance"
+	"cotacoes/internal/analytics/portfolio"
+	"cotacoes/internal/analytics/stats"
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+)
+
+// maxPerformanceBenchmarks limita os benchmarks por consulta
+const maxPerformanceBenchmarks = 5
+
+// PerformanceSummary resume a rentabilidade da carteira no período.
+// Retornos em %.
+type PerformanceSummary struct {
+	StartDate  string  `json:"startDate"`
+	EndDate    string  `json:"endDate"`
+	StartValue float64 `json:"startValue"`
+	EndValue   float64 `json:"endValue"`
+	Inflows    float64 `json:"inflows"`
+	Outflows   float64 `json:"outflows"`
+	// Profit é o ganho em reais: valor final + retiradas - inicial - aportes
+	Profit float64 `json:"profit"`
+	// TWR é o retorno ponderado pelo tempo (método de cotas)
+	TWR           *float64 `json:"twr"`
+	TWRAnnualized *float64 `json:"twrAnnualized"`
+	// XIRR é a taxa interna de retorno anual dos fluxos (ponderada pelo dinheiro)
+	XIRR *float64 `json:"xirr"`
+}
+
+// BenchmarkPerformance é o retorno do benchmark no mesmo período. Excess é
+// o TWR da carteira menos o retorno do benchmark, em pontos percentuais.
+type BenchmarkPerformance struct {
+	Name       string   `json:"name"`
+	Label      string   `json:"label"`
+	Return     *float64 `json:"return"`
+	Annualized *float64 `json:"annualized"`
+	Excess     *float64 `json:"excess"`
+	// Estimated indica taxas ainda não publicadas repetindo a última
+	Estimated bool   `json:"estimated,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// PerformanceSeries é o retorno acumulado (%) em cada pregão, com a data
+// base (0%) na primeira posição
+type PerformanceSeries struct {
+	Dates      []string              `json:"dates"`
+	Portfolio  []*float64            `json:"portfolio"`
+	Benchmarks map[string][]*float64 `json:"benchmarks"`
+}
+
+// MonthlyPerformance é o retorno (%) de um mês da carteira e dos benchmarks
+type MonthlyPerformance struct {
+	Month      string              `json:"month"`
+	Portfolio  *float64            `json:"portfolio"`
+	Benchmarks map[string]*float64 `json:"benchmarks"`
+}
+
+// PortfolioPerformance representa a resposta de /portfolios/:id/performance
+type PortfolioPerformance struct {
+	ID         string                 `json:"id"`
+	Name       string                 `json:"name"`
+	Range      string                 `json:"range"`
+	BaseDate   string                 `json:"baseDate"`
+	Summary    PerformanceSummary     `json:"summary"`
+	Benchmarks []BenchmarkPerformance `json:"benchmarks"`
+	Series     PerformanceSeries      `json:"series"`
+	Monthly    []MonthlyPerformance   `json:"monthly"`
+	Warnings   []string               `json:"warnings,omitempty"`
+	Degraded   bool                   `json:"degraded"`
+}
+
+// benchmarkSpec é um benchmark pedido: cdi, ipca+X ou um símbolo
+// (ibov, ^BVSP, BOVA11, custom:<id>)
+type benchmarkSpec struct {
+	Name   string
+	Label  string
+	Kind   string // cdi, ipca ou price
+	Spread float64
+	Symbol string
+}
+
+type PortfolioPerformanceUseCase struct {
+	portfolios domain.PortfolioRepository
+	getStockUC *GetStockUseCase
+	rates      domain.RateProvider
+	index      string
+	benchmarks []string
+}
+
+func NewPortfolioPerformanceUseCase(
+	portfolios domain.PortfolioRepository,
+	getStockUC *GetStockUseCase,
+	rates domain.RateProvider,
+	index string,
+	benchmarks []string,
+) *PortfolioPerformanceUseCase {
+	return &PortfolioPerformanceUseCase{
+		portfolios: portfolios,
+		getStockUC: getStockUC,
+		rates:      rates,
+		index:      index,
+		benchmarks: benchmarks,
+	}
+}
+
+// Execute calcula a rentabilidade da carteira no período (padrão: desde o
+// primeiro lançamento) e a compara com os benchmarks (padrão: os
+// configurados). A carteira é valorizada a cada pregão pelo fechamento
+// não ajustado, pois os desdobramentos já constam dos lançamentos; sem
+// cotação, usa o último preço negociado.
+func (uc *PortfolioPerformanceUseCase) Execute(
+	owner, id, rangeParam string,
+	benchmarks []string,
+) (*PortfolioPerformance, error) {
+
+	if strings.TrimSpace(rangeParam) == "" {
+		rangeParam = "max"
+	}
+	now := time.Now().In(calendar.Location)
+	rangeStart, err := domain.RangeStart(rangeParam, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(benchmarks) == 0 {
+		benchmarks = uc.benchmarks
+	}
+	specs, err := uc.parseBenchmarks(benchmarks)
+	if err != nil {
+		return nil, err
+	}
+
+	p, err := uc.portfolios.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(p.Transactions) == 0 {
+		return nil, fmt.Errorf("%w: carteira sem lançamentos", ErrInvalidParameter)
+	}
+	days, err := portfolio.Days(p.Transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	grid, err := tradingGrid(days, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Primeiro pregão do período e data base (pregão anterior)
+	start := sort.Search(len(grid), func(i int) bool {
+		return !grid[i].Before(calendar.StartOfDay(rangeStart))
+	})
+	if start == len(grid) {
+		start = len(grid) - 1
+	}
+	baseDate := calendar.Default.PreviousTradingDay(grid[start])
+	fetchRange := historyRange(baseDate, now)
+
+	result := &PortfolioPerformance{
+		ID:       p.ID,
+		Name:     p.Name,
+		Range:    rangeParam,
+		BaseDate: baseDate.Format(portfolio.DateLayout),
+		Monthly:  []MonthlyPerformance{},
+	}
+
+	states := replayGrid(grid, days)
+	closes, warnings, degraded := uc.loadCloses(states[max(start-1, 0):], fetchRange)
+	result.Warnings = append(result.Warnings, warnings...)
+	result.Degraded = degraded
+
+	values := valueGrid(grid, states, closes)
+
+	// Valor inicial e fluxos do período
+	startValue := 0.0
+	if start > 0 {
+		startValue = values[start-1]
+	}
+	dates := grid[start:]
+	vals := make([]performance.Valuation, len(dates))
+	for i := range dates {
+		s := states[start+i]
+		vals[i] = performance.Valuation{Date: dates[i], Value: values[start+i], Inflow: s.Inflow, Outflow: s.Outflow}
+	}
+	index := performance.Index(startValue, vals)
+
+	summary := PerformanceSummary{
+		StartDate:  dates[0].Format(portfolio.DateLayout),
+		EndDate:    dates[len(dates)-1].Format(portfolio.DateLayout),
+		StartValue: round2(startValue),
+		EndValue:   round2(vals[len(vals)-1].Value),
+	}
+	flows := make([]performance.Flow, 0, len(vals)+2)
+	if startValue > 0 {
+		flows = append(flows, performance.Flow{Date: baseDate, Amount: -startValue})
+	}
+	for _, v := range vals {
+		summary.Inflows += v.Inflow
+		summary.Outflows += v.Outflow
+		if net := v.Outflow - v.Inflow; net != 0 {
+			flows = append(flows, performance.Flow{Date: v.Date, Amount: net})
+		}
+	}
+	flows = append(flows, performance.Flow{Date: dates[len(dates)-1], Amount: vals[len(vals)-1].Value})
+	summary.Profit = round2(vals[len(vals)-1].Value + summary.Outflows - startValue - summary.Inflows)
+	summary.Inflows = round2(summary.Inflows)
+	summary.Outflows = round2(summary.Outflows)
+
+	twr := performance.Last(index) - 1
+	summary.TWR = percent(twr)
+	summary.TWRAnnualized = percentPtr(performance.Annualize(twr, len(dates)))
+	if rate, err := performance.XIRR(flows); err == nil {
+		summary.XIRR = percent(rate)
+	}
+	result.Summary = summary
+
+	// Benchmarks na mesma grade
+	indexes := uc.benchmarkIndexes(specs, baseDate, dates, fetchRange)
+
+	result.Series = PerformanceSeries{
+		Dates:      make([]string, 0, len(dates)+1),
+		Portfolio:  withBase(performance.Cumulative(index)),
+		Benchmarks: make(map[string][]*float64, len(specs)),
+	}
+	result.Series.Dates = append(result.Series.Dates, result.BaseDate)
+	for _, d := range dates {
+		result.Series.Dates = append(result.Series.Dates, d.Format(portfolio.DateLayout))
+	}
+
+	monthly := map[string]*MonthlyPerformance{}
+	for _, m := range performance.Monthly(dates, index) {
+		row := MonthlyPerformance{Month: m.Month, Portfolio: percentPtr(m.Return), Benchmarks: map[string]*float64{}}
+		result.Monthly = append(result.Monthly, row)
+	}
+	for i := range result.Monthly {
+		monthly[result.Monthly[i].Month] = &result.Monthly[i]
+	}
+
+	result.Benchmarks = make([]BenchmarkPerformance, 0, len(specs))
+	for i, spec := range specs {
+		b := indexes[i]
+		view := BenchmarkPerformance{Name: spec.Name, Label: spec.Label, Estimated: b.estimated}
+		if b.err != nil {
+			view.Error = b.err.Error()
+			result.Benchmarks = append(result.Benchmarks, view)
+			continue
+		}
+		if b.degraded {
+			result.Degraded = true
+		}
+
+		ret := performance.Last(b.index) - 1
+		view.Return = percent(ret)
+		view.Annualized = percentPtr(performance.Annualize(ret, len(dates)))
+		if view.Return != nil && summary.TWR != nil {
+			view.Excess = nullable(round4(*summary.TWR - *view.Return))
+		}
+		if b.estimated {
+			result.Warnings = append(result.Warnings, fmt.Sprintf("%s: taxa do período mais recente ainda não publicada, repetindo a última", spec.Label))
+		}
+		result.Benchmarks = append(result.Benchmarks, view)
+
+		result.Series.Benchmarks[spec.Name] = withBase(performance.Cumulative(b.index))
+		for _, m := range performance.Monthly(dates, b.index) {
+			if row, ok := monthly[m.Month]; ok {
+				row.Benchmarks[spec.Name] = percentPtr(m.Return)
+			}
+		}
+	}
+
+	return result, nil
+}
+
+// parseBenchmarks interpreta os benchmarks pedidos: "cdi", "ipca" ou
+// "ipca+6" (IPCA + 6% a.a.), "ibov" (o benchmark de mercado configurado)
+// e qualquer símbolo, inclusive índices customizados (custom:<id>)
+func (uc *PortfolioPerformanceUseCase) parseBenchmarks(names []string) ([]benchmarkSpec, error) {
+	specs := make([]benchmarkSpec, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, raw := range names {
+		name := strings.TrimSpace(raw)
+		lower := strings.ToLower(name)
+		if name == "" || seen[lower] {
+			continue
+		}
+		seen[lower] = true
+
+		spec := benchmarkSpec{Name: lower}
+		switch {
+		case lower == domain.RateCDI:
+			spec.Kind, spec.Label = domain.RateCDI, "CDI"
+		case strings.HasPrefix(lower, domain.RateIPCA):
+			spread := 0.0
+			if rest := strings.TrimPrefix(lower, domain.RateIPCA); rest != "" {
+				v, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimPrefix(rest, "+"), ",", "."), 64)
+				if err != nil || !strings.HasPrefix(rest, "+") || math.IsNaN(v) || math.IsInf(v, 0) || v < 0 || v > 100 {
+					return nil, fmt.Errorf("%w: benchmark %q (use ipca ou ipca+X, ex: ipca+6)", ErrInvalidParameter, name)
+				}
+				spread = v
+			}
+			spec.Kind, spec.Spread = domain.RateIPCA, spread
+			spec.Label = "IPCA"
+			if spread > 0 {
+				spec.Label = fmt.Sprintf("IPCA + %s%% a.a.", strconv.FormatFloat(spread, 'f', -1, 64))
+			}
+		case lower == "ibov" || lower == "ibovespa":
+			spec.Kind, spec.Symbol, spec.Label = "price", uc.index, "IBOVESPA"
+		case strings.HasPrefix(lower, "custom:"):
+			spec.Kind, spec.Symbol, spec.Label = "price", lower, name
+		default:
+			spec.Name = strings.ToUpper(name)
+			spec.Kind, spec.Symbol, spec.Label = "price", spec.Name, spec.Name
+		}
+		specs = append(specs, spec)
+	}
+	if len(specs) > maxPerformanceBenchmarks {
+		return nil, fmt.Errorf("%w: máximo de %d benchmarks", ErrInvalidParameter, maxPerformanceBenchmarks)
+	}
+	return specs, nil
+}
+
+// benchmarkIndex é o índice acumulado de um benchmark na grade de pregões
+type benchmarkIndex struct {
+	index     []float64
+	estimated bool
+	degraded  bool
+	err       error
+}
+
+// benchmarkIndexes monta os índices dos benchmarks em paralelo
+func (uc *PortfolioPerformanceUseCase) benchmarkIndexes(
+	specs []benchmarkSpec,
+	baseDate time.Time,
+	dates []time.Time,
+	fetchRange string,
+) []benchmarkIndex {
+
+	out := make([]benchmarkIndex, len(specs))
+	end := dates[len(dates)-1]
+
+	var wg sync.WaitGroup
+	for i, spec := range specs {
+		wg.Add(1)
+		go func(i int, spec benchmarkSpec) {
+			defer wg.Done()
+			b := &out[i]
+
+			switch spec.Kind {
+			case domain.RateCDI:
+				points, err := uc.rates.Series(domain.RateCDI, baseDate, end)
+				if err != nil {
+					b.err = err
+					break
+				}
+				b.index, b.estimated = performance.CDI(baseDate, dates, points)
+			case domain.RateIPCA:
+				// O IPCA é datado no primeiro dia do mês de referência
+				from := time.Date(baseDate.Year(), baseDate.Month(), 1, 0, 0, 0, 0, calendar.Location)
+				points, err := uc.rates.Series(domain.RateIPCA, from, end)
+				if err != nil {
+					b.err = err
+					break
+				}
+				b.index, b.estimated = performance.IPCAPlus(baseDate, dates, points, spec.Spread)
+			default:
+				stock, err := uc.getStockUC.Execute(spec.Symbol, fetchRange, "1d")
+				if err != nil {
+					b.err = err
+					break
+				}
+				b.index = performance.Prices(baseDate, dates, stats.Prices(stock.HistoricalDataPrice))
+				b.degraded = stock.Degraded
+			}
+
+			if b.err == nil && math.IsNaN(performance.Last(b.index)) {
+				b.err = fmt.Errorf("sem dados de %s no período", spec.Label)
+			}
+			if b.err != nil {
+				log.Printf("⚠️ Benchmark %s indisponível: %v", spec.Name, b.err)
+			}
+		}(i, spec)
+	}
+	wg.Wait()
+
+	return out
+}
+
+// gridState é a carteira ao fim de um pregão: quantidades, fluxos do dia
+// e o último preço negociado de cada ativo até a data
+type gridState struct {
+	Quantities map[string]float64
+	Inflow     float64
+	Outflow    float64
+	LastTrade  map[string]float64
+}
+
+// tradingGrid lista os pregões do primeiro lançamento até hoje (ou o
+// último pregão)
+func tradingGrid(days []portfolio.Day, now time.Time) ([]time.Time, error) {
+	first, err := time.ParseInLocation(portfolio.DateLayout, days[0].Date, calendar.Location)
+	if err != nil {
+		return nil, err
+	}
+	if !calendar.Default.IsTradingDay(first) {
+		first = calendar.Default.NextTradingDay(first)
+	}
+	end := calendar.StartOfDay(now)
+	if !calendar.Default.IsTradingDay(end) {
+		end = calendar.Default.PreviousTradingDay(end)
+	}
+	if first.After(end) {
+		return nil, fmt.Errorf("%w: nenhum pregão desde o primeiro lançamento", ErrInvalidParameter)
+	}
+
+	var grid []time.Time
+	for d := first; !d.After(end); d = calendar.Default.NextTradingDay(d) {
+		grid = append(grid, d)
+	}
+	return grid, nil
+}
+
+// replayGrid distribui os dias com lançamentos pela grade: lançamentos
+// em dias sem pregão contam no pregão seguinte
+func replayGrid(grid []time.Time, days []portfolio.Day) []gridState {
+	states := make([]gridState, len(grid))
+	quantities := map[string]float64{}
+	lastTrade := map[string]float64{}
+	k := 0
+	for i, date := range grid {
+		key := date.Format(portfolio.DateLayout)
+		var s gridState
+		for ; k < len(days) && days[k].Date <= key; k++ {
+			s.Inflow += days[k].Inflow
+			s.Outflow += days[k].Outflow
+			quantities = days[k].Quantities
+			if len(days[k].TradePrices) > 0 {
+				next := make(map[string]float64, len(lastTrade)+len(days[k].TradePrices))
+				for symbol, price := range lastTrade {
+					next[symbol] = price
+				}
+				for symbol, price := range days[k].TradePrices {
+					next[symbol] = price
+				}
+				lastTrade = next
+			}
+		}
+		s.Quantities = quantities
+		s.LastTrade = lastTrade
+		states[i] = s
+	}
+	return states
+}
+
+// loadCloses busca em paralelo os fechamentos (não ajustados) dos ativos
+// que tiveram posição no período
+func (uc *PortfolioPerformanceUseCase) loadCloses(
+	states []gridState,
+	fetchRange string,
+) (closes map[string][]stats.Point, warnings []string, degraded bool) {
+
+	var symbols []string
+	seen := map[string]bool{}
+	for _, s := range states {
+		for symbol := range s.Quantities {
+			if !seen[symbol] {
+				seen[symbol] = true
+				symbols = append(symbols, symbol)
+			}
+		}
+	}
+	sort.Strings(symbols)
+
+	var (
+		mu  sync.Mutex
+		wg  sync.WaitGroup
+		sem = make(chan struct{}, maxConcurrentFetches)
+	)
+	closes = make(map[string][]stats.Point, len(symbols))
+	for _, symbol := range symbols {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			stock, err := uc.getStockUC.Execute(symbol, fetchRange, "1d")
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				log.Printf("⚠️ Histórico de %s indisponível: %v", symbol, err)
+				warnings = append(warnings, fmt.Sprintf("%s: sem histórico de cotações, valorizado pelo último preço negociado", symbol))
+				degraded = true
+				return
+			}
+			points := make([]stats.Point, 0, len(stock.HistoricalDataPrice))
+			for _, c := range stock.HistoricalDataPrice {
+				if c.Close > 0 {
+					points = append(points, stats.Point{Date: c.Date, Value: c.Close})
+				}
+			}
+			sort.Slice(points, func(i, j int) bool { return points[i].Date < points[j].Date })
+			closes[symbol] = points
+			if stock.Degraded {
+				degraded = true
+			}
+		}(symbol)
+	}
+	wg.Wait()
+
+	sort.Strings(warnings)
+	return closes, warnings, degraded
+}
+
+// valueGrid valoriza a carteira em cada pregão pelo último fechamento até
+// a data ou, sem ele, pelo último preço negociado
+func valueGrid(grid []time.Time, states []gridState, closes map[string][]stats.Point) []float64 {
+	values := make([]float64, len(grid))
+	next := make(map[string]int, len(closes))
+	for i, date := range grid {
+		limit := date.Unix()
+		total := 0.0
+		for symbol, quantity := range states[i].Quantities {
+			price := states[i].LastTrade[symbol]
+			points := closes[symbol]
+			j := next[symbol]
+			for j < len(points) && stats.DateKey(points[j].Date, "1d") <= limit {
+				j++
+			}
+			next[symbol] = j
+			if j > 0 {
+				price = points[j-1].Value
+			}
+			total += quantity * price
+		}
+		values[i] = total
+	}
+	return values
+}
+
+// historyRange escolhe o menor range do histórico que cobre a data
+func historyRange(from, now time.Time) string {
+	for _, r := range []string{"1mo", "3mo", "6mo", "1y", "2y", "5y", "10y"} {
+		if start, err := domain.RangeStart(r, now); err == nil && start.Before(from) {
+			return r
+		}
+	}
+	return "max"
+}
+
+// withBase inclui o ponto da data base (0%) no início da série
+func withBase(series []*float64) []*float64 {
+	zero := 0.0
+	return append([]*float64{&zero}, series...)
+}
+
+func percent(v float64) *float64 {
+	return nullable(round4(v * 100))
+}
+
+func percentPtr(v *float64) *float64 {
+	if v == nil {
+		return nil
+	}
+	return percent(*v)
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func round4(v float64) float64 {
+	return math.Round(v*1e4) / 1e4
+}
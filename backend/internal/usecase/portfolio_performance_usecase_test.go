@@ -0,0 +1,24 @@
+package usecase
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseBenchmarks(t *testing.T) {
+	uc := &PortfolioPerformanceUseCase{index: "^BVSP"}
+
+	specs, err := uc.parseBenchmarks([]string{"CDI", "ipca+6,5", "ibov", "cdi"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(specs) != 3 || specs[1].Spread != 6.5 || specs[2].Symbol != "^BVSP" {
+		t.Errorf("benchmarks = %+v", specs)
+	}
+
+	for _, name := range []string{"ipca+NaN", "ipca+inf", "ipca+-1", "ipca6", "ipca+101"} {
+		if _, err := uc.parseBenchmarks([]string{name}); !errors.Is(err, ErrInvalidParameter) {
+			t.Errorf("%s: erro = %v, esperado parâmetro inválido", name, err)
+		}
+	}
+}
@@ -0,0 +1,482 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"cotacoes/internal/analytics/market"
+	"cotacoes/internal/analytics/portfolio"
+	"cotacoes/internal/domain"
+)
+
+// Limites das carteiras
+const (
+	maxPortfolios            = 20
+	maxPortfolioTransactions = 10000
+	maxPortfolioNameSize     = 80
+)
+
+// ErrIdempotencyConflict indica uma chave de idempotência já usada por
+// outro lançamento
+var ErrIdempotencyConflict = errors.New("idempotency key already used")
+
+// otherType agrupa na alocação os ativos sem tipo conhecido
+const otherType = "other"
+
+// PositionView é uma posição aberta valorizada pela cotação atual. Sem
+// cotação, a posição entra nos totais pelo custo e Error explica o motivo.
+type PositionView struct {
+	Symbol        string   `json:"symbol"`
+	Name          string   `json:"name"`
+	Sector        string   `json:"sector"`
+	Type          string   `json:"type"`
+	Logo          string   `json:"logo,omitempty"`
+	Quantity      float64  `json:"quantity"`
+	AveragePrice  float64  `json:"averagePrice"`
+	TotalCost     float64  `json:"totalCost"`
+	Price         *float64 `json:"price"`
+	Change        *float64 `json:"change"`
+	MarketValue   *float64 `json:"marketValue"`
+	UnrealizedPnL *float64 `json:"unrealizedPnL"`
+	UnrealizedPct *float64 `json:"unrealizedPct"`
+	RealizedPnL   float64  `json:"realizedPnL"`
+	Dividends     float64  `json:"dividends"`
+	Weight        float64  `json:"weight"`
+	FirstDate     string   `json:"firstDate"`
+	Error         string   `json:"error,omitempty"`
+}
+
+// ClosedPosition é um ativo sem posição, mas com resultado ou proventos
+type ClosedPosition struct {
+	Symbol      string  `json:"symbol"`
+	RealizedPnL float64 `json:"realizedPnL"`
+	Dividends   float64 `json:"dividends"`
+	FirstDate   string  `json:"firstDate"`
+	LastDate    string  `json:"lastDate"`
+}
+
+// AllocationSlice é a fatia da carteira em um setor ou tipo de ativo
+type AllocationSlice struct {
+	Name        string  `json:"name"`
+	Count       int     `json:"count"`
+	MarketValue float64 `json:"marketValue"`
+	Weight      float64 `json:"weight"`
+}
+
+type PortfolioTotals struct {
+	Cost          float64  `json:"cost"`
+	MarketValue   float64  `json:"marketValue"`
+	UnrealizedPnL float64  `json:"unrealizedPnL"`
+	UnrealizedPct *float64 `json:"unrealizedPct"`
+	RealizedPnL   float64  `json:"realizedPnL"`
+	Dividends     float64  `json:"dividends"`
+	TotalPnL      float64  `json:"totalPnL"`
+}
+
+// PortfolioSummary representa a resposta de /portfolios/:id
+type PortfolioSummary struct {
+	domain.Portfolio
+	QuotesUpdatedAt time.Time        `json:"quotesUpdatedAt"`
+	Degraded        bool             `json:"degraded,omitempty"`
+	Totals          PortfolioTotals  `json:"totals"`
+	Positions       []PositionView   `json:"positions"`
+	Closed          []ClosedPosition `json:"closed"`
+	Allocation      struct {
+		BySector []AllocationSlice `json:"bySector"`
+		ByType   []AllocationSlice `json:"byType"`
+	} `json:"allocation"`
+}
+
+// RealizedResult representa a resposta de /portfolios/:id/realized
+type RealizedResult struct {
+	ID          string             `json:"id"`
+	RealizedPnL float64            `json:"realizedPnL"`
+	Dividends   float64            `json:"dividends"`
+	Sales       []portfolio.Sale   `json:"sales"`
+	Incomes     []portfolio.Income `json:"incomes"`
+}
+
+type PortfolioUseCase struct {
+	portfolios domain.PortfolioRepository
+	universe   *MarketUniverse
+	getStockUC *GetStockUseCase
+}
+
+func NewPortfolioUseCase(
+	portfolios domain.PortfolioRepository,
+	universe *MarketUniverse,
+	getStockUC *GetStockUseCase,
+) *PortfolioUseCase {
+	return &PortfolioUseCase{
+		portfolios: portfolios,
+		universe:   universe,
+		getStockUC: getStockUC,
+	}
+}
+
+// List retorna as carteiras do usuário
+func (uc *PortfolioUseCase) List(owner string) ([]domain.Portfolio, error) {
+	return uc.portfolios.List(owner)
+}
+
+// Create cria uma carteira vazia
+func (uc *PortfolioUseCase) Create(owner, name, description string) (*domain.Portfolio, error) {
+	name, err := portfolioName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.portfolios.List(owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxPortfolios {
+		return nil, fmt.Errorf("%w: máximo de %d carteiras", ErrInvalidParameter, maxPortfolios)
+	}
+
+	now := time.Now()
+	p := domain.Portfolio{
+		ID:           newID(),
+		Owner:        owner,
+		Name:         name,
+		Description:  strings.TrimSpace(description),
+		Transactions: []domain.Transaction{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := uc.portfolios.Save(p); err != nil {
+		return nil, err
+	}
+	log.Printf("💼 Carteira %s criada", p.ID)
+	return &p, nil
+}
+
+// Update altera nome e descrição da carteira
+func (uc *PortfolioUseCase) Update(owner, id, name, description string) (*domain.Portfolio, error) {
+	name, err := portfolioName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.portfolios.Update(owner, id, func(p *domain.Portfolio) error {
+		p.Name = name
+		p.Description = strings.TrimSpace(description)
+		p.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+// Delete remove a carteira e seus lançamentos
+func (uc *PortfolioUseCase) Delete(owner, id string) error {
+	return uc.portfolios.Delete(owner, id)
+}
+
+// Transactions lista os lançamentos em ordem cronológica, opcionalmente
+// filtrados por ativo
+func (uc *PortfolioUseCase) Transactions(owner, id, symbol string) ([]domain.Transaction, error) {
+	p, err := uc.portfolios.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+
+	symbol = portfolio.NormalizeSymbol(symbol)
+	list := make([]domain.Transaction, 0, len(p.Transactions))
+	for _, tx := range p.Transactions {
+		if symbol == "" || portfolio.NormalizeSymbol(tx.Symbol) == symbol {
+			list = append(list, tx)
+		}
+	}
+	sortTransactions(list)
+	return list, nil
+}
+
+// AddTransaction registra um lançamento. Com chave de idempotência já
+// usada pelo mesmo lançamento, devolve o existente e created=false.
+func (uc *PortfolioUseCase) AddTransaction(owner, id string, tx domain.Transaction) (*domain.Transaction, bool, error) {
+	if err := portfolio.Normalize(&tx); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+	tx.IdempotencyKey = strings.TrimSpace(tx.IdempotencyKey)
+
+	// A checagem da chave e a gravação acontecem sob o mesmo lock, para
+	// que requisições repetidas em paralelo não dupliquem o lançamento
+	var result domain.Transaction
+	created := false
+	_, err := uc.portfolios.Update(owner, id, func(p *domain.Portfolio) error {
+		if tx.IdempotencyKey != "" {
+			for _, existing := range p.Transactions {
+				if existing.IdempotencyKey != tx.IdempotencyKey {
+					continue
+				}
+				if !sameTransaction(existing, tx) {
+					return fmt.Errorf("%w: %s", ErrIdempotencyConflict, tx.IdempotencyKey)
+				}
+				result = existing
+				return nil
+			}
+		}
+
+		if len(p.Transactions) >= maxPortfolioTransactions {
+			return fmt.Errorf("%w: máximo de %d lançamentos por carteira", ErrInvalidParameter, maxPortfolioTransactions)
+		}
+
+		now := time.Now()
+		tx.ID = newID()
+		tx.CreatedAt = now
+
+		// O lançamento precisa manter o histórico consistente (ex: vendas
+		// sem posição)
+		transactions := append(p.Transactions[:len(p.Transactions):len(p.Transactions)], tx)
+		if _, err := portfolio.Replay(transactions); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+
+		p.Transactions = transactions
+		p.UpdatedAt = now
+		result, created = tx, true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	return &result, created, nil
+}
+
+// DeleteTransaction remove um lançamento, desde que o histórico continue
+// consistente sem ele
+func (uc *PortfolioUseCase) DeleteTransaction(owner, id, txID string) error {
+	_, err := uc.portfolios.Update(owner, id, func(p *domain.Portfolio) error {
+		transactions := make([]domain.Transaction, 0, len(p.Transactions))
+		for _, tx := range p.Transactions {
+			if tx.ID != txID {
+				transactions = append(transactions, tx)
+			}
+		}
+		if len(transactions) == len(p.Transactions) {
+			return domain.ErrTransactionNotFound
+		}
+		if _, err := portfolio.Replay(transactions); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+
+		p.Transactions = transactions
+		p.UpdatedAt = time.Now()
+		return nil
+	})
+	return err
+}
+
+// Realized retorna as vendas com resultado apurado e os proventos
+func (uc *PortfolioUseCase) Realized(owner, id string) (*RealizedResult, error) {
+	p, err := uc.portfolios.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := portfolio.Replay(p.Transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RealizedResult{
+		ID:      p.ID,
+		Sales:   ledger.Sales,
+		Incomes: ledger.Incomes,
+	}
+	if result.Sales == nil {
+		result.Sales = []portfolio.Sale{}
+	}
+	if result.Incomes == nil {
+		result.Incomes = []portfolio.Income{}
+	}
+	for _, sale := range ledger.Sales {
+		result.RealizedPnL += sale.Result
+	}
+	for _, income := range ledger.Incomes {
+		result.Dividends += income.Amount
+	}
+	return result, nil
+}
+
+// Summary calcula as posições valorizadas pela cotação atual, os
+// resultados e a alocação por setor e tipo de ativo
+func (uc *PortfolioUseCase) Summary(owner, id string) (*PortfolioSummary, error) {
+	p, err := uc.portfolios.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := portfolio.Replay(p.Transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	var open []string
+	for _, pos := range ledger.Positions {
+		if pos.Quantity > 0 {
+			open = append(open, pos.Symbol)
+		}
+	}
+	set := loadQuotes(uc.universe, uc.getStockUC, open)
+
+	summary := &PortfolioSummary{
+		Portfolio:       *p,
+		QuotesUpdatedAt: set.UpdatedAt,
+		Degraded:        set.Degraded,
+		Positions:       []PositionView{},
+		Closed:          []ClosedPosition{},
+	}
+	totals := &summary.Totals
+
+	for _, pos := range ledger.Positions {
+		totals.RealizedPnL += pos.RealizedPnL
+		totals.Dividends += pos.Dividends
+
+		if pos.Quantity == 0 {
+			summary.Closed = append(summary.Closed, ClosedPosition{
+				Symbol:      pos.Symbol,
+				RealizedPnL: pos.RealizedPnL,
+				Dividends:   pos.Dividends,
+				FirstDate:   pos.FirstDate,
+				LastDate:    pos.LastDate,
+			})
+			continue
+		}
+
+		q := set.Quotes[pos.Symbol]
+		view := PositionView{
+			Symbol:       pos.Symbol,
+			Name:         q.Name,
+			Sector:       market.SectorOf(domain.StockListItem{Sector: q.Sector}),
+			Type:         q.Type,
+			Logo:         q.Logo,
+			Quantity:     pos.Quantity,
+			AveragePrice: pos.AveragePrice,
+			TotalCost:    pos.TotalCost,
+			Price:        q.Price,
+			Change:       q.Change,
+			RealizedPnL:  pos.RealizedPnL,
+			Dividends:    pos.Dividends,
+			FirstDate:    pos.FirstDate,
+		}
+		if view.Name == "" {
+			view.Name = pos.Symbol
+		}
+		if view.Type == "" {
+			view.Type = otherType
+		}
+
+		value := pos.TotalCost
+		switch {
+		case q.Err != nil:
+			view.Error = q.Err.Error()
+		case q.Price == nil:
+			view.Error = "cotação indisponível"
+		default:
+			value = pos.Quantity * *q.Price
+			pnl := value - pos.TotalCost
+			view.MarketValue = nullable(value)
+			view.UnrealizedPnL = nullable(pnl)
+			if pos.TotalCost > 0 {
+				view.UnrealizedPct = nullable(pnl / pos.TotalCost * 100)
+			}
+		}
+
+		totals.Cost += pos.TotalCost
+		totals.MarketValue += value
+		summary.Positions = append(summary.Positions, view)
+	}
+
+	totals.UnrealizedPnL = totals.MarketValue - totals.Cost
+	if totals.Cost > 0 {
+		totals.UnrealizedPct = nullable(totals.UnrealizedPnL / totals.Cost * 100)
+	}
+	totals.TotalPnL = totals.UnrealizedPnL + totals.RealizedPnL + totals.Dividends
+
+	bySector := map[string]*AllocationSlice{}
+	byType := map[string]*AllocationSlice{}
+	for i := range summary.Positions {
+		view := &summary.Positions[i]
+		value := view.TotalCost
+		if view.MarketValue != nil {
+			value = *view.MarketValue
+		}
+		if totals.MarketValue > 0 {
+			view.Weight = value / totals.MarketValue * 100
+		}
+		addAllocation(bySector, view.Sector, value)
+		addAllocation(byType, view.Type, value)
+	}
+	summary.Allocation.BySector = allocationSlices(bySector, totals.MarketValue)
+	summary.Allocation.ByType = allocationSlices(byType, totals.MarketValue)
+
+	sort.SliceStable(summary.Positions, func(i, j int) bool {
+		return summary.Positions[i].Weight > summary.Positions[j].Weight
+	})
+
+	return summary, nil
+}
+
+func addAllocation(slices map[string]*AllocationSlice, name string, value float64) {
+	s, ok := slices[name]
+	if !ok {
+		s = &AllocationSlice{Name: name}
+		slices[name] = s
+	}
+	s.Count++
+	s.MarketValue += value
+}
+
+// allocationSlices ordena as fatias pelo valor, da maior para a menor
+func allocationSlices(slices map[string]*AllocationSlice, total float64) []AllocationSlice {
+	out := make([]AllocationSlice, 0, len(slices))
+	for _, s := range slices {
+		if total > 0 {
+			s.Weight = s.MarketValue / total * 100
+		}
+		out = append(out, *s)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].MarketValue != out[j].MarketValue {
+			return out[i].MarketValue > out[j].MarketValue
+		}
+		return out[i].Name < out[j].Name
+	})
+	return out
+}
+
+// sameTransaction compara o conteúdo de dois lançamentos (ignora id,
+// data de criação e nota)
+func sameTransaction(a, b domain.Transaction) bool {
+	return a.Type == b.Type &&
+		a.Symbol == b.Symbol &&
+		a.Date == b.Date &&
+		a.Quantity == b.Quantity &&
+		a.Price == b.Price &&
+		a.Fees == b.Fees &&
+		a.Ratio == b.Ratio &&
+		a.Amount == b.Amount &&
+		a.AssetClass == b.AssetClass
+}
+
+func sortTransactions(txs []domain.Transaction) {
+	sort.SliceStable(txs, func(i, j int) bool {
+		if txs[i].Date != txs[j].Date {
+			return txs[i].Date < txs[j].Date
+		}
+		return txs[i].CreatedAt.Before(txs[j].CreatedAt)
+	})
+}
+
+func portfolioName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return "", fmt.Errorf("%w: informe name", ErrInvalidParameter)
+	case len([]rune(name)) > maxPortfolioNameSize:
+		return "", fmt.Errorf("%w: name com mais de %d caracteres", ErrInvalidParameter, maxPortfolioNameSize)
+	}
+	return name, nil
+}
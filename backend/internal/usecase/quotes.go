@@ -0,0 +1,105 @@
+package usecase
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// assetQuote é a cotação atual de um ativo com seus dados de cadastro.
+// Err é preenchido quando a cotação não pôde ser obtida.
+type assetQuote struct {
+	Name      string
+	Sector    string
+	Type      string
+	Logo      string
+	Price     *float64
+	Change    *float64
+	Volume    int64
+	MarketCap int64
+	Err       error
+}
+
+// quoteSet reúne as cotações de um conjunto de ativos
+type quoteSet struct {
+	Quotes    map[string]assetQuote
+	UpdatedAt time.Time
+	Degraded  bool
+}
+
+// loadQuotes busca as cotações atuais dos ativos. Os preços vêm do
+// universo em cache; ativos fora dele (ex: índices) são buscados
+// individualmente, em paralelo.
+func loadQuotes(universe *MarketUniverse, getStockUC *GetStockUseCase, symbols []string) quoteSet {
+	set := quoteSet{Quotes: make(map[string]assetQuote, len(symbols))}
+
+	bySymbol := map[string]domain.StockListItem{}
+	snapshot, err := universe.Load()
+	if err != nil {
+		log.Printf("⚠️ Universo indisponível para cotações: %v", err)
+	} else {
+		set.UpdatedAt = snapshot.UpdatedAt
+		set.Degraded = snapshot.Degraded
+		for _, item := range snapshot.Stocks {
+			bySymbol[item.Stock] = item
+		}
+	}
+
+	var (
+		wg  sync.WaitGroup
+		mu  sync.Mutex
+		sem = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, symbol := range symbols {
+		if listed, ok := bySymbol[symbol]; ok {
+			// As goroutines já iniciadas escrevem no mesmo mapa
+			mu.Lock()
+			set.Quotes[symbol] = assetQuote{
+				Name:      listed.Name,
+				Sector:    listed.Sector,
+				Type:      listed.Type,
+				Logo:      listed.Logo,
+				Price:     present(listed.Close),
+				Change:    nullable(listed.Change),
+				Volume:    listed.Volume,
+				MarketCap: listed.MarketCap,
+			}
+			mu.Unlock()
+			continue
+		}
+
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var quote assetQuote
+			stock, err := getStockUC.Execute(symbol, "1d", "1d")
+			if err != nil {
+				quote.Err = err
+			} else {
+				quote = assetQuote{
+					Name:      stock.ShortName,
+					Logo:      stock.LogoURL,
+					Price:     present(stock.RegularMarketPrice),
+					Change:    nullable(stock.RegularMarketChangePercent),
+					Volume:    stock.RegularMarketVolume,
+					MarketCap: stock.MarketCap,
+				}
+			}
+
+			mu.Lock()
+			set.Quotes[symbol] = quote
+			if stock != nil && stock.Degraded {
+				set.Degraded = true
+			}
+			mu.Unlock()
+		}(symbol)
+	}
+	wg.Wait()
+
+	return set
+}
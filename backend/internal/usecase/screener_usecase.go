@@ -0,0 +1,408 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"cotacoes/internal/calendar"
+	"cotacoes/internal/domain"
+	"cotacoes/internal/screener"
+)
+
+const (
+	defaultScreenerPerPage = 50
+	maxScreenerPerPage     = 200
+
+	// Tamanho máximo da expressão do screener
+	maxExpressionLength = 2000
+)
+
+var validScreenName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ScreenerRequest é o corpo do POST /screener. Com Screen, roda um screen
+// salvo; Sort/Order informados sobrescrevem os do screen.
+type ScreenerRequest struct {
+	Expression string `json:"expression"`
+	Screen     string `json:"screen"`
+	Sort       string `json:"sort"`
+	Order      string `json:"order"`
+	Page       int    `json:"page"`
+	PerPage    int    `json:"perPage"`
+}
+
+// ScreenerItem é uma ação aprovada pelo filtro, com os campos usados na
+// expressão e na ordenação
+type ScreenerItem struct {
+	Symbol string         `json:"symbol"`
+	Name   string         `json:"name"`
+	Sector string         `json:"sector"`
+	Type   string         `json:"type"`
+	Logo   string         `json:"logo"`
+	Values map[string]any `json:"values"`
+}
+
+// ScreenerResult representa a resposta do POST /screener
+type ScreenerResult struct {
+	Expression string            `json:"expression"`
+	Sort       string            `json:"sort"`
+	Order      string            `json:"order"`
+	Universe   int               `json:"universe"`
+	Detailed   int               `json:"detailed,omitempty"`
+	UpdatedAt  time.Time         `json:"updatedAt"`
+	Results    []ScreenerItem    `json:"results"`
+	Pagination domain.Pagination `json:"pagination"`
+}
+
+// screenerRow junta o item da listagem aos valores dos campos
+type screenerRow struct {
+	item domain.StockListItem
+	row  screener.Row
+}
+
+// ScreenerUseCase avalia expressões sobre o universo de ações. Os campos
+// de detalhe (uma chamada por ação, só para as "detailUniverse" mais
+// negociadas) ficam em memória por "ttl".
+type ScreenerUseCase struct {
+	universe       *MarketUniverse
+	getStockUC     *GetStockUseCase
+	screens        domain.ScreenRepository
+	detailUniverse int
+	ttl            time.Duration
+
+	mu         sync.Mutex
+	details    map[string]*domain.Stock
+	detailsAt  time.Time
+	refreshing chan struct{} // fechado ao fim da renovação em andamento
+}
+
+func NewScreenerUseCase(
+	universe *MarketUniverse,
+	getStockUC *GetStockUseCase,
+	screens domain.ScreenRepository,
+	detailUniverse int,
+	ttl time.Duration,
+) *ScreenerUseCase {
+	return &ScreenerUseCase{
+		universe:       universe,
+		getStockUC:     getStockUC,
+		screens:        screens,
+		detailUniverse: detailUniverse,
+		ttl:            ttl,
+	}
+}
+
+// Fields retorna o catálogo de campos das expressões
+func (uc *ScreenerUseCase) Fields() []screener.Field {
+	return screener.Fields
+}
+
+// Execute filtra, ordena e pagina o universo de ações
+func (uc *ScreenerUseCase) Execute(req ScreenerRequest) (*ScreenerResult, error) {
+	if req.Screen != "" {
+		saved, err := uc.screens.Get(strings.ToLower(req.Screen))
+		if err != nil {
+			return nil, err
+		}
+		req.Expression = saved.Expression
+		if req.Sort == "" {
+			req.Sort, req.Order = saved.Sort, saved.Order
+		}
+	}
+
+	expr, sortField, order, err := compileScreen(req.Expression, req.Sort, req.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	page := max(req.Page, 1)
+	perPage := req.PerPage
+	if perPage <= 0 {
+		perPage = defaultScreenerPerPage
+	}
+	if perPage > maxScreenerPerPage {
+		return nil, fmt.Errorf("%w: perPage máximo é %d", ErrInvalidParameter, maxScreenerPerPage)
+	}
+
+	snapshot, err := uc.universe.Load()
+	if err != nil {
+		return nil, err
+	}
+	updatedAt := snapshot.UpdatedAt
+
+	rows := make([]screenerRow, 0, len(snapshot.Stocks))
+	for _, item := range snapshot.Stocks {
+		rows = append(rows, screenerRow{item: item, row: screener.RowFromListItem(item)})
+	}
+
+	detailed := 0
+	if screener.NeedsDetail(append(expr.Fields(), sortField)...) {
+		details := uc.loadDetails(rows)
+		detailed = len(details)
+
+		now := time.Now().In(calendar.Location)
+		for _, r := range rows {
+			if stock, ok := details[r.item.Stock]; ok {
+				r.row.AddDetail(stock, now)
+			}
+		}
+	}
+
+	matched := make([]screenerRow, 0)
+	for _, r := range rows {
+		if expr.Match(r.row.Get) {
+			matched = append(matched, r)
+		}
+	}
+
+	sortRows(matched, sortField, order)
+
+	columns := append([]string{"price", "change", "volume", "market_cap"}, expr.Fields()...)
+	columns = append(columns, sortField)
+
+	total := len(matched)
+	totalPages := max((total+perPage-1)/perPage, 1)
+	start := min((page-1)*perPage, total)
+	end := min(start+perPage, total)
+
+	results := make([]ScreenerItem, 0, end-start)
+	for _, r := range matched[start:end] {
+		values := make(map[string]any, len(columns))
+		for _, col := range columns {
+			values[col] = jsonValue(r.row.Get(col))
+		}
+		results = append(results, ScreenerItem{
+			Symbol: r.item.Stock,
+			Name:   r.item.Name,
+			Sector: r.item.Sector,
+			Type:   r.item.Type,
+			Logo:   r.item.Logo,
+			Values: values,
+		})
+	}
+
+	return &ScreenerResult{
+		Expression: expr.Source,
+		Sort:       sortField,
+		Order:      order,
+		Universe:   len(rows),
+		Detailed:   detailed,
+		UpdatedAt:  updatedAt,
+		Results:    results,
+		Pagination: domain.Pagination{
+			CurrentPage:  page,
+			TotalPages:   totalPages,
+			ItemsPerPage: perPage,
+			TotalCount:   total,
+			HasNextPage:  page < totalPages,
+		},
+	}, nil
+}
+
+// ListScreens retorna os screens salvos
+func (uc *ScreenerUseCase) ListScreens() ([]domain.SavedScreen, error) {
+	return uc.screens.List()
+}
+
+// GetScreen retorna um screen salvo
+func (uc *ScreenerUseCase) GetScreen(name string) (*domain.SavedScreen, error) {
+	return uc.screens.Get(strings.ToLower(name))
+}
+
+// SaveScreen valida e salva (cria ou substitui) um screen com nome
+func (uc *ScreenerUseCase) SaveScreen(name string, screen domain.SavedScreen) (*domain.SavedScreen, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !validScreenName.MatchString(name) {
+		return nil, fmt.Errorf("%w: nome de screen inválido %q (use letras minúsculas, números, - e _)", ErrInvalidParameter, name)
+	}
+
+	_, sortField, order, err := compileScreen(screen.Expression, screen.Sort, screen.Order)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	screen.Name = name
+	screen.Sort, screen.Order = sortField, order
+	screen.CreatedAt, screen.UpdatedAt = now, now
+
+	existing, err := uc.screens.Get(name)
+	switch {
+	case err == nil:
+		screen.CreatedAt = existing.CreatedAt
+	case !errors.Is(err, domain.ErrScreenNotFound):
+		return nil, err
+	}
+
+	if err := uc.screens.Save(screen); err != nil {
+		return nil, err
+	}
+	log.Printf("💾 Screen %q salvo: %s", name, screen.Expression)
+	return &screen, nil
+}
+
+// DeleteScreen remove um screen salvo
+func (uc *ScreenerUseCase) DeleteScreen(name string) error {
+	return uc.screens.Delete(strings.ToLower(name))
+}
+
+// compileScreen valida a expressão e a ordenação (padrão: market_cap desc)
+func compileScreen(expression, sortField, order string) (*screener.Expr, string, string, error) {
+	if strings.TrimSpace(expression) == "" {
+		return nil, "", "", fmt.Errorf("%w: informe expression", ErrInvalidParameter)
+	}
+	if len(expression) > maxExpressionLength {
+		return nil, "", "", fmt.Errorf("%w: expression com mais de %d caracteres", ErrInvalidParameter, maxExpressionLength)
+	}
+	expr, err := screener.Compile(expression, screener.FieldTypes())
+	if err != nil {
+		return nil, "", "", fmt.Errorf("%w: %w", ErrInvalidParameter, err)
+	}
+
+	sortField = strings.ToLower(sortField)
+	if sortField == "" {
+		sortField = "market_cap"
+	}
+	if _, ok := screener.FieldByName(sortField); !ok {
+		return nil, "", "", fmt.Errorf("%w: campo de ordenação desconhecido %q", ErrInvalidParameter, sortField)
+	}
+
+	order = strings.ToLower(order)
+	switch order {
+	case "":
+		order = "desc"
+	case "asc", "desc":
+	default:
+		return nil, "", "", fmt.Errorf("%w: order deve ser asc ou desc", ErrInvalidParameter)
+	}
+
+	return expr, sortField, order, nil
+}
+
+// sortRows ordena pelo campo; valores ausentes vão para o fim e empates
+// são desfeitos pelo símbolo
+func sortRows(rows []screenerRow, field, order string) {
+	sort.SliceStable(rows, func(i, j int) bool {
+		a, b := rows[i].row.Get(field), rows[j].row.Get(field)
+		if a.Null != b.Null {
+			return b.Null
+		}
+		if c := compareValues(a, b); c != 0 {
+			if order == "desc" {
+				return c > 0
+			}
+			return c < 0
+		}
+		return rows[i].item.Stock < rows[j].item.Stock
+	})
+}
+
+// compareValues compara dois valores presentes do mesmo campo; textos sem
+// diferenciar maiúsculas (diferenças só de caixa empatam)
+func compareValues(a, b screener.Value) int {
+	if a.Null || b.Null {
+		return 0
+	}
+	if a.Str != "" || b.Str != "" {
+		return strings.Compare(strings.ToLower(a.Str), strings.ToLower(b.Str))
+	}
+	switch {
+	case a.Num < b.Num:
+		return -1
+	case a.Num > b.Num:
+		return 1
+	}
+	return 0
+}
+
+func jsonValue(v screener.Value) any {
+	switch {
+	case v.Null:
+		return nil
+	case v.Str != "":
+		return v.Str
+	}
+	return math.Round(v.Num*1e4) / 1e4
+}
+
+// loadDetails busca o detalhe das ações mais negociadas (cache por "ttl").
+// Ações cujo detalhe falhar ficam sem os campos de detalhe (Null). A
+// renovação roda fora do lock: enquanto isso, as requisições usam os
+// detalhes anteriores (sem eles, aguardam a renovação em andamento).
+func (uc *ScreenerUseCase) loadDetails(rows []screenerRow) map[string]*domain.Stock {
+	uc.mu.Lock()
+	if uc.details != nil && time.Since(uc.detailsAt) < uc.ttl {
+		defer uc.mu.Unlock()
+		return uc.details
+	}
+	if wait := uc.refreshing; wait != nil {
+		if uc.details == nil {
+			uc.mu.Unlock()
+			<-wait
+			uc.mu.Lock()
+		}
+		defer uc.mu.Unlock()
+		return uc.details
+	}
+	done := make(chan struct{})
+	uc.refreshing = done
+	uc.mu.Unlock()
+
+	details, requested := uc.fetchDetails(rows)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.details = details
+	uc.detailsAt = time.Now()
+	uc.refreshing = nil
+	close(done)
+	log.Printf("🔬 Detalhes do screener atualizados: %d de %d ações", len(details), requested)
+	return uc.details
+}
+
+// fetchDetails busca em paralelo o detalhe das "detailUniverse" ações de
+// maior volume
+func (uc *ScreenerUseCase) fetchDetails(rows []screenerRow) (map[string]*domain.Stock, int) {
+	top := make([]domain.StockListItem, 0, len(rows))
+	for _, r := range rows {
+		top = append(top, r.item)
+	}
+	sort.SliceStable(top, func(i, j int) bool { return top[i].Volume > top[j].Volume })
+	if len(top) > uc.detailUniverse {
+		top = top[:uc.detailUniverse]
+	}
+
+	var (
+		wg      sync.WaitGroup
+		mu      sync.Mutex
+		details = make(map[string]*domain.Stock, len(top))
+		sem     = make(chan struct{}, maxConcurrentFetches)
+	)
+	for _, item := range top {
+		wg.Add(1)
+		go func(symbol string) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			stock, err := uc.getStockUC.Execute(symbol, "1d", "1d")
+			if err != nil {
+				log.Printf("⚠️ Detalhe de %s indisponível para o screener: %v", symbol, err)
+				return
+			}
+
+			mu.Lock()
+			details[symbol] = stock
+			mu.Unlock()
+		}(item.Stock)
+	}
+	wg.Wait()
+
+	return details, len(top)
+}
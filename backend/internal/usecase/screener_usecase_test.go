@@ -0,0 +1,51 @@
+package usecase
+
+import (
+	"strings"
+	"testing"
+
+	"cotacoes/internal/domain"
+	"cotacoes/internal/screener"
+)
+
+func TestSortRows(t *testing.T) {
+	rows := func() []screenerRow {
+		var out []screenerRow
+		for _, r := range []struct {
+			symbol string
+			sector screener.Value
+		}{
+			{"BBAS3", screener.String("finance")},
+			{"ITUB4", screener.String("Finance")},
+			{"PETR4", screener.String("Energy")},
+			{"ABCD3", screener.Null},
+			{"AAAA3", screener.String("FINANCE")},
+		} {
+			out = append(out, screenerRow{
+				item: domain.StockListItem{Stock: r.symbol},
+				row:  screener.Row{"sector": r.sector},
+			})
+		}
+		return out
+	}
+
+	symbols := func(rows []screenerRow) string {
+		var s []string
+		for _, r := range rows {
+			s = append(s, r.item.Stock)
+		}
+		return strings.Join(s, ",")
+	}
+
+	// Diferenças só de caixa empatam e são desfeitas pelo símbolo; ausentes no fim
+	asc := rows()
+	sortRows(asc, "sector", "asc")
+	if got := symbols(asc); got != "PETR4,AAAA3,BBAS3,ITUB4,ABCD3" {
+		t.Errorf("asc = %s", got)
+	}
+	desc := rows()
+	sortRows(desc, "sector", "desc")
+	if got := symbols(desc); got != "AAAA3,BBAS3,ITUB4,PETR4,ABCD3" {
+		t.Errorf("desc = %s", got)
+	}
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cotacoes/internal/analytics/market"
+	"cotacoes/internal/domain"
+)
+
+// Tamanho das listas de maiores altas/baixas por setor
+const (
+	defaultSectorTop = 5
+	maxSectorTop     = 50
+)
+
+// SectorSummaryResult representa a resposta da rota /sectors/summary
+type SectorSummaryResult struct {
+	UpdatedAt time.Time              `json:"updatedAt"`
+	Degraded  bool                   `json:"degraded,omitempty"`
+	Type      string                 `json:"type,omitempty"`
+	Sectors   []market.SectorSummary `json:"sectors"`
+}
+
+// SectorDetailResult representa a resposta da rota /sectors/:name/summary
+type SectorDetailResult struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+	Degraded  bool      `json:"degraded,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	market.SectorSummary
+}
+
+// SectorSummaryUseCase agrega o universo de ações por setor. Os números
+// são recalculados a partir da listagem em cache, então acompanham cada
+// renovação do universo.
+type SectorSummaryUseCase struct {
+	universe *MarketUniverse
+}
+
+func NewSectorSummaryUseCase(universe *MarketUniverse) *SectorSummaryUseCase {
+	return &SectorSummaryUseCase{universe: universe}
+}
+
+// Execute resume todos os setores; stockType opcional filtra o tipo de ativo
+func (uc *SectorSummaryUseCase) Execute(stockType string, top int) (*SectorSummaryResult, error) {
+	top, err := sectorTop(top)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := uc.universe.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	return &SectorSummaryResult{
+		UpdatedAt: snapshot.UpdatedAt,
+		Degraded:  snapshot.Degraded,
+		Type:      stockType,
+		Sectors:   market.Sectors(filterByType(snapshot.Stocks, stockType), top),
+	}, nil
+}
+
+// ExecuteSector resume um setor (nome sem diferenciar maiúsculas)
+func (uc *SectorSummaryUseCase) ExecuteSector(name, stockType string, top int) (*SectorDetailResult, error) {
+	top, err := sectorTop(top)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := uc.universe.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	name = strings.TrimSpace(name)
+	sector := ""
+	items := make([]domain.StockListItem, 0)
+	for _, item := range filterByType(snapshot.Stocks, stockType) {
+		if s := market.SectorOf(item); strings.EqualFold(s, name) {
+			sector = s
+			items = append(items, item)
+		}
+	}
+	if sector == "" {
+		return nil, fmt.Errorf("%w: %q", domain.ErrSectorNotFound, name)
+	}
+
+	return &SectorDetailResult{
+		UpdatedAt:     snapshot.UpdatedAt,
+		Degraded:      snapshot.Degraded,
+		Type:          stockType,
+		SectorSummary: market.Sector(sector, items, top),
+	}, nil
+}
+
+func sectorTop(top int) (int, error) {
+	switch {
+	case top == 0:
+		return defaultSectorTop, nil
+	case top < 0 || top > maxSectorTop:
+		return 0, fmt.Errorf("%w: top deve estar entre 1 e %d", ErrInvalidParameter, maxSectorTop)
+	}
+	return top, nil
+}
+
+// filterByType mantém os ativos do tipo informado (todos, se vazio)
+func filterByType(items []domain.StockListItem, stockType string) []domain.StockListItem {
+	if stockType == "" {
+		return items
+	}
+	out := make([]domain.StockListItem, 0, len(items))
+	for _, item := range items {
+		if strings.EqualFold(item.Type, stockType) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -2,31 +2,65 @@ package usecase
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 
+	"cotacoes/internal/analytics/adjust"
+	"cotacoes/internal/analytics/dividends"
+	"cotacoes/internal/analytics/resample"
+	"cotacoes/internal/calendar"
 	"cotacoes/internal/domain"
 )
 
 // Erros de domínio
 var (
-	ErrInvalidSymbol = errors.New("invalid stock symbol")
+	ErrInvalidSymbol    = errors.New("invalid stock symbol")
+	ErrInvalidParameter = errors.New("invalid parameter")
 )
 
 type GetStockUseCase struct {
 	StockRepo domain.StockRepository
+	TaxRules  dividends.TaxRules
 }
 
-func NewGetStockUseCase(stockRepo domain.StockRepository) *GetStockUseCase {
+func NewGetStockUseCase(stockRepo domain.StockRepository, taxRules dividends.TaxRules) *GetStockUseCase {
 	return &GetStockUseCase{
 		StockRepo: stockRepo,
+		TaxRules:  taxRules,
 	}
 }
 
+// StockOptions são transformações opcionais aplicadas ao histórico
+type StockOptions struct {
+	// Adjust aplica eventos corporativos: none, splits ou total.
+	// Vazio mantém o histórico como veio do provider.
+	Adjust string
+	// Resample agrega os candles em 1wk, 1mo, 3mo ou 1y
+	Resample string
+}
+
+// adjustingRepository é implementado por repositórios de séries derivadas
+// (índices customizados), que precisam do modo de ajuste para ajustar os
+// ativos antes do cálculo
+type adjustingRepository interface {
+	GetBySymbolAdjusted(symbol, rangeParam, intervalParam string, mode adjust.Mode) (*domain.Stock, error)
+}
+
 // Execute retorna os detalhes de uma ação pelo símbolo
 func (uc *GetStockUseCase) Execute(
 	symbol, rangeParam, intervalParam string,
 ) (*domain.Stock, error) {
 
+	return uc.ExecuteWithOptions(symbol, rangeParam, intervalParam, StockOptions{})
+}
+
+// ExecuteWithOptions retorna os detalhes de uma ação aplicando as opções
+// ao histórico de preços
+func (uc *GetStockUseCase) ExecuteWithOptions(
+	symbol, rangeParam, intervalParam string,
+	opts StockOptions,
+) (*domain.Stock, error) {
+
 	symbol = strings.TrimSpace(symbol)
 	if symbol == "" {
 		return nil, ErrInvalidSymbol
@@ -40,10 +74,51 @@ func (uc *GetStockUseCase) Execute(
 		intervalParam = "1d"
 	}
 
-	stock, err := uc.StockRepo.GetBySymbol(symbol, rangeParam, intervalParam)
+	var mode adjust.Mode
+	if opts.Adjust != "" {
+		m, err := adjust.ParseMode(opts.Adjust)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+		mode = m
+	}
+	if opts.Resample != "" {
+		if err := resample.Validate(opts.Resample); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+	}
+
+	var stock *domain.Stock
+	var err error
+	if repo, ok := uc.StockRepo.(adjustingRepository); ok && mode != "" {
+		stock, err = repo.GetBySymbolAdjusted(symbol, rangeParam, intervalParam, mode)
+	} else {
+		stock, err = uc.StockRepo.GetBySymbol(symbol, rangeParam, intervalParam)
+	}
 	if err != nil {
 		return nil, err
 	}
 
-	return stock, nil
+	// Cópia rasa: o stock pode estar compartilhado com o cache
+	transformed := *stock
+
+	// Valores líquidos de IR (JCP tem retenção, dividendos são isentos)
+	transformed.DividendsData.CashDividends = dividends.ApplyWithholding(stock.DividendsData.CashDividends, uc.TaxRules)
+
+	// O ajuste vem antes da reamostragem para que as barras agregadas já
+	// usem preços ajustados
+	if mode != "" {
+		transformed.HistoricalDataPrice = adjust.Apply(transformed.HistoricalDataPrice, stock.DividendsData, mode)
+		transformed.Adjustment = string(mode)
+	}
+
+	if opts.Resample != "" {
+		transformed.HistoricalDataPrice, err = resample.Resample(transformed.HistoricalDataPrice, opts.Resample, calendar.Default)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+		}
+		transformed.UsedInterval = opts.Resample
+	}
+
+	return &transformed, nil
 }
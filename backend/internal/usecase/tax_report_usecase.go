@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"cotacoes/internal/analytics/irpf"
+	"cotacoes/internal/analytics/portfolio"
+	"cotacoes/internal/domain"
+)
+
+// TaxReportResult representa a resposta de /portfolios/:id/tax
+type TaxReportResult struct {
+	ID   string `json:"id"`
+	Year int    `json:"year"`
+	// Classes é a classe usada para cada ativo vendido no ano
+	Classes map[string]string `json:"classes"`
+	Months  []irpf.Month      `json:"months"`
+	Totals  struct {
+		ExemptGains float64 `json:"exemptGains"`
+		Tax         float64 `json:"tax"`
+		Withheld    float64 `json:"withheld"`
+		Darf        float64 `json:"darf"`
+	} `json:"totals"`
+	// Saldos ao fim do período
+	LossCarryforward map[string]float64 `json:"lossCarryforward"`
+	WithheldCarry    float64            `json:"withheldCarry"`
+	Deferred         float64            `json:"deferred"`
+}
+
+type TaxReportUseCase struct {
+	portfolios domain.PortfolioRepository
+	universe   *MarketUniverse
+	rules      irpf.Rules
+}
+
+func NewTaxReportUseCase(
+	portfolios domain.PortfolioRepository,
+	universe *MarketUniverse,
+	rules irpf.Rules,
+) *TaxReportUseCase {
+	return &TaxReportUseCase{
+		portfolios: portfolios,
+		universe:   universe,
+		rules:      rules,
+	}
+}
+
+// Execute apura o IR mensal da carteira no ano (0 = ano atual). Todo o
+// histórico é reprocessado para que prejuízos de anos anteriores sejam
+// compensados.
+func (uc *TaxReportUseCase) Execute(owner, id string, year int) (*TaxReportResult, error) {
+	if year == 0 {
+		year = time.Now().Year()
+	}
+	if year < 1990 || year > 2100 {
+		return nil, fmt.Errorf("%w: year %d", ErrInvalidParameter, year)
+	}
+
+	p, err := uc.portfolios.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	ledger, err := portfolio.Replay(p.Transactions)
+	if err != nil {
+		return nil, err
+	}
+
+	sold := make([]string, 0, len(ledger.Sales))
+	for _, sale := range ledger.Sales {
+		sold = append(sold, sale.Symbol)
+	}
+	classes, err := uc.classify(p.Transactions, sold)
+	if err != nil {
+		return nil, err
+	}
+
+	ops := make([]irpf.Operation, 0, len(ledger.Sales))
+	for _, sale := range ledger.Sales {
+		ops = append(ops, irpf.Operation{
+			Date:     sale.Date,
+			Symbol:   sale.Symbol,
+			Class:    classes[sale.Symbol],
+			DayTrade: sale.DayTrade,
+			Gross:    sale.Gross,
+			Result:   sale.Result,
+		})
+	}
+
+	report, err := irpf.Compute(ops, uc.rules)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidParameter, err)
+	}
+
+	result := &TaxReportResult{
+		ID:               p.ID,
+		Year:             year,
+		Classes:          make(map[string]string),
+		Months:           []irpf.Month{},
+		LossCarryforward: map[string]float64{},
+	}
+	prefix := fmt.Sprintf("%04d-", year)
+	for _, m := range report.Months {
+		if m.Month > fmt.Sprintf("%04d-12", year) {
+			break
+		}
+		// Saldos de meses anteriores ao ano também valem como ponto de partida
+		result.LossCarryforward = m.LossCarryforward
+		result.Deferred = m.Deferred
+		if !strings.HasPrefix(m.Month, prefix) {
+			continue
+		}
+		result.WithheldCarry = m.WithheldCarry
+		result.Months = append(result.Months, m)
+		result.Totals.ExemptGains += m.ExemptGains
+		result.Totals.Tax += m.Tax
+		result.Totals.Withheld += m.Withheld
+		result.Totals.Darf += m.DarfAmount
+	}
+	for _, total := range []*float64{
+		&result.Totals.ExemptGains, &result.Totals.Tax, &result.Totals.Withheld, &result.Totals.Darf,
+	} {
+		*total = math.Round(*total*100) / 100
+	}
+	for _, sale := range ledger.Sales {
+		if strings.HasPrefix(sale.Date, prefix) {
+			result.Classes[sale.Symbol] = classes[sale.Symbol]
+		}
+	}
+
+	return result, nil
+}
+
+// classify define a classe de cada ativo vendido: assetClass informada
+// nos lançamentos (a mais recente), senão o tipo do cadastro. A classe
+// nunca é deduzida do código ou do nome, pois ela decide a isenção de
+// R$ 20 mil: fundos listados (FII ou ETF) e ativos fora do cadastro
+// exigem assetClass e geram erro listando os ativos.
+func (uc *TaxReportUseCase) classify(txs []domain.Transaction, sold []string) (map[string]string, error) {
+	classes := make(map[string]string)
+	sorted := make([]domain.Transaction, len(txs))
+	copy(sorted, txs)
+	sortTransactions(sorted)
+	for _, tx := range sorted {
+		if tx.AssetClass != "" {
+			classes[portfolio.NormalizeSymbol(tx.Symbol)] = tx.AssetClass
+		}
+	}
+
+	var pending []string
+	seen := map[string]bool{}
+	for _, symbol := range sold {
+		if _, ok := classes[symbol]; !ok && !seen[symbol] {
+			seen[symbol] = true
+			pending = append(pending, symbol)
+		}
+	}
+	if len(pending) == 0 {
+		return classes, nil
+	}
+
+	snapshot, err := uc.universe.Load()
+	if err != nil {
+		log.Printf("⚠️ Universo indisponível para classificar ativos: %v", err)
+		return nil, fmt.Errorf("classificação dos ativos indisponível: %w", err)
+	}
+	listed := make(map[string]domain.StockListItem, len(snapshot.Stocks))
+	for _, item := range snapshot.Stocks {
+		listed[item.Stock] = item
+	}
+
+	var unknown []string
+	for _, symbol := range pending {
+		class := listedClass(listed[symbol])
+		if class == "" {
+			unknown = append(unknown, symbol)
+			continue
+		}
+		classes[symbol] = class
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("%w: classe desconhecida para %s; informe assetClass (%s, %s, %s ou %s) nos lançamentos",
+			ErrInvalidParameter, strings.Join(unknown, ", "),
+			domain.AssetStock, domain.AssetFII, domain.AssetETF, domain.AssetBDR)
+	}
+	return classes, nil
+}
+
+// listedClass devolve a classe pelo tipo do cadastro, ou vazio quando o
+// tipo não a determina (fundos podem ser FII ou ETF)
+func listedClass(item domain.StockListItem) string {
+	switch strings.ToLower(item.Type) {
+	case "stock":
+		return domain.AssetStock
+	case "bdr":
+		return domain.AssetBDR
+	}
+	return ""
+}
@@ -0,0 +1,326 @@
+package usecase
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"cotacoes/internal/domain"
+)
+
+// Limites das watchlists
+const (
+	maxWatchlists        = 50
+	maxWatchlistItems    = 100
+	maxWatchlistNameSize = 80
+	maxWatchlistNoteSize = 500
+)
+
+// WatchlistItemInput é o corpo de inclusão/edição de um item. Position
+// (a partir de 1) é opcional; sem ela o item vai para o fim da lista.
+type WatchlistItemInput struct {
+	Symbol   string  `json:"symbol"`
+	Note     *string `json:"note"`
+	Position int     `json:"position"`
+}
+
+// WatchlistQuote é um item da watchlist com a cotação atual
+type WatchlistQuote struct {
+	Position  int       `json:"position"`
+	Symbol    string    `json:"symbol"`
+	Note      string    `json:"note,omitempty"`
+	Name      string    `json:"name"`
+	Sector    string    `json:"sector,omitempty"`
+	Type      string    `json:"type,omitempty"`
+	Logo      string    `json:"logo,omitempty"`
+	Price     *float64  `json:"price"`
+	Change    *float64  `json:"change"`
+	Volume    int64     `json:"volume"`
+	MarketCap int64     `json:"marketCap"`
+	Error     string    `json:"error,omitempty"`
+	AddedAt   time.Time `json:"addedAt"`
+}
+
+// WatchlistQuotesResult representa a resposta de /watchlists/:id/quotes
+type WatchlistQuotesResult struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	UpdatedAt time.Time        `json:"updatedAt"`
+	Degraded  bool             `json:"degraded,omitempty"`
+	Quotes    []WatchlistQuote `json:"quotes"`
+}
+
+type WatchlistUseCase struct {
+	watchlists domain.WatchlistRepository
+	universe   *MarketUniverse
+	getStockUC *GetStockUseCase
+}
+
+func NewWatchlistUseCase(
+	watchlists domain.WatchlistRepository,
+	universe *MarketUniverse,
+	getStockUC *GetStockUseCase,
+) *WatchlistUseCase {
+	return &WatchlistUseCase{
+		watchlists: watchlists,
+		universe:   universe,
+		getStockUC: getStockUC,
+	}
+}
+
+// List retorna as watchlists do usuário
+func (uc *WatchlistUseCase) List(owner string) ([]domain.Watchlist, error) {
+	return uc.watchlists.List(owner)
+}
+
+// Get retorna uma watchlist
+func (uc *WatchlistUseCase) Get(owner, id string) (*domain.Watchlist, error) {
+	return uc.watchlists.Get(owner, id)
+}
+
+// Create cria uma watchlist, opcionalmente já com itens
+func (uc *WatchlistUseCase) Create(owner, name string, items []WatchlistItemInput) (*domain.Watchlist, error) {
+	name, err := watchlistName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := uc.watchlists.List(owner)
+	if err != nil {
+		return nil, err
+	}
+	if len(existing) >= maxWatchlists {
+		return nil, fmt.Errorf("%w: máximo de %d watchlists", ErrInvalidParameter, maxWatchlists)
+	}
+
+	now := time.Now()
+	w := domain.Watchlist{
+		ID:        newID(),
+		Owner:     owner,
+		Name:      name,
+		Items:     []domain.WatchlistItem{},
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	for _, in := range items {
+		if err := addItem(&w, in, now); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := uc.watchlists.Save(w); err != nil {
+		return nil, err
+	}
+	log.Printf("⭐ Watchlist %s criada (%d itens)", w.ID, len(w.Items))
+	return &w, nil
+}
+
+// Rename altera o nome da watchlist
+func (uc *WatchlistUseCase) Rename(owner, id, name string) (*domain.Watchlist, error) {
+	name, err := watchlistName(name)
+	if err != nil {
+		return nil, err
+	}
+	return uc.update(owner, id, func(w *domain.Watchlist) error {
+		w.Name = name
+		return nil
+	})
+}
+
+// Delete remove a watchlist
+func (uc *WatchlistUseCase) Delete(owner, id string) error {
+	return uc.watchlists.Delete(owner, id)
+}
+
+// AddItem inclui um ativo na watchlist
+func (uc *WatchlistUseCase) AddItem(owner, id string, in WatchlistItemInput) (*domain.Watchlist, error) {
+	return uc.update(owner, id, func(w *domain.Watchlist) error {
+		return addItem(w, in, time.Now())
+	})
+}
+
+// UpdateItem altera a nota e/ou a posição de um item
+func (uc *WatchlistUseCase) UpdateItem(owner, id, symbol string, in WatchlistItemInput) (*domain.Watchlist, error) {
+	return uc.update(owner, id, func(w *domain.Watchlist) error {
+		i := indexOfItem(w.Items, symbol)
+		if i < 0 {
+			return fmt.Errorf("%w: %s não está na watchlist", ErrInvalidParameter, strings.ToUpper(symbol))
+		}
+
+		item := w.Items[i]
+		if in.Note != nil {
+			note, err := watchlistNote(*in.Note)
+			if err != nil {
+				return err
+			}
+			item.Note = note
+		}
+		w.Items[i] = item
+
+		if in.Position != 0 {
+			return moveItem(w, i, in.Position)
+		}
+		return nil
+	})
+}
+
+// RemoveItem tira um ativo da watchlist
+func (uc *WatchlistUseCase) RemoveItem(owner, id, symbol string) (*domain.Watchlist, error) {
+	return uc.update(owner, id, func(w *domain.Watchlist) error {
+		i := indexOfItem(w.Items, symbol)
+		if i < 0 {
+			return fmt.Errorf("%w: %s não está na watchlist", ErrInvalidParameter, strings.ToUpper(symbol))
+		}
+		w.Items = append(w.Items[:i], w.Items[i+1:]...)
+		return nil
+	})
+}
+
+// Reorder define a nova ordem dos itens; symbols deve conter exatamente
+// os ativos da watchlist
+func (uc *WatchlistUseCase) Reorder(owner, id string, symbols []string) (*domain.Watchlist, error) {
+	return uc.update(owner, id, func(w *domain.Watchlist) error {
+		if len(symbols) != len(w.Items) {
+			return fmt.Errorf("%w: informe todos os %d ativos da watchlist", ErrInvalidParameter, len(w.Items))
+		}
+
+		ordered := make([]domain.WatchlistItem, 0, len(w.Items))
+		seen := make(map[int]bool, len(symbols))
+		for _, symbol := range symbols {
+			i := indexOfItem(w.Items, symbol)
+			if i < 0 || seen[i] {
+				return fmt.Errorf("%w: %s ausente ou repetido", ErrInvalidParameter, strings.ToUpper(symbol))
+			}
+			seen[i] = true
+			ordered = append(ordered, w.Items[i])
+		}
+		w.Items = ordered
+		return nil
+	})
+}
+
+// Quotes retorna os itens com a cotação atual
+func (uc *WatchlistUseCase) Quotes(owner, id string) (*WatchlistQuotesResult, error) {
+	w, err := uc.watchlists.Get(owner, id)
+	if err != nil {
+		return nil, err
+	}
+
+	symbols := make([]string, len(w.Items))
+	for i, item := range w.Items {
+		symbols[i] = item.Symbol
+	}
+	set := loadQuotes(uc.universe, uc.getStockUC, symbols)
+
+	result := &WatchlistQuotesResult{
+		ID:        w.ID,
+		Name:      w.Name,
+		UpdatedAt: set.UpdatedAt,
+		Degraded:  set.Degraded,
+		Quotes:    make([]WatchlistQuote, len(w.Items)),
+	}
+	for i, item := range w.Items {
+		q := set.Quotes[item.Symbol]
+		quote := WatchlistQuote{
+			Position:  i + 1,
+			Symbol:    item.Symbol,
+			Note:      item.Note,
+			Name:      q.Name,
+			Sector:    q.Sector,
+			Type:      q.Type,
+			Logo:      q.Logo,
+			Price:     q.Price,
+			Change:    q.Change,
+			Volume:    q.Volume,
+			MarketCap: q.MarketCap,
+			AddedAt:   item.AddedAt,
+		}
+		if q.Err != nil {
+			quote.Error = q.Err.Error()
+		}
+		result.Quotes[i] = quote
+	}
+
+	return result, nil
+}
+
+// update aplica a alteração e salva a watchlist sob o lock do repositório
+func (uc *WatchlistUseCase) update(owner, id string, change func(*domain.Watchlist) error) (*domain.Watchlist, error) {
+	return uc.watchlists.Update(owner, id, func(w *domain.Watchlist) error {
+		if err := change(w); err != nil {
+			return err
+		}
+		w.UpdatedAt = time.Now()
+		return nil
+	})
+}
+
+func addItem(w *domain.Watchlist, in WatchlistItemInput, now time.Time) error {
+	symbol := strings.ToUpper(strings.TrimSpace(in.Symbol))
+	if symbol == "" {
+		return fmt.Errorf("%w: informe symbol", ErrInvalidParameter)
+	}
+	if indexOfItem(w.Items, symbol) >= 0 {
+		return fmt.Errorf("%w: %s já está na watchlist", ErrInvalidParameter, symbol)
+	}
+	if len(w.Items) >= maxWatchlistItems {
+		return fmt.Errorf("%w: máximo de %d ativos por watchlist", ErrInvalidParameter, maxWatchlistItems)
+	}
+
+	item := domain.WatchlistItem{Symbol: symbol, AddedAt: now}
+	if in.Note != nil {
+		note, err := watchlistNote(*in.Note)
+		if err != nil {
+			return err
+		}
+		item.Note = note
+	}
+
+	w.Items = append(w.Items, item)
+	if in.Position != 0 {
+		return moveItem(w, len(w.Items)-1, in.Position)
+	}
+	return nil
+}
+
+// moveItem move o item do índice "from" para a posição (a partir de 1)
+func moveItem(w *domain.Watchlist, from, position int) error {
+	if position < 1 || position > len(w.Items) {
+		return fmt.Errorf("%w: position deve estar entre 1 e %d", ErrInvalidParameter, len(w.Items))
+	}
+	item := w.Items[from]
+	items := append(w.Items[:from:from], w.Items[from+1:]...)
+	to := position - 1
+	items = append(items[:to], append([]domain.WatchlistItem{item}, items[to:]...)...)
+	w.Items = items
+	return nil
+}
+
+func indexOfItem(items []domain.WatchlistItem, symbol string) int {
+	for i, item := range items {
+		if strings.EqualFold(item.Symbol, strings.TrimSpace(symbol)) {
+			return i
+		}
+	}
+	return -1
+}
+
+func watchlistName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	switch {
+	case name == "":
+		return "", fmt.Errorf("%w: informe name", ErrInvalidParameter)
+	case len([]rune(name)) > maxWatchlistNameSize:
+		return "", fmt.Errorf("%w: name com mais de %d caracteres", ErrInvalidParameter, maxWatchlistNameSize)
+	}
+	return name, nil
+}
+
+func watchlistNote(note string) (string, error) {
+	note = strings.TrimSpace(note)
+	if len([]rune(note)) > maxWatchlistNoteSize {
+		return "", fmt.Errorf("%w: nota com mais de %d caracteres", ErrInvalidParameter, maxWatchlistNoteSize)
+	}
+	return note, nil
+}